@@ -72,3 +72,15 @@ func TestBindFormPointer(t *testing.T) {
 	}
 	runTestBindForm(t, dest, validate)
 }
+
+func TestBindFormUint(t *testing.T) {
+	var dest struct {
+		Epoch uint64 `form:"epoch"`
+	}
+	req, _ := http.NewRequest("GET", "/?epoch=12", nil)
+	assert.NoError(t, Bind(req, &dest))
+	assert.Equal(t, uint64(12), dest.Epoch)
+
+	req, _ = http.NewRequest("GET", "/?epoch=-1", nil)
+	assert.Error(t, Bind(req, &dest))
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+
+	"github.com/bloxapp/ssv/api"
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/runner/performance"
+)
+
+type Duties struct {
+	Tracker *performance.Tracker
+}
+
+func (h *Duties) List(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		Validators api.HexSlice `json:"validator" form:"validator"`
+		Roles      requestRoles `json:"roles" form:"roles"`
+		FromEpoch  uint64       `json:"from_epoch" form:"from_epoch"`
+		ToEpoch    uint64       `json:"to_epoch" form:"to_epoch"`
+	}
+	var response struct {
+		Data []*dutyJSON `json:"data"`
+	}
+
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if request.ToEpoch != 0 && request.ToEpoch < request.FromEpoch {
+		return api.InvalidRequestError(fmt.Errorf("to_epoch must not be lower than from_epoch"))
+	}
+
+	filter := performance.Filter{
+		Roles:     request.Roles,
+		FromEpoch: phase0.Epoch(request.FromEpoch),
+		ToEpoch:   phase0.Epoch(request.ToEpoch),
+	}
+	for _, pubKey := range request.Validators {
+		filter.PubKeys = append(filter.PubKeys, pubKey)
+	}
+
+	duties := h.Tracker.Duties(filter)
+	response.Data = make([]*dutyJSON, len(duties))
+	for i, duty := range duties {
+		response.Data[i] = dutyFromRecord(duty)
+	}
+	return api.Render(w, r, response)
+}
+
+// requestRoles is a comma-separated list of beacon role names, such as ATTESTER.
+type requestRoles []spectypes.BeaconRole
+
+func (rr *requestRoles) Bind(value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, s := range strings.Split(value, ",") {
+		role, err := message.BeaconRoleFromString(strings.ToUpper(s))
+		if err != nil {
+			return err
+		}
+		*rr = append(*rr, role)
+	}
+	return nil
+}
+
+type dutyJSON struct {
+	PubKey              api.Hex             `json:"public_key"`
+	Role                string              `json:"role"`
+	Slot                phase0.Slot         `json:"slot"`
+	Outcome             performance.Outcome `json:"outcome"`
+	Error               string              `json:"error,omitempty"`
+	Round               specqbft.Round      `json:"round,omitempty"`
+	Started             time.Time           `json:"started"`
+	Decided             *time.Time          `json:"decided,omitempty"`
+	ConsensusTime       string              `json:"consensus_time,omitempty"`
+	PostConsensusQuorum *time.Time          `json:"post_consensus_quorum,omitempty"`
+	PostConsensusTime   string              `json:"post_consensus_time,omitempty"`
+	Submitted           *time.Time          `json:"submitted,omitempty"`
+	TotalTime           string              `json:"total_time,omitempty"`
+}
+
+func dutyFromRecord(duty performance.Duty) *dutyJSON {
+	d := &dutyJSON{
+		PubKey:  api.Hex(duty.PubKey),
+		Role:    duty.Role.String(),
+		Slot:    duty.Slot,
+		Outcome: duty.Outcome,
+		Error:   duty.Error,
+		Round:   duty.Round,
+		Started: duty.Started,
+	}
+	if !duty.Decided.IsZero() {
+		d.Decided = &duty.Decided
+		d.ConsensusTime = duty.Decided.Sub(duty.Started).String()
+	}
+	if !duty.PostConsensusQuorum.IsZero() {
+		d.PostConsensusQuorum = &duty.PostConsensusQuorum
+		if !duty.Decided.IsZero() {
+			d.PostConsensusTime = duty.PostConsensusQuorum.Sub(duty.Decided).String()
+		}
+	}
+	if !duty.Submitted.IsZero() {
+		d.Submitted = &duty.Submitted
+		d.TotalTime = duty.Submitted.Sub(duty.Started).String()
+	}
+	return d
+}
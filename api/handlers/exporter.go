@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/ssv/api"
+	exporterapi "github.com/bloxapp/ssv/exporter/api"
+	"github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+const (
+	defaultDecidedsLimit = 100
+	maxDecidedsLimit     = 1000
+
+	// headerNextHeight is the response header holding the height to continue from, if there are more results.
+	headerNextHeight = "X-Next-Height"
+)
+
+type Exporter struct {
+	QBFTStores *storage.QBFTStores
+}
+
+// Decideds returns the decided messages of a validator's role in the given range of heights.
+// The range is inclusive, and without an upper bound it ends at the highest decided height.
+// Results are paginated: at most limit decided messages are returned per request,
+// and if the range isn't exhausted, the height to continue from is returned in next_height.
+// Responds with an SSZ-encoded list of signed messages if the request accepts application/octet-stream.
+func (h *Exporter) Decideds(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		PubKey api.Hex `json:"pubkey" form:"pubkey"`
+		Role   string  `json:"role" form:"role"`
+		From   uint64  `json:"from" form:"from"`
+		To     uint64  `json:"to" form:"to"`
+		Limit  uint64  `json:"limit" form:"limit"`
+	}
+	var response struct {
+		Data       any     `json:"data"`
+		NextHeight *uint64 `json:"next_height,omitempty"`
+	}
+
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if len(request.PubKey) != len(phase0.BLSPubKey{}) {
+		return api.InvalidRequestError(fmt.Errorf("invalid pubkey length: %d", len(request.PubKey)))
+	}
+	role, err := message.BeaconRoleFromString(request.Role)
+	if err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if request.To != 0 && request.To < request.From {
+		return api.InvalidRequestError(fmt.Errorf("to must not be lower than from"))
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultDecidedsLimit
+	}
+	if request.Limit > maxDecidedsLimit {
+		return api.InvalidRequestError(fmt.Errorf("limit must not be higher than %d", maxDecidedsLimit))
+	}
+
+	roleStorage := h.QBFTStores.Get(role)
+	if roleStorage == nil {
+		return api.Error(fmt.Errorf("role storage doesn't exist: %s", role))
+	}
+
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), request.PubKey, role)
+	msgs := make([]*specqbft.SignedMessage, 0)
+
+	// Without an upper bound, query up to the highest decided height.
+	from, to := specqbft.Height(request.From), specqbft.Height(request.To)
+	if request.To == 0 {
+		to = math.MaxUint64
+	}
+
+	// Scan one more instance than the limit to find the height to continue from.
+	instances, err := roleStorage.GetInstances(msgID[:], from, to, int(request.Limit)+1)
+	if err != nil {
+		return api.Error(errors.Wrap(err, "could not get decided messages"))
+	}
+	if uint64(len(instances)) > request.Limit {
+		next := uint64(instances[request.Limit].State.Height)
+		response.NextHeight = &next
+		w.Header().Set(headerNextHeight, strconv.FormatUint(next, 10))
+		instances = instances[:request.Limit]
+	}
+	for _, instance := range instances {
+		msgs = append(msgs, instance.DecidedMessage)
+	}
+
+	if api.AcceptsSSZ(r) {
+		data, err := marshalSignedMessagesSSZ(msgs)
+		if err != nil {
+			return api.Error(errors.Wrap(err, "could not encode decided messages"))
+		}
+		return api.RenderSSZ(w, data)
+	}
+
+	response.Data = []*exporterapi.SignedMessageAPI{}
+	if len(msgs) > 0 {
+		if response.Data, err = exporterapi.DecidedAPIData(msgs...); err != nil {
+			return api.Error(errors.Wrap(err, "could not convert decided messages"))
+		}
+	}
+	return api.Render(w, r, response)
+}
+
+// marshalSignedMessagesSSZ encodes the messages as an SSZ list of variable-size items:
+// a 4-byte offset per message followed by the concatenated messages.
+func marshalSignedMessagesSSZ(msgs []*specqbft.SignedMessage) ([]byte, error) {
+	offsets := make([]byte, 4*len(msgs))
+	var data []byte
+	for i, msg := range msgs {
+		binary.LittleEndian.PutUint32(offsets[4*i:], uint32(len(offsets)+len(data)))
+
+		b, err := msg.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return append(offsets, data...), nil
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/api"
+	"github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging"
+	protocoltesting "github.com/bloxapp/ssv/protocol/v2/testing"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+func TestExporterDecideds(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	role := spectypes.BNRoleAttester
+	stores := storage.NewStores()
+	stores.Add(role, storage.New(db, role.String()))
+
+	_ = bls.Init(bls.BLS12_381)
+	sks := make(map[spectypes.OperatorID]*bls.SecretKey)
+	var oids []spectypes.OperatorID
+	for i := spectypes.OperatorID(1); i <= 4; i++ {
+		sk := &bls.SecretKey{}
+		sk.SetByCSPRNG()
+		sks[i] = sk
+		oids = append(oids, i)
+	}
+	pk := sks[1].GetPublicKey().Serialize()
+	instances, err := protocoltesting.CreateMultipleStoredInstances(sks, 0, 250, func(height specqbft.Height) ([]spectypes.OperatorID, *specqbft.Message) {
+		id := spectypes.NewMsgID(types.GetDefaultDomain(), pk, role)
+		return oids, &specqbft.Message{
+			MsgType:    specqbft.CommitMsgType,
+			Height:     height,
+			Round:      1,
+			Identifier: id[:],
+			Root:       [32]byte{0x1, 0x2, 0x3},
+		}
+	})
+	require.NoError(t, err)
+	for _, instance := range instances {
+		require.NoError(t, stores.Get(role).SaveInstance(instance))
+	}
+	require.NoError(t, stores.Get(role).SaveHighestInstance(instances[len(instances)-1]))
+
+	h := &Exporter{QBFTStores: stores}
+	request := func(query string, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/v1/exporter/decideds?"+query, nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		api.Handler(h.Decideds)(w, r)
+		return w
+	}
+	type response struct {
+		Data       []json.RawMessage `json:"data"`
+		NextHeight *uint64           `json:"next_height"`
+	}
+	pkHex := hex.EncodeToString(pk)
+
+	t.Run("range", func(t *testing.T) {
+		w := request(fmt.Sprintf("pubkey=%s&role=ATTESTER&from=10&to=20", pkHex), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 11)
+		require.Nil(t, resp.NextHeight)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		w := request(fmt.Sprintf("pubkey=%s&role=ATTESTER&from=0&limit=100", pkHex), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 100)
+		require.NotNil(t, resp.NextHeight)
+		require.EqualValues(t, 100, *resp.NextHeight)
+		require.Equal(t, "100", w.Header().Get(headerNextHeight))
+
+		w = request(fmt.Sprintf("pubkey=%s&role=ATTESTER&from=200&limit=100", pkHex), "")
+		resp = response{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 51)
+		require.Nil(t, resp.NextHeight)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		w := request(fmt.Sprintf("pubkey=%s&role=ATTESTER&from=400&to=404", pkHex), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Empty(t, resp.Data)
+	})
+
+	t.Run("ssz", func(t *testing.T) {
+		w := request(fmt.Sprintf("pubkey=%s&role=ATTESTER&from=5&to=6", pkHex), "application/octet-stream")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+
+		body := w.Body.Bytes()
+		first := binary.LittleEndian.Uint32(body[0:4])
+		second := binary.LittleEndian.Uint32(body[4:8])
+		require.EqualValues(t, 8, first)
+
+		msg := &specqbft.SignedMessage{}
+		require.NoError(t, msg.UnmarshalSSZ(body[first:second]))
+		require.EqualValues(t, 5, msg.Message.Height)
+		require.NoError(t, msg.UnmarshalSSZ(body[second:]))
+		require.EqualValues(t, 6, msg.Message.Height)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, query := range []string{
+			fmt.Sprintf("pubkey=%s&role=UNKNOWN", pkHex),
+			"pubkey=abcd&role=ATTESTER",
+			fmt.Sprintf("pubkey=%s&role=ATTESTER&from=10&to=5", pkHex),
+			fmt.Sprintf("pubkey=%s&role=ATTESTER&limit=100000", pkHex),
+		} {
+			w := request(query, "")
+			require.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
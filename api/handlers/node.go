@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/bloxapp/ssv/api"
 	networkpeers "github.com/bloxapp/ssv/network/peers"
+	"github.com/bloxapp/ssv/network/peers/connections"
 	"github.com/bloxapp/ssv/nodeprobe"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
@@ -17,12 +22,22 @@ import (
 const (
 	healthyPeerCount = 20
 	healthyInbounds  = 4
+
+	// backupTimeout bounds the duration of a database backup.
+	backupTimeout = 30 * time.Minute
 )
 
 type TopicIndex interface {
 	PeersByTopic() ([]peer.ID, map[string][]peer.ID)
 }
 
+// PeerPolicy holds the static and denied peers of the p2p network.
+type PeerPolicy interface {
+	StaticPeers() []peer.AddrInfo
+	DeniedPeers() []peer.ID
+	UpdatePeerPolicy(static []peer.AddrInfo, denied []peer.ID) error
+}
+
 type AllPeersAndTopicsJSON struct {
 	AllPeers     []peer.ID        `json:"all_peers"`
 	PeersByTopic []topicIndexJSON `json:"peers_by_topic"`
@@ -54,6 +69,11 @@ type identityJSON struct {
 	Version   string   `json:"version"`
 }
 
+type peerPolicyJSON struct {
+	StaticPeers []string `json:"static_peers"`
+	DenyPeers   []string `json:"deny_peers"`
+}
+
 type healthStatus struct {
 	err error
 }
@@ -66,11 +86,13 @@ func (h healthStatus) MarshalJSON() ([]byte, error) {
 }
 
 type healthCheckJSON struct {
-	P2P           healthStatus `json:"p2p"`
-	BeaconNode    healthStatus `json:"beacon_node"`
-	ExecutionNode healthStatus `json:"execution_node"`
-	EventSyncer   healthStatus `json:"event_syncer"`
-	Advanced      struct {
+	P2P            healthStatus            `json:"p2p"`
+	BeaconNode     healthStatus            `json:"beacon_node"`
+	BeaconNodes    map[string]healthStatus `json:"beacon_nodes,omitempty"`
+	ExecutionNode  healthStatus            `json:"execution_node"`
+	ExecutionNodes map[string]healthStatus `json:"execution_nodes,omitempty"`
+	EventSyncer    healthStatus            `json:"event_syncer"`
+	Advanced       struct {
 		Peers           int      `json:"peers"`
 		InboundConns    int      `json:"inbound_conns"`
 		OutboundConns   int      `json:"outbound_conns"`
@@ -92,6 +114,13 @@ type Node struct {
 	TopicIndex      TopicIndex
 	Network         network.Network
 	NodeProber      *nodeprobe.Prober
+	// DBBackup writes an encrypted backup of the node's database.
+	DBBackup func(w io.Writer) error
+	// RegistrySnapshot writes a snapshot of the registry, from which other nodes can be bootstrapped.
+	RegistrySnapshot func(w io.Writer) error
+	// PendingTasks returns the contract event tasks which weren't executed successfully yet.
+	PendingTasks func() ([]*operatorstorage.PendingTask, error)
+	PeerPolicy   PeerPolicy
 }
 
 func (h *Node) Identity(w http.ResponseWriter, r *http.Request) error {
@@ -159,12 +188,118 @@ func (h *Node) Health(w http.ResponseWriter, r *http.Request) error {
 
 	// Check the health of Ethereum nodes and EventSyncer.
 	resp.BeaconNode = healthStatus{h.NodeProber.CheckBeaconNodeHealth(ctx)}
+	if endpoints := h.NodeProber.CheckBeaconNodeEndpointsHealth(ctx); len(endpoints) > 1 {
+		resp.BeaconNodes = make(map[string]healthStatus, len(endpoints))
+		for address, err := range endpoints {
+			resp.BeaconNodes[address] = healthStatus{err}
+		}
+	}
 	resp.ExecutionNode = healthStatus{h.NodeProber.CheckExecutionNodeHealth(ctx)}
+	if endpoints := h.NodeProber.CheckExecutionNodeEndpointsHealth(ctx); len(endpoints) > 1 {
+		resp.ExecutionNodes = make(map[string]healthStatus, len(endpoints))
+		for address, err := range endpoints {
+			resp.ExecutionNodes[address] = healthStatus{err}
+		}
+	}
 	resp.EventSyncer = healthStatus{(h.NodeProber.CheckEventSyncerHealth(ctx))}
 
 	return api.Render(w, r, resp)
 }
 
+// Backup streams an encrypted backup of the node's database, taken while the node is running.
+func (h *Node) Backup(w http.ResponseWriter, r *http.Request) error {
+	if h.DBBackup == nil {
+		return api.Error(errors.New("database backup is not available"))
+	}
+
+	// Backups of large databases may take longer than the server's write timeout,
+	// but must not hold the database open indefinitely.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(backupTimeout))
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="ssv-%s.backup"`, time.Now().UTC().Format("20060102-150405")))
+	return h.DBBackup(w)
+}
+
+// Snapshot streams a snapshot of the registry as of the last processed block.
+func (h *Node) Snapshot(w http.ResponseWriter, r *http.Request) error {
+	if h.RegistrySnapshot == nil {
+		return api.Error(errors.New("registry snapshot is not available"))
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="ssv-%s.snapshot"`, time.Now().UTC().Format("20060102-150405")))
+	return h.RegistrySnapshot(w)
+}
+
+// Tasks lists the contract event tasks, such as starting a validator, which are pending execution or retry.
+func (h *Node) Tasks(w http.ResponseWriter, r *http.Request) error {
+	if h.PendingTasks == nil {
+		return api.Error(errors.New("task journal is not available"))
+	}
+	var response struct {
+		Data []*operatorstorage.PendingTask `json:"data"`
+	}
+	tasks, err := h.PendingTasks()
+	if err != nil {
+		return api.Error(err)
+	}
+	response.Data = tasks
+	if response.Data == nil {
+		response.Data = []*operatorstorage.PendingTask{}
+	}
+	return api.Render(w, r, response)
+}
+
+// GetPeerPolicy returns the static and denied peers.
+func (h *Node) GetPeerPolicy(w http.ResponseWriter, r *http.Request) error {
+	return api.Render(w, r, h.peerPolicy())
+}
+
+// UpdatePeerPolicy replaces the static and denied peers, which then override the configured ones,
+// including after the node restarts.
+func (h *Node) UpdatePeerPolicy(w http.ResponseWriter, r *http.Request) error {
+	var request peerPolicyJSON
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	static, err := connections.ParseStaticPeers(request.StaticPeers)
+	if err != nil {
+		return api.InvalidRequestError(err)
+	}
+	denied, err := connections.ParseDeniedPeers(request.DenyPeers)
+	if err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if err := h.PeerPolicy.UpdatePeerPolicy(static, denied); err != nil {
+		return api.Error(err)
+	}
+	return api.Render(w, r, h.peerPolicy())
+}
+
+func (h *Node) peerPolicy() peerPolicyJSON {
+	resp := peerPolicyJSON{
+		StaticPeers: []string{},
+		DenyPeers:   []string{},
+	}
+	for _, info := range h.PeerPolicy.StaticPeers() {
+		info := info
+		addrs, err := peer.AddrInfoToP2pAddrs(&info)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			resp.StaticPeers = append(resp.StaticPeers, addr.String())
+		}
+	}
+	for _, id := range h.PeerPolicy.DeniedPeers() {
+		resp.DenyPeers = append(resp.DenyPeers, id.String())
+	}
+	sort.Strings(resp.StaticPeers)
+	sort.Strings(resp.DenyPeers)
+	return resp
+}
+
 func (h *Node) peers(peers []peer.ID) []peerJSON {
 	resp := make([]peerJSON, len(peers))
 	for i, id := range peers {
@@ -11,6 +11,7 @@ import (
 const (
 	contentTypePlainText = "text/plain"
 	contentTypeJSON      = "application/json"
+	contentTypeSSZ       = "application/octet-stream"
 )
 
 type HandlerFunc func(http.ResponseWriter, *http.Request) error
@@ -52,3 +53,21 @@ func Render(w http.ResponseWriter, r *http.Request, response any) error {
 		return nil
 	}
 }
+
+// AcceptsSSZ returns true if the request prefers an SSZ-encoded response over JSON.
+func AcceptsSSZ(r *http.Request) bool {
+	contentType := httputil.NegotiateContentType(
+		r,
+		[]string{contentTypeJSON, contentTypeSSZ},
+		contentTypeJSON,
+	)
+	return contentType == contentTypeSSZ
+}
+
+// RenderSSZ renders an SSZ-encoded response.
+func RenderSSZ(w http.ResponseWriter, data []byte) error {
+	w.Header().Set("Content-Type", contentTypeSSZ)
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(data)
+	return err
+}
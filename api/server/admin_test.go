@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminOnly(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		token         string
+		remoteAddr    string
+		authorization string
+		status        int
+	}{
+		{name: "localhost without token", remoteAddr: "127.0.0.1:1234", status: http.StatusOK},
+		{name: "ipv6 localhost without token", remoteAddr: "[::1]:1234", status: http.StatusOK},
+		{name: "remote without token", remoteAddr: "10.0.0.1:1234", status: http.StatusUnauthorized},
+		{name: "remote with token", token: "secret", remoteAddr: "10.0.0.1:1234", authorization: "Bearer secret", status: http.StatusOK},
+		{name: "remote with wrong token", token: "secret", remoteAddr: "10.0.0.1:1234", authorization: "Bearer wrong", status: http.StatusUnauthorized},
+		{name: "localhost without required token", token: "secret", remoteAddr: "127.0.0.1:1234", status: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = test.remoteAddr
+			if test.authorization != "" {
+				r.Header.Set("Authorization", test.authorization)
+			}
+			w := httptest.NewRecorder()
+			adminOnly(test.token)(handler).ServeHTTP(w, r)
+			require.Equal(t, test.status, w.Code)
+		})
+	}
+}
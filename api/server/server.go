@@ -14,24 +14,33 @@ import (
 )
 
 type Server struct {
-	logger *zap.Logger
-	addr   string
+	logger     *zap.Logger
+	addr       string
+	adminToken string
 
 	node       *handlers.Node
 	validators *handlers.Validators
+	duties     *handlers.Duties
+	exporter   *handlers.Exporter
 }
 
 func New(
 	logger *zap.Logger,
 	addr string,
+	adminToken string,
 	node *handlers.Node,
 	validators *handlers.Validators,
+	duties *handlers.Duties,
+	exporter *handlers.Exporter,
 ) *Server {
 	return &Server{
 		logger:     logger,
 		addr:       addr,
+		adminToken: adminToken,
 		node:       node,
 		validators: validators,
+		duties:     duties,
+		exporter:   exporter,
 	}
 }
 
@@ -46,7 +55,19 @@ func (s *Server) Run() error {
 	router.Get("/v1/node/peers", api.Handler(s.node.Peers))
 	router.Get("/v1/node/topics", api.Handler(s.node.Topics))
 	router.Get("/v1/node/health", api.Handler(s.node.Health))
+	router.Get("/v1/node/peer-policy", api.Handler(s.node.GetPeerPolicy))
 	router.Get("/v1/validators", api.Handler(s.validators.List))
+	router.Get("/v1/duties", api.Handler(s.duties.List))
+	router.Get("/v1/exporter/decideds", api.Handler(s.exporter.Decideds))
+
+	// Routes which expose or modify the node's internals.
+	router.Group(func(admin chi.Router) {
+		admin.Use(adminOnly(s.adminToken))
+		admin.Get("/v1/node/backup", api.Handler(s.node.Backup))
+		admin.Get("/v1/node/snapshot", api.Handler(s.node.Snapshot))
+		admin.Get("/v1/node/tasks", api.Handler(s.node.Tasks))
+		admin.Put("/v1/node/peer-policy", api.Handler(s.node.UpdatePeerPolicy))
+	})
 
 	s.logger.Info("Serving SSV API", zap.String("addr", s.addr))
 
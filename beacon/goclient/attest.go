@@ -14,7 +14,12 @@ import (
 
 // AttesterDuties returns attester duties for a given epoch.
 func (gc *goClient) AttesterDuties(ctx context.Context, epoch phase0.Epoch, validatorIndices []phase0.ValidatorIndex) ([]*eth2apiv1.AttesterDuty, error) {
-	return gc.client.AttesterDuties(ctx, epoch, validatorIndices)
+	duties, err := gc.client.AttesterDuties(ctx, epoch, validatorIndices)
+	if err != nil {
+		return nil, err
+	}
+	gc.cacheAttesterDuties(epoch, duties)
+	return duties, nil
 }
 
 func (gc *goClient) GetAttestationData(slot phase0.Slot, committeeIndex phase0.CommitteeIndex) (ssz.Marshaler, spec.DataVersion, error) {
@@ -37,7 +42,7 @@ func (gc *goClient) SubmitAttestation(attestation *phase0.Attestation) error {
 		return errors.Wrap(err, "failed to get signing root")
 	}
 
-	if err := gc.slashableAttestationCheck(gc.ctx, signingRoot); err != nil {
+	if err := gc.slashableAttestationCheck(attestation, signingRoot); err != nil {
 		return errors.Wrap(err, "failed attestation slashing protection check")
 	}
 
@@ -1,13 +1,117 @@
 package goclient
 
 import (
-	"context"
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
 )
 
+// attesterDutyKey identifies an attester duty by its position in a beacon committee,
+// which is all the information a submitted attestation carries about its signer.
+type attesterDutyKey struct {
+	slot                    phase0.Slot
+	committeeIndex          phase0.CommitteeIndex
+	validatorCommitteeIndex uint64
+}
+
+// cacheAttesterDuties remembers the validator public keys of the given duties,
+// so that attestations can later be matched to their validator for slashing protection.
+func (gc *goClient) cacheAttesterDuties(epoch phase0.Epoch, duties []*eth2apiv1.AttesterDuty) {
+	gc.attesterDutiesMu.Lock()
+	defer gc.attesterDutiesMu.Unlock()
+
+	for _, duty := range duties {
+		key := attesterDutyKey{
+			slot:                    duty.Slot,
+			committeeIndex:          duty.CommitteeIndex,
+			validatorCommitteeIndex: duty.ValidatorCommitteeIndex,
+		}
+		gc.attesterDuties[key] = duty.PubKey
+	}
+
+	// Drop duties older than the previous epoch.
+	if epoch == 0 {
+		return
+	}
+	oldestSlot := gc.network.GetEpochFirstSlot(epoch - 1)
+	for key := range gc.attesterDuties {
+		if key.slot < oldestSlot {
+			delete(gc.attesterDuties, key)
+		}
+	}
+}
+
 // slashableAttestationCheck checks if an attestation is slashable by comparing it with the attesting
-// history for the given public key in our DB. If it is not, we then update the history
+// history of its validator in our DB. If it is not, we then update the history
 // with new values and save it to the database.
-func (gc *goClient) slashableAttestationCheck(ctx context.Context, signingRoot [32]byte) error {
-	// TODO: Implement
-	return nil
+func (gc *goClient) slashableAttestationCheck(attestation *phase0.Attestation, signingRoot [32]byte) error {
+	if attestation.Data == nil {
+		return errors.New("attestation data is nil")
+	}
+
+	bits := attestation.AggregationBits.BitIndices()
+	if len(bits) != 1 {
+		return errors.Errorf("expected a single aggregation bit, got %d", len(bits))
+	}
+
+	key := attesterDutyKey{
+		slot:                    attestation.Data.Slot,
+		committeeIndex:          attestation.Data.Index,
+		validatorCommitteeIndex: uint64(bits[0]),
+	}
+
+	gc.attesterDutiesMu.Lock()
+	pubKey, ok := gc.attesterDuties[key]
+	gc.attesterDutiesMu.Unlock()
+	if !ok {
+		// The duties aren't cached after a restart or a beacon node failover,
+		// so the attester is found through the committee of the attestation instead.
+		var err error
+		pubKey, err = gc.fetchAttesterPubKey(key)
+		if err != nil {
+			return errors.Wrap(err, "could not find attester of attestation")
+		}
+	}
+
+	return gc.attestationProtector.CheckAndSaveAttestation(pubKey[:], attestation.Data, signingRoot)
+}
+
+// fetchAttesterPubKey returns the public key of the validator at the given position of a beacon committee,
+// and caches it like the attester duties.
+func (gc *goClient) fetchAttesterPubKey(key attesterDutyKey) (phase0.BLSPubKey, error) {
+	epoch := gc.network.EstimatedEpochAtSlot(key.slot)
+	committees, err := gc.client.BeaconCommitteesAtEpoch(gc.ctx, "head", epoch)
+	if err != nil {
+		return phase0.BLSPubKey{}, errors.Wrap(err, "failed to get beacon committees")
+	}
+
+	var validatorIndex *phase0.ValidatorIndex
+	for _, committee := range committees {
+		if committee.Slot != key.slot || committee.Index != key.committeeIndex {
+			continue
+		}
+		if key.validatorCommitteeIndex >= uint64(len(committee.Validators)) {
+			return phase0.BLSPubKey{}, errors.Errorf("aggregation bit %d is out of committee of size %d", key.validatorCommitteeIndex, len(committee.Validators))
+		}
+		validatorIndex = &committee.Validators[key.validatorCommitteeIndex]
+		break
+	}
+	if validatorIndex == nil {
+		return phase0.BLSPubKey{}, errors.Errorf("committee %d of slot %d not found", key.committeeIndex, key.slot)
+	}
+
+	validators, err := gc.client.Validators(gc.ctx, "head", []phase0.ValidatorIndex{*validatorIndex})
+	if err != nil {
+		return phase0.BLSPubKey{}, errors.Wrap(err, "failed to get validator")
+	}
+	validator, ok := validators[*validatorIndex]
+	if !ok || validator.Validator == nil {
+		return phase0.BLSPubKey{}, errors.Errorf("validator %d not found", *validatorIndex)
+	}
+
+	gc.attesterDutiesMu.Lock()
+	gc.attesterDuties[key] = validator.Validator.PublicKey
+	gc.attesterDutiesMu.Unlock()
+
+	return validator.Validator.PublicKey, nil
 }
@@ -0,0 +1,268 @@
+package goclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1bellatrix "github.com/attestantio/go-eth2-client/api/v1/bellatrix"
+	apiv1capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/pkg/errors"
+)
+
+// blockProposalEndpoint is the endpoint from which block proposals are requested along with their values.
+// go-eth2-client doesn't support it yet, nor does it expose response headers, so it's requested directly
+// from the configured beacon node addresses. Beacon nodes which don't support it yet make the proposer
+// fall back to requesting a blinded block without comparing values.
+const blockProposalEndpoint = "/eth/v3/validator/blocks"
+
+const (
+	// executionPayloadValueHeader is the header in which beacon nodes report the value of the execution payload
+	// of a block proposal to the proposer, in wei.
+	executionPayloadValueHeader = "Eth-Execution-Payload-Value"
+	// executionPayloadBlindedHeader is the header in which beacon nodes report whether the block proposal is blinded.
+	executionPayloadBlindedHeader = "Eth-Execution-Payload-Blinded"
+)
+
+const (
+	// localBoostFactor makes the beacon node propose its locally built block.
+	localBoostFactor = "0"
+	// builderBoostFactor makes the beacon node propose the block of an external builder whenever it has a bid.
+	builderBoostFactor = "18446744073709551615"
+)
+
+const blockProposalTimeout = 5 * time.Second
+
+// blockProposalClient requests block proposals, with the timeouts go-eth2-client uses for its requests.
+var blockProposalClient = &nethttp.Client{
+	Timeout: blockProposalTimeout,
+	Transport: &nethttp.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   blockProposalTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        64,
+		MaxConnsPerHost:     64,
+		MaxIdleConnsPerHost: 64,
+		IdleConnTimeout:     600 * time.Second,
+	},
+}
+
+// BlockValueProvider is implemented by beacon nodes which report the value of the execution payload
+// of block proposals, so that the blocks of external builders can be compared with locally built blocks.
+// An error is returned if the beacon node doesn't support /eth/v3/validator/blocks or doesn't report the value.
+type BlockValueProvider interface {
+	GetBeaconBlockWithValue(slot phase0.Slot, graffiti, randao []byte) (ssz.Marshaler, spec.DataVersion, *big.Int, error)
+	GetBlindedBeaconBlockWithValue(slot phase0.Slot, graffiti, randao []byte) (ssz.Marshaler, spec.DataVersion, *big.Int, error)
+}
+
+// GetBeaconBlockWithValue returns a locally built beacon block by the given slot, graffiti, and randao,
+// with the value of its execution payload.
+func (gc *goClient) GetBeaconBlockWithValue(slot phase0.Slot, graffiti, randao []byte) (ssz.Marshaler, spec.DataVersion, *big.Int, error) {
+	reqStart := time.Now()
+	version, data, value, err := gc.blockProposal(slot, graffiti, randao, false)
+	if err != nil {
+		return nil, DataVersionNil, nil, err
+	}
+	metricsProposerDataRequest.Observe(time.Since(reqStart).Seconds())
+
+	switch version {
+	case spec.DataVersionBellatrix:
+		block := &bellatrix.BeaconBlock{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return nil, DataVersionNil, nil, errors.Wrap(err, "failed to decode bellatrix block")
+		}
+		if block.Body == nil || block.Body.ExecutionPayload == nil {
+			return nil, DataVersionNil, nil, fmt.Errorf("bellatrix block execution payload is nil")
+		}
+		if err := checkBlockProposal(block.Slot, block.Body.RANDAOReveal, slot, randao); err != nil {
+			return nil, DataVersionNil, nil, err
+		}
+		return block, version, value, nil
+	case spec.DataVersionCapella:
+		block := &capella.BeaconBlock{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return nil, DataVersionNil, nil, errors.Wrap(err, "failed to decode capella block")
+		}
+		if block.Body == nil || block.Body.ExecutionPayload == nil {
+			return nil, DataVersionNil, nil, fmt.Errorf("capella block execution payload is nil")
+		}
+		if err := checkBlockProposal(block.Slot, block.Body.RANDAOReveal, slot, randao); err != nil {
+			return nil, DataVersionNil, nil, err
+		}
+		return block, version, value, nil
+	default:
+		return nil, DataVersionNil, nil, fmt.Errorf("beacon block version %s not supported", version)
+	}
+}
+
+// GetBlindedBeaconBlockWithValue returns a blinded beacon block of an external builder by the given slot, graffiti, and randao,
+// with the value of its execution payload, which is the builder's bid.
+func (gc *goClient) GetBlindedBeaconBlockWithValue(slot phase0.Slot, graffiti, randao []byte) (ssz.Marshaler, spec.DataVersion, *big.Int, error) {
+	reqStart := time.Now()
+	version, data, value, err := gc.blockProposal(slot, graffiti, randao, true)
+	if err != nil {
+		return nil, DataVersionNil, nil, err
+	}
+	metricsProposerDataRequest.Observe(time.Since(reqStart).Seconds())
+
+	switch version {
+	case spec.DataVersionBellatrix:
+		block := &apiv1bellatrix.BlindedBeaconBlock{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return nil, DataVersionNil, nil, errors.Wrap(err, "failed to decode bellatrix blinded block")
+		}
+		if block.Body == nil || block.Body.ExecutionPayloadHeader == nil {
+			return nil, DataVersionNil, nil, fmt.Errorf("bellatrix block execution payload header is nil")
+		}
+		if err := checkBlockProposal(block.Slot, block.Body.RANDAOReveal, slot, randao); err != nil {
+			return nil, DataVersionNil, nil, err
+		}
+		return block, version, value, nil
+	case spec.DataVersionCapella:
+		block := &apiv1capella.BlindedBeaconBlock{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return nil, DataVersionNil, nil, errors.Wrap(err, "failed to decode capella blinded block")
+		}
+		if block.Body == nil || block.Body.ExecutionPayloadHeader == nil {
+			return nil, DataVersionNil, nil, fmt.Errorf("capella block execution payload header is nil")
+		}
+		if err := checkBlockProposal(block.Slot, block.Body.RANDAOReveal, slot, randao); err != nil {
+			return nil, DataVersionNil, nil, err
+		}
+		return block, version, value, nil
+	default:
+		return nil, DataVersionNil, nil, fmt.Errorf("beacon block version %s not supported", version)
+	}
+}
+
+// blockProposal requests a block proposal which is either blinded, from an external builder,
+// or locally built, returning its version, its encoded block and the value of its execution payload.
+// The endpoints are tried in turn, starting with the active one.
+func (gc *goClient) blockProposal(slot phase0.Slot, graffiti, randao []byte, blinded bool) (spec.DataVersion, json.RawMessage, *big.Int, error) {
+	addresses := []string{gc.client.Address()}
+	for _, endpoint := range gc.endpoints {
+		if endpoint.Address() != addresses[0] {
+			addresses = append(addresses, endpoint.Address())
+		}
+	}
+
+	var errs []string
+	for _, address := range addresses {
+		version, data, value, err := gc.blockProposalFrom(address, slot, graffiti, randao, blinded)
+		if err == nil {
+			return version, data, value, nil
+		}
+		if len(addresses) == 1 {
+			return DataVersionNil, nil, nil, err
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", address, err))
+	}
+	return DataVersionNil, nil, nil, fmt.Errorf("failed to get block proposal from any endpoint: %s", strings.Join(errs, "; "))
+}
+
+func (gc *goClient) blockProposalFrom(address string, slot phase0.Slot, graffiti, randao []byte, blinded bool) (spec.DataVersion, json.RawMessage, *big.Int, error) {
+	// Graffiti should be 32 bytes.
+	fixedGraffiti := make([]byte, 32)
+	copy(fixedGraffiti, graffiti)
+
+	boostFactor := localBoostFactor
+	if blinded {
+		boostFactor = builderBoostFactor
+	}
+
+	// Parse the address like go-eth2-client does, keeping any credentials in it.
+	if !strings.HasPrefix(address, "http") {
+		address = "http://" + address
+	}
+	base, err := url.Parse(address)
+	if err != nil {
+		return DataVersionNil, nil, nil, errors.Wrap(err, "invalid beacon node address")
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/") + fmt.Sprintf("%s/%d", blockProposalEndpoint, slot)
+	base.RawQuery = fmt.Sprintf("randao_reveal=%#x&graffiti=%#x&builder_boost_factor=%s", randao, fixedGraffiti, boostFactor)
+
+	ctx, cancel := context.WithTimeout(gc.ctx, blockProposalTimeout)
+	defer cancel()
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, base.String(), nil)
+	if err != nil {
+		return DataVersionNil, nil, nil, errors.Wrap(err, "failed to create block proposal request")
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := blockProposalClient.Do(req)
+	if err != nil {
+		return DataVersionNil, nil, nil, errors.Wrap(err, "failed to request block proposal")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return DataVersionNil, nil, nil, errors.Wrap(err, "failed to read block proposal")
+	}
+	if resp.StatusCode/100 != 2 {
+		return DataVersionNil, nil, nil, fmt.Errorf("%s request failed with status %d: %s", blockProposalEndpoint, resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var proposal struct {
+		Version spec.DataVersion `json:"version"`
+		Blinded *bool            `json:"execution_payload_blinded"`
+		Value   string           `json:"execution_payload_value"`
+		Data    json.RawMessage  `json:"data"`
+	}
+	if err := json.Unmarshal(body, &proposal); err != nil {
+		return DataVersionNil, nil, nil, errors.Wrap(err, "failed to decode block proposal")
+	}
+
+	// The headers are required by the specification of the endpoint, and the fields are set by most beacon nodes as well.
+	if header := resp.Header.Get(executionPayloadBlindedHeader); header != "" {
+		isBlinded, err := strconv.ParseBool(header)
+		if err != nil {
+			return DataVersionNil, nil, nil, fmt.Errorf("invalid %s header %q", executionPayloadBlindedHeader, header)
+		}
+		proposal.Blinded = &isBlinded
+	}
+	if header := resp.Header.Get(executionPayloadValueHeader); header != "" {
+		proposal.Value = header
+	}
+	if proposal.Blinded == nil {
+		return DataVersionNil, nil, nil, errors.New("beacon node didn't report whether the block proposal is blinded")
+	}
+	if *proposal.Blinded != blinded {
+		if blinded {
+			return DataVersionNil, nil, nil, errors.New("beacon node has no builder block proposal")
+		}
+		return DataVersionNil, nil, nil, errors.New("beacon node proposed a blinded block instead of a local one")
+	}
+	if proposal.Value == "" {
+		return DataVersionNil, nil, nil, errors.New("beacon node didn't report the execution payload value")
+	}
+	value, ok := new(big.Int).SetString(proposal.Value, 10)
+	if !ok {
+		return DataVersionNil, nil, nil, fmt.Errorf("invalid execution payload value %q", proposal.Value)
+	}
+	return proposal.Version, proposal.Data, value, nil
+}
+
+func checkBlockProposal(blockSlot phase0.Slot, blockRandao phase0.BLSSignature, slot phase0.Slot, randao []byte) error {
+	if blockSlot != slot {
+		return errors.New("beacon block proposal not for requested slot")
+	}
+	if !bytes.Equal(blockRandao[:], randao) {
+		return fmt.Errorf("beacon block proposal has RANDAO reveal %#x; expected %#x", blockRandao[:], randao)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package goclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// FinalizedExecutionBlock returns the number of the execution block included in the latest finalized beacon block,
+// along with the epoch of the finalized checkpoint.
+// The block is only fetched when the finalized checkpoint changes.
+func (gc *goClient) FinalizedExecutionBlock(ctx context.Context) (uint64, phase0.Epoch, error) {
+	finality, err := gc.client.Finality(ctx, "head")
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get finality")
+	}
+	if finality == nil || finality.Finalized == nil {
+		return 0, 0, errors.New("finality is nil")
+	}
+	checkpoint := finality.Finalized
+
+	gc.finalizedMu.Lock()
+	defer gc.finalizedMu.Unlock()
+
+	if gc.finalizedRoot == checkpoint.Root {
+		return gc.finalizedBlock, checkpoint.Epoch, nil
+	}
+
+	block, err := gc.client.SignedBeaconBlock(ctx, fmt.Sprintf("%#x", checkpoint.Root))
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get finalized block")
+	}
+	if block == nil {
+		return 0, 0, errors.New("finalized block is nil")
+	}
+	number, err := executionBlockNumber(block)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	gc.finalizedRoot = checkpoint.Root
+	gc.finalizedBlock = number
+	return number, checkpoint.Epoch, nil
+}
+
+// executionBlockNumber returns the number of the execution payload of the given block,
+// which is 0 before the merge.
+func executionBlockNumber(block *spec.VersionedSignedBeaconBlock) (uint64, error) {
+	switch block.Version {
+	case spec.DataVersionPhase0, spec.DataVersionAltair:
+		return 0, nil
+	case spec.DataVersionBellatrix:
+		if block.Bellatrix == nil || block.Bellatrix.Message == nil || block.Bellatrix.Message.Body == nil || block.Bellatrix.Message.Body.ExecutionPayload == nil {
+			return 0, errors.New("bellatrix block has no execution payload")
+		}
+		return block.Bellatrix.Message.Body.ExecutionPayload.BlockNumber, nil
+	case spec.DataVersionCapella:
+		if block.Capella == nil || block.Capella.Message == nil || block.Capella.Message.Body == nil || block.Capella.Message.Body.ExecutionPayload == nil {
+			return 0, errors.New("capella block has no execution payload")
+		}
+		return block.Capella.Message.Body.ExecutionPayload.BlockNumber, nil
+	case spec.DataVersionDeneb:
+		if block.Deneb == nil || block.Deneb.Message == nil || block.Deneb.Message.Body == nil || block.Deneb.Message.Body.ExecutionPayload == nil {
+			return 0, errors.New("deneb block has no execution payload")
+		}
+		return block.Deneb.Message.Body.ExecutionPayload.BlockNumber, nil
+	default:
+		return 0, errors.Errorf("unsupported block version %s", block.Version)
+	}
+}
@@ -2,6 +2,7 @@ package goclient
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"strings"
 	"sync"
@@ -10,6 +11,7 @@ import (
 	eth2client "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
 	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/multi"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	spectypes "github.com/bloxapp/ssv-spec/types"
@@ -19,6 +21,7 @@ import (
 	"github.com/rs/zerolog"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/ekm"
 	"github.com/bloxapp/ssv/logging/fields"
 	"github.com/bloxapp/ssv/operator/slotticker"
 	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
@@ -33,12 +36,17 @@ type beaconNodeStatus int32
 var (
 	allMetrics = []prometheus.Collector{
 		metricsBeaconNodeStatus,
+		metricsBeaconEndpointStatus,
 		metricsBeaconDataRequest,
 	}
 	metricsBeaconNodeStatus = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "ssv_beacon_status",
 		Help: "Status of the connected beacon node",
 	})
+	metricsBeaconEndpointStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv_beacon_endpoint_status",
+		Help: "Status of each configured beacon node endpoint",
+	}, []string{"address"})
 
 	// metricsBeaconDataRequest is located here to avoid including waiting for 1/3 or 2/3 of slot time into request duration.
 	metricsBeaconDataRequest = promauto.NewHistogramVec(prometheus.HistogramOpts{
@@ -93,7 +101,6 @@ func ParseNodeClient(version string) NodeClient {
 type Client interface {
 	eth2client.Service
 	eth2client.NodeVersionProvider
-	eth2client.NodeClientProvider
 
 	eth2client.AttestationDataProvider
 	eth2client.AggregateAttestationProvider
@@ -103,6 +110,7 @@ type Client interface {
 	eth2client.BeaconCommitteeSubscriptionsSubmitter
 	eth2client.SyncCommitteeSubscriptionsSubmitter
 	eth2client.AttesterDutiesProvider
+	eth2client.BeaconCommitteesProvider
 	eth2client.ProposerDutiesProvider
 	eth2client.SyncCommitteeDutiesProvider
 	eth2client.NodeSyncingProvider
@@ -123,6 +131,8 @@ type Client interface {
 	eth2client.BlindedBeaconBlockSubmitter
 	eth2client.ValidatorRegistrationsSubmitter
 	eth2client.VoluntaryExitSubmitter
+	eth2client.FinalityProvider
+	eth2client.SignedBeaconBlockProvider
 }
 
 type NodeClientProvider interface {
@@ -137,6 +147,7 @@ type goClient struct {
 	ctx                  context.Context
 	network              beaconprotocol.Network
 	client               Client
+	endpoints            []*http.Service
 	nodeVersion          string
 	nodeClient           NodeClient
 	graffiti             []byte
@@ -145,35 +156,83 @@ type goClient struct {
 	registrationMu       sync.Mutex
 	registrationLastSlot phase0.Slot
 	registrationCache    map[phase0.BLSPubKey]*api.VersionedSignedValidatorRegistration
+	attestationProtector ekm.AttestationProtector
+	attesterDutiesMu     sync.Mutex
+	attesterDuties       map[attesterDutyKey]phase0.BLSPubKey
+	finalizedMu          sync.Mutex
+	finalizedRoot        phase0.Root
+	finalizedBlock       uint64
 }
 
 // New init new client and go-client instance
-func New(logger *zap.Logger, opt beaconprotocol.Options, operatorID spectypes.OperatorID, slotTickerProvider slotticker.Provider) (beaconprotocol.BeaconNode, error) {
+func New(
+	logger *zap.Logger,
+	opt beaconprotocol.Options,
+	operatorID spectypes.OperatorID,
+	slotTickerProvider slotticker.Provider,
+	attestationProtector ekm.AttestationProtector,
+) (beaconprotocol.BeaconNode, error) {
 	logger.Info("consensus client: connecting", fields.Address(opt.BeaconNodeAddr), fields.Network(string(opt.Network.BeaconNetwork)))
 
-	httpClient, err := http.New(opt.Context,
-		// WithAddress supplies the address of the beacon node, in host:port format.
-		http.WithAddress(opt.BeaconNodeAddr),
-		// LogLevel supplies the level of logging to carry out.
-		http.WithLogLevel(zerolog.DebugLevel),
-		http.WithTimeout(time.Second*5),
-	)
-	if err != nil {
-		return nil, errors.WithMessage(err, "failed to create http client")
+	var endpoints []*http.Service
+	for _, addr := range strings.Split(opt.BeaconNodeAddr, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		httpClient, err := http.New(opt.Context,
+			// WithAddress supplies the address of the beacon node, in host:port format.
+			http.WithAddress(addr),
+			// LogLevel supplies the level of logging to carry out.
+			http.WithLogLevel(zerolog.DebugLevel),
+			http.WithTimeout(time.Second*5),
+		)
+		if err != nil {
+			// Unreachable endpoints are tolerated as long as at least one is available.
+			logger.Error("consensus client: failed to connect to endpoint", fields.Address(addr), zap.Error(err))
+			continue
+		}
+		endpoints = append(endpoints, httpClient.(*http.Service))
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("failed to create http client for any beacon node address")
+	}
+
+	var eth2Client Client = endpoints[0]
+	if len(endpoints) > 1 {
+		services := make([]eth2client.Service, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			services = append(services, endpoint)
+		}
+		// The multi client routes each request to the first active (synced) endpoint,
+		// and deactivates endpoints which fail to respond until they recover.
+		multiClient, err := multi.New(opt.Context,
+			multi.WithClients(services),
+			multi.WithLogLevel(zerolog.DebugLevel),
+			multi.WithTimeout(time.Second*5),
+		)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create multi client")
+		}
+		eth2Client = multiClient.(*multi.Service)
 	}
 
 	client := &goClient{
-		log:               logger,
-		ctx:               opt.Context,
-		network:           opt.Network,
-		client:            httpClient.(*http.Service),
-		graffiti:          opt.Graffiti,
-		gasLimit:          opt.GasLimit,
-		operatorID:        operatorID,
-		registrationCache: map[phase0.BLSPubKey]*api.VersionedSignedValidatorRegistration{},
+		log:                  logger,
+		ctx:                  opt.Context,
+		network:              opt.Network,
+		client:               eth2Client,
+		endpoints:            endpoints,
+		graffiti:             opt.Graffiti,
+		gasLimit:             opt.GasLimit,
+		operatorID:           operatorID,
+		registrationCache:    map[phase0.BLSPubKey]*api.VersionedSignedValidatorRegistration{},
+		attestationProtector: attestationProtector,
+		attesterDuties:       map[attesterDutyKey]phase0.BLSPubKey{},
 	}
 
 	// Get the node's version and client.
+	var err error
 	client.nodeVersion, err = client.client.NodeVersion(opt.Context)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get node version")
@@ -181,10 +240,11 @@ func New(logger *zap.Logger, opt beaconprotocol.Options, operatorID spectypes.Op
 	client.nodeClient = ParseNodeClient(client.nodeVersion)
 
 	logger.Info("consensus client connected",
-		fields.Name(httpClient.Name()),
-		fields.Address(httpClient.Address()),
+		fields.Name(client.client.Name()),
+		fields.Address(client.client.Address()),
 		zap.String("client", string(client.nodeClient)),
 		zap.String("version", client.nodeVersion),
+		zap.Int("endpoints", len(endpoints)),
 	)
 
 	// Start registration submitter.
@@ -199,30 +259,78 @@ func (gc *goClient) NodeClient() NodeClient {
 
 // Healthy returns if beacon node is currently healthy: responds to requests, not in the syncing state, not optimistic
 // (for optimistic see https://github.com/ethereum/consensus-specs/blob/dev/sync/optimistic.md#block-production).
+// With multiple endpoints, it's enough for the best endpoint to be healthy.
 func (gc *goClient) Healthy(ctx context.Context) error {
-	syncState, err := gc.client.NodeSyncing(ctx)
-	if err != nil {
+	if len(gc.endpoints) == 1 {
+		err := gc.endpointHealthy(ctx, gc.endpoints[0])
 		// TODO: get rid of global variable, pass metrics to goClient
+		metricsBeaconNodeStatus.Set(float64(endpointStatus(err)))
+		return err
+	}
+
+	var errs []string
+	for address, err := range gc.EndpointsHealthy(ctx) {
+		if err != nil {
+			gc.log.Warn("consensus client endpoint is not healthy", fields.Address(address), zap.Error(err))
+			errs = append(errs, fmt.Sprintf("%s: %s", address, err))
+		}
+	}
+	if len(errs) == len(gc.endpoints) {
 		metricsBeaconNodeStatus.Set(float64(statusUnknown))
+		return fmt.Errorf("no healthy endpoints: %s", strings.Join(errs, "; "))
+	}
+
+	metricsBeaconNodeStatus.Set(float64(statusOK))
+	return nil
+}
+
+// EndpointsHealthy returns the health of each beacon node endpoint by its address.
+func (gc *goClient) EndpointsHealthy(ctx context.Context) map[string]error {
+	health := make(map[string]error, len(gc.endpoints))
+	for _, endpoint := range gc.endpoints {
+		err := gc.endpointHealthy(ctx, endpoint)
+		metricsBeaconEndpointStatus.WithLabelValues(endpoint.Address()).Set(float64(endpointStatus(err)))
+		health[endpoint.Address()] = err
+	}
+	return health
+}
+
+func (gc *goClient) endpointHealthy(ctx context.Context, endpoint *http.Service) error {
+	syncState, err := endpoint.NodeSyncing(ctx)
+	if err != nil {
 		return err
 	}
 
 	// TODO: also check if syncState.ElOffline when github.com/attestantio/go-eth2-client supports it
-	metricsBeaconNodeStatus.Set(float64(statusSyncing))
 	if syncState == nil {
-		return errors.New("sync state is nil")
+		return errSyncStateNil
 	}
 	if syncState.IsSyncing {
-		return errors.New("syncing")
+		return errSyncing
 	}
 	if syncState.IsOptimistic {
-		return errors.New("optimistic")
+		return errOptimistic
 	}
-
-	metricsBeaconNodeStatus.Set(float64(statusOK))
 	return nil
 }
 
+var (
+	errSyncStateNil = errors.New("sync state is nil")
+	errSyncing      = errors.New("syncing")
+	errOptimistic   = errors.New("optimistic")
+)
+
+func endpointStatus(err error) beaconNodeStatus {
+	switch {
+	case err == nil:
+		return statusOK
+	case errors.Is(err, errSyncing), errors.Is(err, errOptimistic), errors.Is(err, errSyncStateNil):
+		return statusSyncing
+	default:
+		return statusUnknown
+	}
+}
+
 // GetBeaconNetwork returns the beacon network the node is on
 func (gc *goClient) GetBeaconNetwork() spectypes.BeaconNetwork {
 	return gc.network.BeaconNetwork
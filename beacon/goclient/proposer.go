@@ -184,10 +184,27 @@ func (gc *goClient) SubmitBeaconBlock(block *spec.VersionedBeaconBlock, sig phas
 	return gc.client.SubmitBeaconBlock(gc.ctx, signedBlock)
 }
 
+// ValidatorRegistrationSubmitter is implemented by beacon nodes which submit validator registrations as they were signed,
+// unlike SubmitValidatorRegistration, which registers the node-wide gas limit and the current epoch's timestamp.
+type ValidatorRegistrationSubmitter interface {
+	SubmitSignedValidatorRegistration(registration *eth2apiv1.ValidatorRegistration, sig phase0.BLSSignature) error
+}
+
 func (gc *goClient) SubmitValidatorRegistration(pubkey []byte, feeRecipient bellatrix.ExecutionAddress, sig phase0.BLSSignature) error {
 	return gc.updateBatchRegistrationCache(gc.createValidatorRegistration(pubkey, feeRecipient, sig))
 }
 
+// SubmitSignedValidatorRegistration caches the given validator registration for submission.
+func (gc *goClient) SubmitSignedValidatorRegistration(registration *eth2apiv1.ValidatorRegistration, sig phase0.BLSSignature) error {
+	return gc.updateBatchRegistrationCache(&api.VersionedSignedValidatorRegistration{
+		Version: spec.BuilderVersionV1,
+		V1: &eth2apiv1.SignedValidatorRegistration{
+			Message:   registration,
+			Signature: sig,
+		},
+	})
+}
+
 func (gc *goClient) SubmitProposalPreparation(feeRecipients map[phase0.ValidatorIndex]bellatrix.ExecutionAddress) error {
 	var preparations []*eth2apiv1.ProposalPreparation
 	for index, recipient := range feeRecipients {
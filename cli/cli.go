@@ -1,7 +1,11 @@
 package cli
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -24,7 +28,16 @@ func Execute(appName, version string) {
 	RootCmd.Short = appName
 	RootCmd.Version = version
 
-	if err := RootCmd.Execute(); err != nil {
+	// The context of the commands is cancelled on the first interrupt, letting them shut down,
+	// while another interrupt terminates the process right away.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := RootCmd.ExecuteContext(ctx); err != nil {
 		log.Fatal("failed to execute root command", zap.Error(err))
 	}
 }
@@ -33,4 +46,6 @@ func init() {
 	RootCmd.AddCommand(bootnode.StartBootNodeCmd)
 	RootCmd.AddCommand(operator.StartNodeCmd)
 	RootCmd.AddCommand(operator.GenerateDocCmd)
+	RootCmd.AddCommand(operator.DBCmd)
+	RootCmd.AddCommand(operator.RotateOperatorKeyCmd)
 }
@@ -0,0 +1,57 @@
+package flags
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/cliflag"
+)
+
+// Flag names.
+const (
+	dbPathFlag     = "db-path"
+	dbEngineFlag   = "db-engine"
+	ssvNetworkFlag = "ssv-network"
+	filePathFlag   = "file"
+)
+
+// AddDBPathFlag adds the database path flag to the command
+func AddDBPathFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, dbPathFlag, "./data/db", "Path to the node database", false)
+}
+
+// GetDBPathFlagValue gets the database path flag from the command
+func GetDBPathFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(dbPathFlag)
+}
+
+// AddDBEngineFlag adds the database storage engine flag to the command
+func AddDBEngineFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, dbEngineFlag, basedb.EngineBadger, "Storage engine of the node database (badger or pebble)", false)
+}
+
+// GetDBEngineFlagValue gets the database storage engine flag from the command
+func GetDBEngineFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(dbEngineFlag)
+}
+
+// AddSSVNetworkFlag adds the SSV network flag to the command
+func AddSSVNetworkFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, ssvNetworkFlag, networkconfig.Mainnet.Name, "SSV network of the node", false)
+}
+
+// GetSSVNetworkFlagValue gets the SSV network flag from the command
+func GetSSVNetworkFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(ssvNetworkFlag)
+}
+
+// AddFilePathFlag adds the file path flag to the command
+func AddFilePathFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, filePathFlag, "", "Path to the file", true)
+}
+
+// GetFilePathFlagValue gets the file path flag from the command
+func GetFilePathFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(filePathFlag)
+}
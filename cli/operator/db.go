@@ -0,0 +1,403 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	global_config "github.com/bloxapp/ssv/cli/config"
+	ibftstorage "github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/networkconfig"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/registry/snapshot"
+	"github.com/bloxapp/ssv/storage/backup"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+const (
+	backupFileFlag   = "file"
+	nodeAPIFlag      = "node-api"
+	targetEngineFlag = "engine"
+	targetDBPathFlag = "path"
+
+	retentionEpochsFlag  = "epochs"
+	retentionMaxSizeFlag = "max-size-mb"
+)
+
+// DBCmd is the command to manage the node's database
+var DBCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the database of SSV node",
+}
+
+// DBBackupCmd is the command to back up the node's database into an encrypted file
+var DBBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the database into a file encrypted with the operator key",
+	Long: `Back up the database into a file encrypted with the operator key.
+While the node is running, the database is locked by it, so the backup must be taken from the node's API with --node-api.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+		encryptionKey, err := backupEncryptionKey(logger)
+		if err != nil {
+			logger.Fatal("could not load operator key", zap.Error(err))
+		}
+		path, _ := cmd.Flags().GetString(backupFileFlag)
+		nodeAPI, _ := cmd.Flags().GetString(nodeAPIFlag)
+
+		err = writeFileAtomically(path, func(f *os.File) error {
+			if nodeAPI != "" {
+				return download(f, nodeAPI, "/v1/node/backup")
+			}
+			cfg.DBOptions.Ctx = cmd.Context()
+			db, err := kv.Open(logger, cfg.DBOptions)
+			if err != nil {
+				return errors.Wrap(err, "failed to open db, if the node is running use --node-api")
+			}
+			defer db.Close()
+			return backup.Backup(f, db, encryptionKey, networkConfig.Name)
+		})
+		if err != nil {
+			logger.Fatal("could not back up database", zap.Error(err))
+		}
+
+		// Make sure the backup can be restored with the operator key.
+		header, err := verifyBackup(path, encryptionKey)
+		if err != nil {
+			logger.Fatal("could not verify backup", zap.Error(err))
+		}
+		if header.Network != networkConfig.Name {
+			logger.Fatal("backup belongs to another network", fields.Network(header.Network))
+		}
+		logger.Info("database backed up", zap.String("file", path), fields.Network(header.Network))
+	},
+}
+
+// DBRestoreCmd is the command to restore the node's database from an encrypted backup
+var DBRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the database from a backup, the node must be stopped and the database empty",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+		encryptionKey, err := backupEncryptionKey(logger)
+		if err != nil {
+			logger.Fatal("could not load operator key", zap.Error(err))
+		}
+		path, _ := cmd.Flags().GetString(backupFileFlag)
+		f, err := os.Open(path)
+		if err != nil {
+			logger.Fatal("could not open backup", zap.Error(err))
+		}
+		defer f.Close()
+
+		cfg.DBOptions.Ctx = cmd.Context()
+		db, err := kv.Open(logger, cfg.DBOptions)
+		if err != nil {
+			logger.Fatal("could not open db", zap.Error(err))
+		}
+		defer db.Close()
+
+		header, err := backup.Restore(f, db, encryptionKey, networkConfig.Name)
+		if err != nil {
+			logger.Fatal("could not restore database", zap.Error(err))
+		}
+
+		if err := verifyRestoredConfig(logger, db, networkConfig.Name); err != nil {
+			if dropErr := db.DropAll(); dropErr != nil {
+				logger.Error("could not clear restored database", zap.Error(dropErr))
+			}
+			logger.Fatal("restored database is incompatible with the config", zap.Error(err))
+		}
+		logger.Info("database restored",
+			zap.String("file", path),
+			fields.Network(header.Network),
+			zap.Time("created", header.Created))
+	},
+}
+
+// DBExportSnapshotCmd is the command to export a snapshot of the registry, from which other nodes can be bootstrapped
+var DBExportSnapshotCmd = &cobra.Command{
+	Use:   "export-snapshot",
+	Short: "Export a snapshot of the registry signed with the operator key, from which other nodes can be bootstrapped",
+	Long: `Export a snapshot of the registry signed with the operator key, from which other nodes can be bootstrapped.
+While the node is running, the database is locked by it, so the snapshot must be taken from the node's API with --node-api.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+		path, _ := cmd.Flags().GetString(backupFileFlag)
+		nodeAPI, _ := cmd.Flags().GetString(nodeAPIFlag)
+
+		err = writeFileAtomically(path, func(f *os.File) error {
+			if nodeAPI != "" {
+				return download(f, nodeAPI, "/v1/node/snapshot")
+			}
+			operatorKey := loadOperatorKey(logger)
+			cfg.DBOptions.Ctx = cmd.Context()
+			db, err := kv.Open(logger, cfg.DBOptions)
+			if err != nil {
+				return errors.Wrap(err, "failed to open db, if the node is running use --node-api")
+			}
+			defer db.Close()
+			nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+			if err != nil {
+				return errors.Wrap(err, "failed to create node storage")
+			}
+			return snapshot.Export(f, nodeStorage, networkConfig.Name, operatorKey)
+		})
+		if err != nil {
+			logger.Fatal("could not export registry snapshot", zap.Error(err))
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			logger.Fatal("could not open registry snapshot", zap.Error(err))
+		}
+		defer f.Close()
+		header, _, err := snapshot.Read(f, networkConfig.Name, "")
+		if err != nil {
+			logger.Fatal("could not verify registry snapshot", zap.Error(err))
+		}
+		logger.Info("registry snapshot exported",
+			zap.String("file", path),
+			fields.Network(header.Network),
+			fields.BlockNumber(header.Block),
+			zap.String("signer", header.Signer))
+	},
+}
+
+// DBConvertCmd is the command to convert the node's database to another storage engine
+var DBConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert the database to another storage engine, the node must be stopped",
+	Long: `Convert the database to another storage engine, the node must be stopped.
+The database is copied into a new database of the given engine at the given path, which must be empty.
+Once converted, set the engine and path of the database in the config to the new ones.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		targetOptions := cfg.DBOptions
+		targetOptions.Ctx = cmd.Context()
+		targetOptions.Engine, _ = cmd.Flags().GetString(targetEngineFlag)
+		targetOptions.Path, _ = cmd.Flags().GetString(targetDBPathFlag)
+		if filepath.Clean(targetOptions.Path) == filepath.Clean(cfg.DBOptions.Path) {
+			logger.Fatal("the converted database must be at another path")
+		}
+
+		cfg.DBOptions.Ctx = cmd.Context()
+		source, err := kv.Open(logger, cfg.DBOptions)
+		if err != nil {
+			logger.Fatal("could not open db", zap.Error(err))
+		}
+		defer source.Close()
+		target, err := kv.Open(logger, targetOptions)
+		if err != nil {
+			logger.Fatal("could not open converted db", zap.Error(err))
+		}
+		defer target.Close()
+
+		start := time.Now()
+		count, err := kv.Copy(target, source)
+		if err != nil {
+			logger.Fatal("could not convert database", zap.Error(err))
+		}
+		logger.Info("database converted, set the engine and path of the database in the config to the converted one",
+			zap.String("engine", targetOptions.Engine),
+			zap.String("path", targetOptions.Path),
+			fields.Count(int(count)),
+			fields.Duration(start))
+	},
+}
+
+// DBPruneCmd is the command to prune the decided history of the node's database
+var DBPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Prune the decided history by the history retention policy, the node must be stopped",
+	Long: `Prune the decided history by the history retention policy, the node must be stopped.
+The retention policy of the config can be overridden for all roles with --epochs and --max-size-mb.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+
+		retention := cfg.HistoryRetention
+		if cmd.Flags().Changed(retentionEpochsFlag) || cmd.Flags().Changed(retentionMaxSizeFlag) {
+			retention.Epochs, _ = cmd.Flags().GetUint64(retentionEpochsFlag)
+			retention.MaxSizeMB, _ = cmd.Flags().GetUint64(retentionMaxSizeFlag)
+			retention.Roles = nil
+		}
+		if err := retention.Validate(); err != nil {
+			logger.Fatal("invalid history retention", zap.Error(err))
+		}
+		if !retention.Enabled() {
+			logger.Fatal("no history retention is configured, set it in the config or with --epochs or --max-size-mb")
+		}
+
+		cfg.DBOptions.Ctx = cmd.Context()
+		db, err := kv.Open(logger, cfg.DBOptions)
+		if err != nil {
+			logger.Fatal("could not open db", zap.Error(err))
+		}
+		defer db.Close()
+
+		start := time.Now()
+		stores := ibftstorage.NewStoresFromRoles(db, storageRoles...)
+		pruner := ibftstorage.NewPruner(logger, stores, networkConfig.Beacon, retention)
+		if err := pruner.Prune(networkConfig.Beacon.EstimatedCurrentSlot()); err != nil {
+			logger.Fatal("could not prune decided history", zap.Error(err))
+		}
+		if gc, ok := db.(basedb.GarbageCollector); ok {
+			if err := gc.FullGC(cmd.Context()); err != nil {
+				logger.Fatal("could not collect garbage", zap.Error(err))
+			}
+		}
+		logger.Info("decided history pruned", fields.Duration(start))
+	},
+}
+
+// backupEncryptionKey returns the key backups are encrypted with,
+// which is the encryption key of the operator private key, like the one of the key manager.
+func backupEncryptionKey(logger *zap.Logger) (string, error) {
+	return loadOperatorKey(logger).EncryptionKey()
+}
+
+// writeFileAtomically writes a file through a temporary file, so that a failed write doesn't leave a partial file.
+func writeFileAtomically(path string, write func(f *os.File) error) error {
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// download downloads a file from the given endpoint of the API of a running node,
+// authenticating with the configured admin token, if any.
+func download(w io.Writer, nodeAPI string, endpoint string) error {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(nodeAPI, "/")+endpoint, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create download request")
+	}
+	if cfg.SSVAPIAdminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.SSVAPIAdminToken)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to request download")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return errors.Wrap(err, "failed to download")
+	}
+	return nil
+}
+
+// verifyBackup decrypts a backup in full to make sure it's complete and encrypted with the given key.
+func verifyBackup(path string, encryptionKey string) (*backup.Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := backup.NewReader(f, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return &r.Header, nil
+}
+
+// verifyRestoredConfig checks that the config lock of a restored database matches the current config.
+func verifyRestoredConfig(logger *zap.Logger, db kv.Database, networkName string) error {
+	nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+	if err != nil {
+		return errors.Wrap(err, "failed to create node storage")
+	}
+	storedConfig, found, err := nodeStorage.GetConfig(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to get config")
+	}
+	if !found {
+		return nil
+	}
+	return storedConfig.EnsureSameWith(&operatorstorage.ConfigLock{
+		NetworkName:      networkName,
+		UsingLocalEvents: len(cfg.LocalEventsPath) != 0,
+	})
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, DBCmd)
+
+	DBBackupCmd.Flags().String(backupFileFlag, "", "Path to the backup file to write")
+	_ = DBBackupCmd.MarkFlagRequired(backupFileFlag)
+	DBBackupCmd.Flags().String(nodeAPIFlag, "", "URL of the SSV API of a running node to take the backup from (e.g. http://localhost:16000)")
+
+	DBRestoreCmd.Flags().String(backupFileFlag, "", "Path to the backup file to restore")
+	_ = DBRestoreCmd.MarkFlagRequired(backupFileFlag)
+
+	DBExportSnapshotCmd.Flags().String(backupFileFlag, "", "Path to the snapshot file to write")
+	_ = DBExportSnapshotCmd.MarkFlagRequired(backupFileFlag)
+	DBExportSnapshotCmd.Flags().String(nodeAPIFlag, "", "URL of the SSV API of a running node to take the snapshot from (e.g. http://localhost:16000)")
+
+	DBConvertCmd.Flags().String(targetEngineFlag, basedb.EnginePebble, "Storage engine to convert the database to (badger or pebble)")
+	DBConvertCmd.Flags().String(targetDBPathFlag, "", "Path of the converted database")
+	_ = DBConvertCmd.MarkFlagRequired(targetDBPathFlag)
+
+	DBPruneCmd.Flags().Uint64(retentionEpochsFlag, 0, "Number of recent epochs to keep the decided history of, or 0 to keep all of it")
+	DBPruneCmd.Flags().Uint64(retentionMaxSizeFlag, 0, "Size in megabytes to trim the decided history of each role to, or 0 for no limit")
+
+	DBCmd.AddCommand(DBBackupCmd, DBRestoreCmd, DBExportSnapshotCmd, DBConvertCmd, DBPruneCmd)
+}
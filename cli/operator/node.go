@@ -2,10 +2,8 @@ package operator
 
 import (
 	"context"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/base64"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
 	"net/http"
@@ -39,19 +37,27 @@ import (
 	"github.com/bloxapp/ssv/migrations"
 	"github.com/bloxapp/ssv/monitoring/metrics"
 	"github.com/bloxapp/ssv/monitoring/metricsreporter"
+	"github.com/bloxapp/ssv/monitoring/tracing"
 	"github.com/bloxapp/ssv/network"
 	p2pv1 "github.com/bloxapp/ssv/network/p2p"
+	networkpeers "github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/networkconfig"
 	"github.com/bloxapp/ssv/nodeprobe"
 	"github.com/bloxapp/ssv/operator"
 	"github.com/bloxapp/ssv/operator/duties/dutystore"
+	"github.com/bloxapp/ssv/operator/fee_recipient"
+	"github.com/bloxapp/ssv/operator/keys"
+	"github.com/bloxapp/ssv/operator/shadow"
 	"github.com/bloxapp/ssv/operator/slotticker"
 	operatorstorage "github.com/bloxapp/ssv/operator/storage"
 	"github.com/bloxapp/ssv/operator/validator"
 	"github.com/bloxapp/ssv/operator/validatorsmap"
 	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/runner/performance"
 	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/registry/snapshot"
 	registrystorage "github.com/bloxapp/ssv/registry/storage"
+	"github.com/bloxapp/ssv/storage/backup"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/kv"
 	"github.com/bloxapp/ssv/utils/commons"
@@ -60,8 +66,15 @@ import (
 )
 
 type KeyStore struct {
-	PrivateKeyFile string `yaml:"PrivateKeyFile" env:"PRIVATE_KEY_FILE" env-description:"Operator private key file"`
-	PasswordFile   string `yaml:"PasswordFile" env:"PASSWORD_FILE" env-description:"Password for operator private key file decryption"`
+	PrivateKeyFile string            `yaml:"PrivateKeyFile" env:"PRIVATE_KEY_FILE" env-description:"Operator private key file"`
+	PasswordFile   string            `yaml:"PasswordFile" env:"PASSWORD_FILE" env-description:"Password for operator private key file decryption"`
+	PKCS11         keys.PKCS11Config `yaml:"PKCS11"`
+}
+
+type RegistrySnapshot struct {
+	Path          string `yaml:"Path" env:"REGISTRY_SNAPSHOT_PATH" env-description:"Path to a registry snapshot to bootstrap the registry from, instead of syncing it from the beginning"`
+	TrustedSigner string `yaml:"TrustedSigner" env:"REGISTRY_SNAPSHOT_TRUSTED_SIGNER" env-description:"Public key of the operator whose signature the registry snapshot must bear"`
+	AllowUnsigned bool   `yaml:"AllowUnsigned" env:"REGISTRY_SNAPSHOT_ALLOW_UNSIGNED" env-description:"Bootstrap from the registry snapshot without verifying its signature, if no trusted signer is configured"`
 }
 
 type config struct {
@@ -79,11 +92,27 @@ type config struct {
 	WsAPIPort                  int                              `yaml:"WebSocketAPIPort" env:"WS_API_PORT" env-description:"Port to listen on for the websocket API."`
 	WithPing                   bool                             `yaml:"WithPing" env:"WITH_PING" env-description:"Whether to send websocket ping messages'"`
 	SSVAPIPort                 int                              `yaml:"SSVAPIPort" env:"SSV_API_PORT" env-description:"Port to listen on for the SSV API."`
+	SSVAPIAdminToken           string                           `yaml:"SSVAPIAdminToken" env:"SSV_API_ADMIN_TOKEN" env-description:"Bearer token required by the SSV API routes which expose or modify the node's internals, which are otherwise only served to localhost."`
 	LocalEventsPath            string                           `yaml:"LocalEventsPath" env:"EVENTS_PATH" env-description:"path to local events"`
+	RegistrySnapshot           RegistrySnapshot                 `yaml:"RegistrySnapshot"`
+	HistoryRetention           ibftstorage.RetentionOptions     `yaml:"HistoryRetention"`
+	ProposerConfigFile         string                           `yaml:"ProposerConfigFile" env:"PROPOSER_CONFIG_FILE" env-description:"Path to a YAML or JSON file overriding the fee recipient, gas limit and builder proposals per validator, reloaded when it changes"`
+	Tracing                    tracing.Options                  `yaml:"tracing"`
 }
 
 var cfg config
 
+// storageRoles are the roles which have a QBFT store.
+var storageRoles = []spectypes.BeaconRole{
+	spectypes.BNRoleAttester,
+	spectypes.BNRoleProposer,
+	spectypes.BNRoleAggregator,
+	spectypes.BNRoleSyncCommittee,
+	spectypes.BNRoleSyncCommitteeContribution,
+	spectypes.BNRoleValidatorRegistration,
+	spectypes.BNRoleVoluntaryExit,
+}
+
 var globalArgs global_config.Args
 
 var operatorNode operator.Node
@@ -104,6 +133,16 @@ var StartNodeCmd = &cobra.Command{
 
 		logger.Info(fmt.Sprintf("starting %v", commons.GetBuildData()))
 
+		shutdownTracing, err := tracing.Init(cmd.Context(), logger, cfg.Tracing)
+		if err != nil {
+			logger.Fatal("could not setup tracing", zap.Error(err))
+		}
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				logger.Error("could not shutdown tracing", zap.Error(err))
+			}
+		}()
+
 		metricsReporter := metricsreporter.New(
 			metricsreporter.WithLogger(logger),
 		)
@@ -124,12 +163,40 @@ var StartNodeCmd = &cobra.Command{
 
 		verifyConfig(logger, nodeStorage, networkConfig.Name, usingLocalEvents)
 
+		var proposerConfig *fee_recipient.ProposerConfig
+		if cfg.ProposerConfigFile != "" {
+			proposerConfig, err = fee_recipient.LoadProposerConfig(cfg.ProposerConfigFile)
+			if err != nil {
+				logger.Fatal("could not load proposer config", zap.Error(err))
+			}
+			cfg.SSVOptions.ValidatorOptions.ProposerConfig = proposerConfig
+		}
+
 		operatorKey, _, _ := nodeStorage.GetPrivateKey()
-		keyBytes := x509.MarshalPKCS1PrivateKey(operatorKey)
-		hashedKey, _ := rsaencryption.HashRsaKey(keyBytes)
-		keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, networkConfig, cfg.SSVOptions.ValidatorOptions.BuilderProposals, hashedKey)
+		if closer, ok := operatorKey.(io.Closer); ok {
+			// Logs out of the PKCS#11 token once the node stops.
+			defer func() {
+				if err := closer.Close(); err != nil {
+					logger.Error("could not close operator key", zap.Error(err))
+				}
+			}()
+		}
+		hashedKey, err := operatorKey.EncryptionKey()
 		if err != nil {
-			logger.Fatal("could not create new eth-key-manager signer", zap.Error(err))
+			logger.Fatal("could not get operator key encryption key", zap.Error(err))
+		}
+		var keyManager spectypes.KeyManager
+		if remoteSignerURL := cfg.SSVOptions.ValidatorOptions.RemoteSignerURL; remoteSignerURL != "" {
+			keyManager, err = ekm.NewRemoteKeyManager(logger, db, networkConfig, remoteSignerURL)
+			if err != nil {
+				logger.Fatal("could not create remote signer key manager", zap.Error(err))
+			}
+			logger.Info("using remote signer for share keys")
+		} else {
+			keyManager, err = ekm.NewETHKeyManagerSigner(logger, db, networkConfig, hashedKey)
+			if err != nil {
+				logger.Fatal("could not create new eth-key-manager signer", zap.Error(err))
+			}
 		}
 
 		cfg.P2pNetworkConfig.Ctx = cmd.Context()
@@ -148,7 +215,18 @@ var StartNodeCmd = &cobra.Command{
 		cfg.ConsensusClient.GasLimit = spectypes.DefaultGasLimit
 		cfg.ConsensusClient.Network = networkConfig.Beacon.GetNetwork()
 
-		consensusClient := setupConsensusClient(logger, operatorData.ID, slotTickerProvider)
+		// Submitted attestations are checked against the history of the share they were signed with.
+		attestationProtector := ekm.NewValidatorAttestationProtector(
+			ekm.NewSignerStorage(db, networkConfig.Beacon, logger),
+			func(validatorPubKey []byte) ([]byte, bool) {
+				share := nodeStorage.Shares().Get(nil, validatorPubKey)
+				if share == nil || len(share.SharePubKey) == 0 {
+					return nil, false
+				}
+				return share.SharePubKey, true
+			},
+		)
+		consensusClient := setupConsensusClient(logger, operatorData.ID, slotTickerProvider, attestationProtector)
 
 		executionClient, err := executionclient.New(
 			cmd.Context(),
@@ -195,17 +273,27 @@ var StartNodeCmd = &cobra.Command{
 
 		p2pNetwork := setupP2P(logger, db, metricsReporter)
 
+		// In shadow mode, duties are run against wrappers which only log what would have been
+		// broadcast or submitted, while the underlying clients are still set up and probed as usual.
+		var dutyNetwork network.P2PNetwork = p2pNetwork
+		dutyBeaconNode := consensusClient
+		if cfg.SSVOptions.ShadowMode {
+			logger.Warn("running in shadow mode, signed messages will not be broadcast nor submitted")
+			dutyNetwork = shadow.NewNetwork(logger, p2pNetwork)
+			dutyBeaconNode = shadow.NewBeaconNode(logger, consensusClient)
+		}
+
 		cfg.SSVOptions.Context = cmd.Context()
 		cfg.SSVOptions.DB = db
-		cfg.SSVOptions.BeaconNode = consensusClient
+		cfg.SSVOptions.BeaconNode = dutyBeaconNode
 		cfg.SSVOptions.ExecutionClient = executionClient
 		cfg.SSVOptions.Network = networkConfig
-		cfg.SSVOptions.P2PNetwork = p2pNetwork
+		cfg.SSVOptions.P2PNetwork = dutyNetwork
 		cfg.SSVOptions.ValidatorOptions.BeaconNetwork = networkConfig.Beacon.GetNetwork()
 		cfg.SSVOptions.ValidatorOptions.Context = cmd.Context()
 		cfg.SSVOptions.ValidatorOptions.DB = db
-		cfg.SSVOptions.ValidatorOptions.Network = p2pNetwork
-		cfg.SSVOptions.ValidatorOptions.Beacon = consensusClient
+		cfg.SSVOptions.ValidatorOptions.Network = dutyNetwork
+		cfg.SSVOptions.ValidatorOptions.Beacon = dutyBeaconNode
 		cfg.SSVOptions.ValidatorOptions.KeyManager = keyManager
 		cfg.SSVOptions.ValidatorOptions.ValidatorsMap = validatorsMap
 
@@ -214,6 +302,21 @@ var StartNodeCmd = &cobra.Command{
 		cfg.SSVOptions.ValidatorOptions.RegistryStorage = nodeStorage
 		cfg.SSVOptions.ValidatorOptions.GasLimit = cfg.ConsensusClient.GasLimit
 
+		feeRecipientCtrl := fee_recipient.NewController(&fee_recipient.ControllerOptions{
+			Ctx:                cmd.Context(),
+			BeaconClient:       dutyBeaconNode,
+			Network:            networkConfig,
+			ShareStorage:       nodeStorage.Shares(),
+			RecipientStorage:   nodeStorage,
+			OperatorData:       operatorData,
+			SlotTickerProvider: slotTickerProvider,
+			ProposerConfig:     cfg.SSVOptions.ValidatorOptions.ProposerConfig,
+		})
+		cfg.SSVOptions.FeeRecipientCtrl = feeRecipientCtrl
+		if proposerConfig != nil {
+			go proposerConfig.Watch(cmd.Context(), logger, fee_recipient.DefaultProposerConfigWatchInterval, feeRecipientCtrl.ProposerConfigReloaded)
+		}
+
 		if cfg.WsAPIPort != 0 {
 			ws := exporterapi.NewWsServer(cmd.Context(), nil, http.NewServeMux(), cfg.WithPing)
 			cfg.SSVOptions.WS = ws
@@ -223,15 +326,6 @@ var StartNodeCmd = &cobra.Command{
 
 		cfg.SSVOptions.ValidatorOptions.DutyRoles = []spectypes.BeaconRole{spectypes.BNRoleAttester} // TODO could be better to set in other place
 
-		storageRoles := []spectypes.BeaconRole{
-			spectypes.BNRoleAttester,
-			spectypes.BNRoleProposer,
-			spectypes.BNRoleAggregator,
-			spectypes.BNRoleSyncCommittee,
-			spectypes.BNRoleSyncCommitteeContribution,
-			spectypes.BNRoleValidatorRegistration,
-			spectypes.BNRoleVoluntaryExit,
-		}
 		storageMap := ibftstorage.NewStores()
 
 		for _, storageRole := range storageRoles {
@@ -239,7 +333,16 @@ var StartNodeCmd = &cobra.Command{
 		}
 
 		cfg.SSVOptions.ValidatorOptions.StorageMap = storageMap
+		if cfg.SSVOptions.ValidatorOptions.FullNode && cfg.HistoryRetention.Enabled() {
+			if err := cfg.HistoryRetention.Validate(); err != nil {
+				logger.Fatal("invalid history retention", zap.Error(err))
+			}
+			pruner := ibftstorage.NewPruner(logger, storageMap, networkConfig.Beacon, cfg.HistoryRetention)
+			go pruner.Start(cmd.Context())
+		}
+		dutyTracker := performance.NewTracker(networkConfig.Beacon, performance.DefaultRetention)
 		cfg.SSVOptions.ValidatorOptions.Metrics = metricsReporter
+		cfg.SSVOptions.ValidatorOptions.DutyTracker = dutyTracker
 		cfg.SSVOptions.Metrics = metricsReporter
 
 		validatorCtrl = validator.NewController(logger, cfg.SSVOptions.ValidatorOptions)
@@ -281,6 +384,8 @@ var StartNodeCmd = &cobra.Command{
 			metricsReporter,
 			networkConfig,
 			nodeStorage,
+			consensusClient,
+			feeRecipientCtrl,
 		)
 		nodeProber.AddNode("event syncer", eventSyncer)
 
@@ -298,6 +403,7 @@ var StartNodeCmd = &cobra.Command{
 			apiServer := apiserver.New(
 				logger,
 				fmt.Sprintf(":%d", cfg.SSVAPIPort),
+				cfg.SSVAPIAdminToken,
 				&handlers.Node{
 					// TODO: replace with narrower interface! (instead of accessing the entire PeersIndex)
 					ListenAddresses: []string{fmt.Sprintf("tcp://%s:%d", cfg.P2pNetworkConfig.HostAddress, cfg.P2pNetworkConfig.TCPPort), fmt.Sprintf("udp://%s:%d", cfg.P2pNetworkConfig.HostAddress, cfg.P2pNetworkConfig.UDPPort)},
@@ -305,10 +411,26 @@ var StartNodeCmd = &cobra.Command{
 					Network:         p2pNetwork.(p2pv1.HostProvider).Host().Network(),
 					TopicIndex:      p2pNetwork.(handlers.TopicIndex),
 					NodeProber:      nodeProber,
+					DBBackup: func(w io.Writer) error {
+						return backup.Backup(w, db, hashedKey, networkConfig.Name)
+					},
+					RegistrySnapshot: func(w io.Writer) error {
+						return snapshot.Export(w, nodeStorage, networkConfig.Name, operatorKey)
+					},
+					PendingTasks: func() ([]*operatorstorage.PendingTask, error) {
+						return nodeStorage.GetPendingTasks(nil)
+					},
+					PeerPolicy: p2pNetwork.(handlers.PeerPolicy),
 				},
 				&handlers.Validators{
 					Shares: nodeStorage.Shares(),
 				},
+				&handlers.Duties{
+					Tracker: dutyTracker,
+				},
+				&handlers.Exporter{
+					QBFTStores: storageMap,
+				},
 			)
 			go func() {
 				err := apiServer.Run()
@@ -381,8 +503,8 @@ func setupGlobal() (*zap.Logger, error) {
 	return zap.L(), nil
 }
 
-func setupDB(logger *zap.Logger, eth2Network beaconprotocol.Network) (*kv.BadgerDB, error) {
-	db, err := kv.New(logger, cfg.DBOptions)
+func setupDB(logger *zap.Logger, eth2Network beaconprotocol.Network) (kv.Database, error) {
+	db, err := kv.Open(logger, cfg.DBOptions)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to open db")
 	}
@@ -390,7 +512,7 @@ func setupDB(logger *zap.Logger, eth2Network beaconprotocol.Network) (*kv.Badger
 		if err := db.Close(); err != nil {
 			return errors.Wrap(err, "failed to close db")
 		}
-		db, err = kv.New(logger, cfg.DBOptions)
+		db, err = kv.Open(logger, cfg.DBOptions)
 		return errors.Wrap(err, "failed to reopen db")
 	}
 
@@ -406,6 +528,10 @@ func setupDB(logger *zap.Logger, eth2Network beaconprotocol.Network) (*kv.Badger
 	if applied == 0 {
 		return db, nil
 	}
+	if _, ok := db.(basedb.GarbageCollector); !ok {
+		// The storage engine reclaims disk space on its own.
+		return db, nil
+	}
 
 	// If migrations were applied, we run a full garbage collection cycle
 	// to reclaim any space that may have been freed up.
@@ -419,7 +545,7 @@ func setupDB(logger *zap.Logger, eth2Network beaconprotocol.Network) (*kv.Badger
 	// Run a long garbage collection cycle with a timeout.
 	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Minute)
 	defer cancel()
-	if err := db.FullGC(ctx); err != nil {
+	if err := db.(basedb.GarbageCollector).FullGC(ctx); err != nil {
 		return nil, errors.Wrap(err, "failed to collect garbage")
 	}
 
@@ -437,29 +563,10 @@ func setupOperatorStorage(logger *zap.Logger, db basedb.Database) (operatorstora
 	if err != nil {
 		logger.Fatal("failed to create node storage", zap.Error(err))
 	}
-	if cfg.KeyStore.PrivateKeyFile != "" {
-		encryptedJSON, err := os.ReadFile(cfg.KeyStore.PrivateKeyFile)
-		if err != nil {
-			log.Fatal("Error reading PEM file", zap.Error(err))
-		}
-		keyStorePassword, err := os.ReadFile(cfg.KeyStore.PasswordFile)
-		if err != nil {
-			log.Fatal("Error reading Password file", zap.Error(err))
-		}
-
-		privateKey, err := rsaencryption.ConvertEncryptedPemToPrivateKey(encryptedJSON, string(keyStorePassword))
-		if err != nil {
-			logger.Fatal("could not decrypt operator private key", zap.Error(err))
-		}
-		cfg.OperatorPrivateKey = rsaencryption.ExtractPrivateKey(privateKey)
-	}
-
-	cfg.P2pNetworkConfig.OperatorPrivateKey, err = decodePrivateKey(cfg.OperatorPrivateKey)
-	if err != nil {
-		logger.Fatal("could not decode operator private key", zap.Error(err))
-	}
+	operatorKey := loadOperatorKey(logger)
+	cfg.P2pNetworkConfig.OperatorSigner = operatorKey
 
-	operatorPubKey, err := nodeStorage.SetupPrivateKey(cfg.OperatorPrivateKey)
+	operatorPubKey, err := nodeStorage.SetupOperatorKey(operatorKey)
 	if err != nil {
 		logger.Fatal("could not setup operator private key", zap.Error(err))
 	}
@@ -482,18 +589,77 @@ func setupOperatorStorage(logger *zap.Logger, db basedb.Database) (operatorstora
 	return nodeStorage, operatorData
 }
 
-func decodePrivateKey(key string) (*rsa.PrivateKey, error) {
-	operatorKeyByte, err := base64.StdEncoding.DecodeString(key)
+// bootstrapFromSnapshot imports the registry snapshot and returns its block, from which syncing continues.
+func bootstrapFromSnapshot(logger *zap.Logger, eventHandler *eventhandler.EventHandler, networkConfig networkconfig.NetworkConfig) (uint64, error) {
+	if cfg.RegistrySnapshot.TrustedSigner == "" {
+		if !cfg.RegistrySnapshot.AllowUnsigned {
+			return 0, errors.New("no trusted signer is configured to verify the registry snapshot, AllowUnsigned must be set to bootstrap from it anyway")
+		}
+		logger.Warn("registry snapshot signature is not verified since no trusted signer is configured")
+	}
+
+	f, err := os.Open(cfg.RegistrySnapshot.Path)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	defer f.Close()
 
-	sk, err := rsaencryption.ConvertPemToPrivateKey(string(operatorKeyByte))
+	header, state, err := snapshot.Read(f, networkConfig.Name, cfg.RegistrySnapshot.TrustedSigner)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+	logger.Info("bootstrapping registry from snapshot",
+		zap.String("path", cfg.RegistrySnapshot.Path),
+		fields.BlockNumber(header.Block),
+		zap.Time("created", header.Created))
+	if err := eventHandler.ImportSnapshot(state); err != nil {
+		return 0, err
+	}
+	return header.Block, nil
+}
+
+// loadOperatorKey loads the operator private key from the PKCS#11 token if one is configured,
+// otherwise from the key store or the config.
+func loadOperatorKey(logger *zap.Logger) keys.OperatorPrivateKey {
+	if cfg.KeyStore.PKCS11.Module != "" {
+		operatorKey, err := keys.OpenPKCS11(cfg.KeyStore.PKCS11)
+		if err != nil {
+			logger.Fatal("could not open operator private key on PKCS#11 token", zap.Error(err))
+		}
+		return operatorKey
 	}
+	return loadOperatorKeyFile(logger)
+}
 
-	return sk, err
+// loadOperatorKeyFile loads the operator private key from the key store or the config.
+func loadOperatorKeyFile(logger *zap.Logger) keys.OperatorPrivateKey {
+	loadKeyStore(logger)
+	operatorKey, err := keys.PrivateKeyFromBase64(cfg.OperatorPrivateKey)
+	if err != nil {
+		logger.Fatal("could not decode operator private key", zap.Error(err))
+	}
+	return operatorKey
+}
+
+// loadKeyStore decrypts the operator private key from the key store, if one is configured.
+func loadKeyStore(logger *zap.Logger) {
+	if cfg.KeyStore.PrivateKeyFile == "" {
+		return
+	}
+	encryptedJSON, err := os.ReadFile(cfg.KeyStore.PrivateKeyFile)
+	if err != nil {
+		log.Fatal("Error reading PEM file", zap.Error(err))
+	}
+	keyStorePassword, err := os.ReadFile(cfg.KeyStore.PasswordFile)
+	if err != nil {
+		log.Fatal("Error reading Password file", zap.Error(err))
+	}
+
+	privateKey, err := rsaencryption.ConvertEncryptedPemToPrivateKey(encryptedJSON, string(keyStorePassword))
+	if err != nil {
+		logger.Fatal("could not decrypt operator private key", zap.Error(err))
+	}
+	cfg.OperatorPrivateKey = rsaencryption.ExtractPrivateKey(privateKey)
 }
 
 func setupSSVNetwork(logger *zap.Logger) (networkconfig.NetworkConfig, error) {
@@ -533,6 +699,7 @@ func setupP2P(logger *zap.Logger, db basedb.Database, mr metricsreporter.Metrics
 		logger.Fatal("failed to setup network private key", zap.Error(err))
 	}
 	cfg.P2pNetworkConfig.NetworkPrivateKey = netPrivKey
+	cfg.P2pNetworkConfig.KnownPeers = networkpeers.NewKnownPeersStore(db)
 
 	return p2pv1.New(logger, &cfg.P2pNetworkConfig, mr)
 }
@@ -541,8 +708,9 @@ func setupConsensusClient(
 	logger *zap.Logger,
 	operatorID spectypes.OperatorID,
 	slotTickerProvider slotticker.Provider,
+	attestationProtector ekm.AttestationProtector,
 ) beaconprotocol.BeaconNode {
-	cl, err := goclient.New(logger, cfg.ConsensusClient, operatorID, slotTickerProvider)
+	cl, err := goclient.New(logger, cfg.ConsensusClient, operatorID, slotTickerProvider, attestationProtector)
 	if err != nil {
 		logger.Fatal("failed to create beacon go-client", zap.Error(err),
 			fields.Address(cfg.ConsensusClient.BeaconNodeAddr))
@@ -560,6 +728,8 @@ func setupEventHandling(
 	metricsReporter metricsreporter.MetricsReporter,
 	networkConfig networkconfig.NetworkConfig,
 	nodeStorage operatorstorage.Storage,
+	consensusClient beaconprotocol.BeaconNode,
+	recipientNotifier eventhandler.RecipientNotifier,
 ) *eventsyncer.EventSyncer {
 	eventFilterer, err := executionClient.Filterer()
 	if err != nil {
@@ -581,23 +751,43 @@ func setupEventHandling(
 		eventhandler.WithFullNode(),
 		eventhandler.WithLogger(logger),
 		eventhandler.WithMetrics(metricsReporter),
+		eventhandler.WithRecipientNotifier(recipientNotifier),
 	)
 	if err != nil {
 		logger.Fatal("failed to setup event data handler", zap.Error(err))
 	}
 
+	syncerOptions := []eventsyncer.Option{
+		eventsyncer.WithLogger(logger),
+		eventsyncer.WithMetrics(metricsReporter),
+	}
+	if cfg.ExecutionClient.FollowFinalized {
+		finalityProvider, ok := consensusClient.(eventsyncer.FinalityProvider)
+		if !ok {
+			logger.Fatal("consensus client does not provide finality")
+		}
+		logger.Info("following finalized checkpoint for registry events")
+		syncerOptions = append(syncerOptions, eventsyncer.WithFinalizedCheckpoint(finalityProvider, networkConfig.Beacon, eventsyncer.DefaultMaxFinalityDelay))
+	}
+
 	eventSyncer := eventsyncer.New(
 		nodeStorage,
 		executionClient,
 		eventHandler,
-		eventsyncer.WithLogger(logger),
-		eventsyncer.WithMetrics(metricsReporter),
+		syncerOptions...,
 	)
 
 	fromBlock, found, err := nodeStorage.GetLastProcessedBlock(nil)
 	if err != nil {
 		logger.Fatal("syncing registry contract events failed, could not get last processed block", zap.Error(err))
 	}
+	if !found && cfg.RegistrySnapshot.Path != "" && len(cfg.LocalEventsPath) == 0 {
+		block, err := bootstrapFromSnapshot(logger, eventHandler, networkConfig)
+		if err != nil {
+			logger.Fatal("failed to bootstrap registry from snapshot", zap.Error(err))
+		}
+		fromBlock, found = new(big.Int).SetUint64(block), true
+	}
 	if !found {
 		fromBlock = networkConfig.RegistrySyncOffset
 	} else if fromBlock == nil {
@@ -614,9 +804,22 @@ func setupEventHandling(
 			logger.Fatal("failed to load local events", zap.Error(err))
 		}
 
-		if err := eventHandler.HandleLocalEvents(localEvents); err != nil {
+		if err := eventHandler.HandleLocalEvents(localEvents, false); err != nil {
 			logger.Fatal("error occurred while running event data handler", zap.Error(err))
 		}
+
+		// Execute the tasks left pending before a restart, and retry failed tasks in the background.
+		go eventHandler.RetryTasks(ctx)
+
+		// Handle the events appended to the file or scheduled for later in the background.
+		go func() {
+			err := localevents.Watch(ctx, logger, cfg.LocalEventsPath, localevents.DefaultWatchInterval, localEvents, func(events []localevents.Event) error {
+				return eventHandler.HandleLocalEvents(events, true)
+			})
+			if !errors.Is(err, context.Canceled) {
+				logger.Fatal("failed handling local events", zap.Error(err))
+			}
+		}()
 	} else {
 		// Sync historical registry events.
 		logger.Debug("syncing historical registry events", zap.Uint64("fromBlock", fromBlock.Uint64()))
@@ -658,6 +861,9 @@ func setupEventHandling(
 			zap.Int("my_validators", operatorValidators),
 		)
 
+		// Execute the tasks left pending before a restart, and retry failed tasks in the background.
+		go eventHandler.RetryTasks(ctx)
+
 		// Sync ongoing registry events in the background.
 		go func() {
 			err = eventSyncer.SyncOngoing(ctx, fromBlock.Uint64())
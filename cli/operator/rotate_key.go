@@ -0,0 +1,277 @@
+package operator
+
+import (
+	"log"
+	"os"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	global_config "github.com/bloxapp/ssv/cli/config"
+	"github.com/bloxapp/ssv/ekm"
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/operator/keys"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/storage/kv"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+const (
+	newKeyFlag          = "new-key"
+	newKeyFileFlag      = "new-key-file"
+	newPasswordFileFlag = "new-password-file"
+	toPKCS11Flag        = "to-pkcs11"
+)
+
+// RotateOperatorKeyCmd is the command to switch the node to a new operator key
+var RotateOperatorKeyCmd = &cobra.Command{
+	Use:   "rotate-operator-key",
+	Short: "Switch the node to a new operator key, the node must be stopped",
+	Long: `Switch the node to a new operator key, the node must be stopped.
+The current key is read from the config, and the new key from --new-key-file and --new-password-file, or --new-key.
+With --to-pkcs11, the new key is the one on the PKCS#11 token of the config instead, and the current key is read
+from the key store or OperatorPrivateKey of the config.
+
+If the new key is the current key imported into the token, the node remains the same operator,
+and the key manager storage is re-encrypted since the key manager storage of a token key is encrypted differently.
+
+Otherwise, the new key must be registered on-chain to another operator of the same owner, and the node migrates
+to that operator. Share keys are encrypted on-chain for the key of their operator, so the shares of the current
+operator can't be moved to the new one, and they're exposed anyway if the current key is compromised.
+Instead, the validators must be registered again with the new operator in place of the current one,
+which splits their keys into new shares. The rotation is rejected until the node has synced validators
+of the new operator. It then drops the registry and the share keys of the key manager, keeping the slashing
+protection data, and the node syncs the registry again from the contract as the new operator once it's started.
+Validators which still have the current operator in their committee are no longer operated by the node.
+
+The database is left unchanged if the rotation fails before the registry is dropped.
+Once rotated, replace the operator key in the config with the new one before starting the node.
+Backups taken before the rotation remain encrypted with the current key.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+
+		var currentKey, newKey keys.OperatorPrivateKey
+		if toPKCS11, _ := cmd.Flags().GetBool(toPKCS11Flag); toPKCS11 {
+			if cfg.KeyStore.PKCS11.Module == "" {
+				logger.Fatal("no PKCS#11 token is configured")
+			}
+			currentKey = loadOperatorKeyFile(logger)
+			pkcs11Key, err := keys.OpenPKCS11(cfg.KeyStore.PKCS11)
+			if err != nil {
+				logger.Fatal("could not open new operator key on PKCS#11 token", zap.Error(err))
+			}
+			defer pkcs11Key.Close()
+			newKey = pkcs11Key
+		} else {
+			currentKey = loadOperatorKey(logger)
+			newKey, err = loadNewOperatorKey(cmd)
+			if err != nil {
+				logger.Fatal("could not load new operator key", zap.Error(err))
+			}
+		}
+		currentEncryptionKey, err := currentKey.EncryptionKey()
+		if err != nil {
+			logger.Fatal("could not get current encryption key", zap.Error(err))
+		}
+		newEncryptionKey, err := newKey.EncryptionKey()
+		if err != nil {
+			logger.Fatal("could not get new encryption key", zap.Error(err))
+		}
+		if newEncryptionKey == currentEncryptionKey {
+			logger.Fatal("the new operator key is the current one")
+		}
+
+		cfg.DBOptions.Ctx = cmd.Context()
+		db, err := kv.Open(logger, cfg.DBOptions)
+		if err != nil {
+			logger.Fatal("could not open db, the node must be stopped", zap.Error(err))
+		}
+		defer db.Close()
+
+		if err := verifyRestoredConfig(logger, db, networkConfig.Name); err != nil {
+			logger.Fatal("database is incompatible with the config", zap.Error(err))
+		}
+		nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+		if err != nil {
+			logger.Fatal("could not create node storage", zap.Error(err))
+		}
+		// Fails unless the current key is the one the database was set up with.
+		currentPubKey, err := nodeStorage.SetupOperatorKey(currentKey)
+		if err != nil {
+			logger.Fatal("could not verify current operator key", zap.Error(err))
+		}
+
+		currentOperator, found, err := nodeStorage.GetOperatorDataByPubKey(nil, currentPubKey)
+		if err != nil {
+			logger.Fatal("could not get operator of current key", zap.Error(err))
+		}
+		if !found {
+			logger.Fatal("current operator key is not registered on-chain")
+		}
+		newPubKey, err := keys.Base64PublicKey(newKey)
+		if err != nil {
+			logger.Fatal("could not extract new operator public key", zap.Error(err))
+		}
+		newOperator, found, err := nodeStorage.GetOperatorDataByPubKey(nil, []byte(newPubKey))
+		if err != nil {
+			logger.Fatal("could not get operator of new key", zap.Error(err))
+		}
+		if !found {
+			logger.Fatal("new operator key is not registered on-chain, or the node hasn't synced its registration yet")
+		}
+		if newOperator.OwnerAddress != currentOperator.OwnerAddress {
+			logger.Fatal("operator of new key has another owner",
+				fields.Owner(newOperator.OwnerAddress),
+				zap.String("current_owner", currentOperator.OwnerAddress.Hex()))
+		}
+		logger = logger.With(
+			zap.Uint64("current_operator_id", currentOperator.ID),
+			zap.Uint64("new_operator_id", newOperator.ID))
+
+		signerStorage := ekm.NewSignerStorage(db, networkConfig.Beacon, logger)
+		if err := signerStorage.SetEncryptionKey(currentEncryptionKey); err != nil {
+			logger.Fatal("could not set current encryption key", zap.Error(err))
+		}
+
+		if newOperator.ID != currentOperator.ID {
+			migrateOperator(logger, nodeStorage, signerStorage, currentOperator.ID, newOperator.ID, newKey)
+			return
+		}
+
+		txn := nodeStorage.Begin()
+		defer txn.Discard()
+
+		accounts, err := signerStorage.ListAccountsTxn(txn)
+		if err != nil {
+			logger.Fatal("could not decrypt share keys with current operator key", zap.Error(err))
+		}
+		if err := signerStorage.SetEncryptionKey(newEncryptionKey); err != nil {
+			logger.Fatal("could not set new encryption key", zap.Error(err))
+		}
+		for _, account := range accounts {
+			if err := signerStorage.SaveAccountTxn(txn, account); err != nil {
+				logger.Fatal("could not re-encrypt share key", zap.String("account", account.ID().String()), zap.Error(err))
+			}
+		}
+		if err := nodeStorage.RotatePrivateKey(txn, newKey); err != nil {
+			logger.Fatal("could not save new operator key", zap.Error(err))
+		}
+		if err := txn.Commit(); err != nil {
+			logger.Fatal("could not commit rotation", zap.Error(err))
+		}
+
+		// Make sure the share keys can be decrypted with the new key.
+		rotatedStorage := ekm.NewSignerStorage(db, networkConfig.Beacon, logger)
+		if err := rotatedStorage.SetEncryptionKey(newEncryptionKey); err != nil {
+			logger.Fatal("could not set new encryption key", zap.Error(err))
+		}
+		rotated, err := rotatedStorage.ListAccounts()
+		if err != nil {
+			logger.Fatal("could not decrypt share keys with new operator key", zap.Error(err))
+		}
+		if len(rotated) != len(accounts) {
+			logger.Fatal("unexpected number of share keys after rotation",
+				zap.Int("expected", len(accounts)),
+				zap.Int("actual", len(rotated)))
+		}
+
+		logger.Info("operator key rotated, replace the operator key in the config with the new one before starting the node",
+			zap.Int("share_keys", len(accounts)))
+	},
+}
+
+// migrateOperator switches the node to the operator of the new key, which is another operator than the current one.
+// It's rejected unless the node has synced validators of the new operator, which the node would otherwise not operate.
+func migrateOperator(
+	logger *zap.Logger,
+	nodeStorage operatorstorage.Storage,
+	signerStorage ekm.Storage,
+	currentOperatorID, newOperatorID spectypes.OperatorID,
+	newKey keys.OperatorPrivateKey,
+) {
+	var newValidators, currentValidators int
+	for _, share := range nodeStorage.Shares().List(nil) {
+		for _, operator := range share.Committee {
+			switch operator.OperatorID {
+			case newOperatorID:
+				newValidators++
+			case currentOperatorID:
+				currentValidators++
+			}
+		}
+	}
+	if newValidators == 0 {
+		logger.Fatal("no validators have the new operator in their committee, so the node would operate none after the rotation: " +
+			"register the validators again with the new operator in place of the current one, " +
+			"and run the rotation once the node has synced their registration")
+	}
+	if currentValidators > 0 {
+		logger.Warn("validators which still have the current operator in their committee won't be operated by the node after the rotation",
+			zap.Int("validators", currentValidators))
+	}
+
+	// The registry is synced again by the node as the new operator, decrypting the shares of its validators with the new key.
+	if err := nodeStorage.DropRegistryData(); err != nil {
+		logger.Fatal("could not drop registry data", zap.Error(err))
+	}
+	// The share keys of the current operator are dropped, while their slashing protection data is kept.
+	if err := signerStorage.DropRegistryData(); err != nil {
+		logger.Fatal("could not drop share keys", zap.Error(err))
+	}
+	if err := nodeStorage.RotatePrivateKey(nil, newKey); err != nil {
+		logger.Fatal("could not save new operator key", zap.Error(err))
+	}
+
+	logger.Info("migrated to the operator of the new key, replace the operator key in the config with the new one before starting the node, "+
+		"which then syncs the registry from the contract",
+		zap.Int("validators", newValidators))
+}
+
+// loadNewOperatorKey returns the new operator key, either decrypted from a key store file or given as is.
+func loadNewOperatorKey(cmd *cobra.Command) (keys.OperatorPrivateKey, error) {
+	newKey, _ := cmd.Flags().GetString(newKeyFlag)
+	keyFile, _ := cmd.Flags().GetString(newKeyFileFlag)
+	if (newKey == "") == (keyFile == "") {
+		return nil, errors.Errorf("either --%s or --%s must be given", newKeyFileFlag, newKeyFlag)
+	}
+	if newKey != "" {
+		return keys.PrivateKeyFromBase64(newKey)
+	}
+
+	passwordFile, _ := cmd.Flags().GetString(newPasswordFileFlag)
+	if passwordFile == "" {
+		return nil, errors.Errorf("--%s must be given with --%s", newPasswordFileFlag, newKeyFileFlag)
+	}
+	encryptedJSON, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read key file")
+	}
+	password, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read password file")
+	}
+	privateKey, err := rsaencryption.ConvertEncryptedPemToPrivateKey(encryptedJSON, string(password))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt key file")
+	}
+	return keys.PrivateKeyFromRSA(privateKey), nil
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, RotateOperatorKeyCmd)
+
+	RotateOperatorKeyCmd.Flags().String(newKeyFileFlag, "", "Path to the key store file of the new operator key")
+	RotateOperatorKeyCmd.Flags().String(newPasswordFileFlag, "", "Path to the password file of the key store of the new operator key")
+	RotateOperatorKeyCmd.Flags().String(newKeyFlag, "", "New operator key, base64 encoded like OperatorPrivateKey")
+	RotateOperatorKeyCmd.Flags().Bool(toPKCS11Flag, false, "Use the operator key on the PKCS#11 token of the config as the new key")
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/cli/flags"
+	"github.com/bloxapp/ssv/ekm"
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/networkconfig"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+// slashingProtectionCmd is the parent command of the EIP-3076 slashing protection interchange commands
+var slashingProtectionCmd = &cobra.Command{
+	Use:   "slashing-protection",
+	Short: "Imports or exports slashing protection data in the EIP-3076 interchange format",
+}
+
+// exportSlashingProtectionCmd is the command to export slashing protection data of the node's validators
+var exportSlashingProtectionCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports slashing protection data of the node's validators to an EIP-3076 JSON file",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, db, networkConfig, done := setupSlashingProtection(cmd)
+		defer done()
+
+		filePath, err := flags.GetFilePathFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get file flag value", zap.Error(err))
+		}
+
+		exported, err := exportSlashingProtection(logger, db, networkConfig, filePath)
+		if err != nil {
+			logger.Fatal("failed to export slashing protection", zap.Error(err))
+		}
+
+		fmt.Println("Exported slashing protection of", exported, "validators to", filePath)
+	},
+}
+
+// importSlashingProtectionCmd is the command to import slashing protection data into the node's database
+var importSlashingProtectionCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports slashing protection data of the node's validators from an EIP-3076 JSON file, keeping the more conservative data",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, db, networkConfig, done := setupSlashingProtection(cmd)
+		defer done()
+
+		filePath, err := flags.GetFilePathFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get file flag value", zap.Error(err))
+		}
+
+		imported, total, err := importSlashingProtection(logger, db, networkConfig, filePath)
+		if err != nil {
+			logger.Fatal("failed to import slashing protection", zap.Error(err))
+		}
+
+		fmt.Println("Imported slashing protection of", imported, "validators from", filePath)
+		if skipped := total - imported; skipped > 0 {
+			fmt.Println("Skipped", skipped, "validators which have no share in the database")
+		}
+	},
+}
+
+// exportSlashingProtection writes the slashing protection data of the node's validators to the given file,
+// and returns the number of exported validators.
+func exportSlashingProtection(logger *zap.Logger, db basedb.Database, networkConfig networkconfig.NetworkConfig, filePath string) (int, error) {
+	genesisValidatorsRoot, ok := networkConfig.GenesisValidatorsRoot()
+	if !ok {
+		return 0, fmt.Errorf("genesis validators root is unknown for network %s", networkConfig.Name)
+	}
+	shareKeys, err := loadShareKeys(logger, db)
+	if err != nil {
+		return 0, err
+	}
+
+	signerStorage := ekm.NewSignerStorage(db, networkConfig.Beacon, logger)
+	interchange, err := ekm.ExportSlashingProtection(signerStorage, genesisValidatorsRoot, shareKeys)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := json.MarshalIndent(interchange, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal slashing protection: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write slashing protection file: %w", err)
+	}
+	return len(interchange.Data), nil
+}
+
+// importSlashingProtection imports the slashing protection data of the node's validators from the given file
+// in a single transaction. It returns the number of imported validators and of validators in the file.
+func importSlashingProtection(logger *zap.Logger, db basedb.Database, networkConfig networkconfig.NetworkConfig, filePath string) (int, int, error) {
+	genesisValidatorsRoot, ok := networkConfig.GenesisValidatorsRoot()
+	if !ok {
+		return 0, 0, fmt.Errorf("genesis validators root is unknown for network %s", networkConfig.Name)
+	}
+	shareKeys, err := loadShareKeys(logger, db)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read slashing protection file: %w", err)
+	}
+	var interchange ekm.Interchange
+	if err := json.Unmarshal(data, &interchange); err != nil {
+		return 0, 0, fmt.Errorf("failed to unmarshal slashing protection file: %w", err)
+	}
+
+	signerStorage := ekm.NewSignerStorage(db, networkConfig.Beacon, logger)
+	var imported int
+	err = db.Update(func(txn basedb.Txn) error {
+		imported, err = ekm.ImportSlashingProtection(txn, signerStorage, &interchange, genesisValidatorsRoot, shareKeys)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return imported, len(interchange.Data), nil
+}
+
+// loadShareKeys returns the share public keys of the node's validators.
+func loadShareKeys(logger *zap.Logger, db basedb.Database) (ekm.ShareKeys, error) {
+	nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create node storage: %w", err)
+	}
+	shareKeys := ekm.ShareKeys{}
+	for _, share := range nodeStorage.Shares().List(nil) {
+		shareKeys[phase0.BLSPubKey(share.ValidatorPubKey)] = phase0.BLSPubKey(share.SharePubKey)
+	}
+	return shareKeys, nil
+}
+
+// setupSlashingProtection opens the node's database for the configured network.
+// The network must match the network the database was created with.
+func setupSlashingProtection(cmd *cobra.Command) (*zap.Logger, basedb.Database, networkconfig.NetworkConfig, func()) {
+	if err := logging.SetGlobalLogger("info", "capital", "console", nil); err != nil {
+		log.Fatal(err)
+	}
+	logger := zap.L().Named(logging.NameSlashingProtection)
+
+	networkName, err := flags.GetSSVNetworkFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get network flag value", zap.Error(err))
+	}
+	networkConfig, err := networkconfig.GetNetworkConfigByName(networkName)
+	if err != nil {
+		logger.Fatal("failed to get network config", zap.Error(err))
+	}
+
+	dbPath, err := flags.GetDBPathFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get db path flag value", zap.Error(err))
+	}
+	dbEngine, err := flags.GetDBEngineFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get db engine flag value", zap.Error(err))
+	}
+	db, err := kv.Open(logger, basedb.Options{
+		Ctx:    cmd.Context(),
+		Engine: dbEngine,
+		Path:   dbPath,
+	})
+	if err != nil {
+		logger.Fatal("failed to open db", zap.Error(err))
+	}
+
+	nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+	if err != nil {
+		logger.Fatal("failed to create node storage", zap.Error(err))
+	}
+	storedConfig, found, err := nodeStorage.GetConfig(nil)
+	if err != nil {
+		logger.Fatal("failed to get stored config", zap.Error(err))
+	}
+	if found && storedConfig.NetworkName != networkConfig.Name {
+		logger.Fatal("database belongs to a different network",
+			zap.String("db_network", storedConfig.NetworkName),
+			zap.String("network", networkConfig.Name))
+	}
+
+	return logger, db, networkConfig, func() {
+		if err := db.Close(); err != nil {
+			logger.Error("failed to close db", zap.Error(err))
+		}
+	}
+}
+
+func init() {
+	flags.AddDBPathFlag(slashingProtectionCmd)
+	flags.AddDBEngineFlag(slashingProtectionCmd)
+	flags.AddSSVNetworkFlag(slashingProtectionCmd)
+	flags.AddFilePathFlag(slashingProtectionCmd)
+
+	slashingProtectionCmd.AddCommand(exportSlashingProtectionCmd)
+	slashingProtectionCmd.AddCommand(importSlashingProtectionCmd)
+	RootCmd.AddCommand(slashingProtectionCmd)
+}
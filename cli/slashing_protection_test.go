@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/ekm"
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/networkconfig"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+func TestSlashingProtectionRoundTrip(t *testing.T) {
+	logger := logging.TestLogger(t)
+	networkConfig := networkconfig.TestNetwork
+	validatorPubKey := phase0.BLSPubKey{1}
+	sharePubKey := phase0.BLSPubKey{2}
+
+	newDB := func(sharePubKey phase0.BLSPubKey) basedb.Database {
+		db, err := kv.NewInMemory(logger, basedb.Options{})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+		require.NoError(t, err)
+		require.NoError(t, nodeStorage.Shares().Save(nil, &types.SSVShare{
+			Share: spectypes.Share{
+				ValidatorPubKey: validatorPubKey[:],
+				SharePubKey:     sharePubKey[:],
+			},
+		}))
+		return db
+	}
+
+	source := newDB(sharePubKey)
+	sourceStorage := ekm.NewSignerStorage(source, networkConfig.Beacon, logger)
+	require.NoError(t, sourceStorage.SaveHighestProposal(sharePubKey[:], 100))
+	require.NoError(t, sourceStorage.CheckAndSaveAttestation(sharePubKey[:], &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: 10},
+		Target: &phase0.Checkpoint{Epoch: 11},
+	}, phase0.Root{3}))
+	// Data of removed validators isn't exported.
+	removedSharePubKey := phase0.BLSPubKey{9}
+	require.NoError(t, sourceStorage.SaveHighestProposal(removedSharePubKey[:], 100))
+
+	filePath := filepath.Join(t.TempDir(), "slashing_protection.json")
+	exported, err := exportSlashingProtection(logger, source, networkConfig, filePath)
+	require.NoError(t, err)
+	require.Equal(t, 1, exported)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	var interchange ekm.Interchange
+	require.NoError(t, json.Unmarshal(data, &interchange))
+	require.Len(t, interchange.Data, 1)
+	require.Equal(t, validatorPubKey.String(), interchange.Data[0].PubKey)
+
+	t.Run("import to another share", func(t *testing.T) {
+		otherSharePubKey := phase0.BLSPubKey{4}
+		target := newDB(otherSharePubKey)
+
+		imported, total, err := importSlashingProtection(logger, target, networkConfig, filePath)
+		require.NoError(t, err)
+		require.Equal(t, 1, imported)
+		require.Equal(t, 1, total)
+
+		targetStorage := ekm.NewSignerStorage(target, networkConfig.Beacon, logger)
+		slot, found, err := targetStorage.RetrieveHighestProposal(otherSharePubKey[:])
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, phase0.Slot(100), slot)
+		history, err := targetStorage.ListAttestationHistory(otherSharePubKey[:])
+		require.NoError(t, err)
+		require.Equal(t, []*ekm.AttestationRecord{{SourceEpoch: 10, TargetEpoch: 11, SigningRoot: phase0.Root{3}}}, history)
+
+		// The imported data round-trips.
+		reexported := filepath.Join(t.TempDir(), "slashing_protection.json")
+		_, err = exportSlashingProtection(logger, target, networkConfig, reexported)
+		require.NoError(t, err)
+		reexportedData, err := os.ReadFile(reexported)
+		require.NoError(t, err)
+		require.JSONEq(t, string(data), string(reexportedData))
+	})
+
+	t.Run("failed import changes nothing", func(t *testing.T) {
+		target := newDB(sharePubKey)
+
+		invalid := interchange
+		invalid.Data = append([]*ekm.InterchangeData{}, interchange.Data...)
+		invalid.Data = append(invalid.Data, &ekm.InterchangeData{
+			PubKey:             interchange.Data[0].PubKey,
+			SignedAttestations: []*ekm.InterchangeAttestation{{SourceEpoch: "20", TargetEpoch: "x"}},
+		})
+		invalidData, err := json.Marshal(invalid)
+		require.NoError(t, err)
+		invalidPath := filepath.Join(t.TempDir(), "invalid.json")
+		require.NoError(t, os.WriteFile(invalidPath, invalidData, 0600))
+
+		_, _, err = importSlashingProtection(logger, target, networkConfig, invalidPath)
+		require.Error(t, err)
+
+		targetStorage := ekm.NewSignerStorage(target, networkConfig.Beacon, logger)
+		pubKeys, err := targetStorage.ListSlashingProtectionPubKeys()
+		require.NoError(t, err)
+		require.Empty(t, pubKeys)
+	})
+}
@@ -0,0 +1,203 @@
+package ekm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// attestationHistoryRetention is the number of epochs (counted back from the highest recorded target)
+// for which attestation records are kept. Attestations targeting epochs older than the oldest retained
+// record are refused, so pruning never weakens the protection.
+const attestationHistoryRetention = phase0.Epoch(4096)
+
+// AttestationRecord is a single entry of a public key's attestation history.
+type AttestationRecord struct {
+	SourceEpoch phase0.Epoch
+	TargetEpoch phase0.Epoch
+	SigningRoot phase0.Root
+}
+
+const attestationRecordSize = 8 + 8 + 32
+
+// Encode returns the binary representation of the record.
+func (r *AttestationRecord) Encode() []byte {
+	data := make([]byte, attestationRecordSize)
+	binary.BigEndian.PutUint64(data[0:8], uint64(r.SourceEpoch))
+	binary.BigEndian.PutUint64(data[8:16], uint64(r.TargetEpoch))
+	copy(data[16:], r.SigningRoot[:])
+	return data
+}
+
+// Decode parses the binary representation of the record.
+func (r *AttestationRecord) Decode(data []byte) error {
+	if len(data) != attestationRecordSize {
+		return fmt.Errorf("invalid attestation record size: %d", len(data))
+	}
+	r.SourceEpoch = phase0.Epoch(binary.BigEndian.Uint64(data[0:8]))
+	r.TargetEpoch = phase0.Epoch(binary.BigEndian.Uint64(data[8:16]))
+	copy(r.SigningRoot[:], data[16:])
+	return nil
+}
+
+// attestationWatermarks bound the attestation history of a public key,
+// so that attestations of new epochs can be checked without loading the history.
+type attestationWatermarks struct {
+	// MinSource and MinTarget are the lowest source and target epochs an attestation may have,
+	// since the records it could conflict with below them are unknown or were pruned.
+	MinSource phase0.Epoch
+	MinTarget phase0.Epoch
+	// MaxSource and MaxTarget are the highest source and target epochs of the history.
+	MaxSource phase0.Epoch
+	MaxTarget phase0.Epoch
+}
+
+const attestationWatermarksSize = 4 * 8
+
+// newAttestationWatermarks returns the watermarks of the given history, or nil if it's empty.
+func newAttestationWatermarks(history []*AttestationRecord) *attestationWatermarks {
+	var w *attestationWatermarks
+	for _, record := range history {
+		w = w.add(record)
+	}
+	return w
+}
+
+// add returns the watermarks including the given record.
+func (w *attestationWatermarks) add(record *AttestationRecord) *attestationWatermarks {
+	if w == nil {
+		return &attestationWatermarks{
+			MinTarget: record.TargetEpoch,
+			MaxSource: record.SourceEpoch,
+			MaxTarget: record.TargetEpoch,
+		}
+	}
+	added := *w
+	if record.TargetEpoch < added.MinTarget {
+		added.MinTarget = record.TargetEpoch
+	}
+	if record.SourceEpoch > added.MaxSource {
+		added.MaxSource = record.SourceEpoch
+	}
+	if record.TargetEpoch > added.MaxTarget {
+		added.MaxTarget = record.TargetEpoch
+	}
+	return &added
+}
+
+// precedes returns true if the given attestation is newer than the whole history, so it can't conflict with it:
+// no record has its target, surrounds it with a higher target, or is surrounded by it with a lower source.
+func (w *attestationWatermarks) precedes(data *phase0.AttestationData) bool {
+	return data.Source.Epoch >= w.MaxSource && data.Target.Epoch > w.MaxTarget
+}
+
+// Encode returns the binary representation of the watermarks.
+func (w *attestationWatermarks) Encode() []byte {
+	data := make([]byte, attestationWatermarksSize)
+	binary.BigEndian.PutUint64(data[0:8], uint64(w.MinSource))
+	binary.BigEndian.PutUint64(data[8:16], uint64(w.MinTarget))
+	binary.BigEndian.PutUint64(data[16:24], uint64(w.MaxSource))
+	binary.BigEndian.PutUint64(data[24:32], uint64(w.MaxTarget))
+	return data
+}
+
+// Decode parses the binary representation of the watermarks.
+func (w *attestationWatermarks) Decode(data []byte) error {
+	if len(data) != attestationWatermarksSize {
+		return fmt.Errorf("invalid attestation watermarks size: %d", len(data))
+	}
+	w.MinSource = phase0.Epoch(binary.BigEndian.Uint64(data[0:8]))
+	w.MinTarget = phase0.Epoch(binary.BigEndian.Uint64(data[8:16]))
+	w.MaxSource = phase0.Epoch(binary.BigEndian.Uint64(data[16:24]))
+	w.MaxTarget = phase0.Epoch(binary.BigEndian.Uint64(data[24:32]))
+	return nil
+}
+
+// AttestationProtector checks attestations against the full attesting history of a public key
+// and records them if they are safe to sign or submit.
+type AttestationProtector interface {
+	CheckAndSaveAttestation(pubKey []byte, data *phase0.AttestationData, signingRoot phase0.Root) error
+}
+
+// SharePubKeyProvider returns the public key of the node's share of the given validator, if it has one.
+type SharePubKeyProvider func(validatorPubKey []byte) ([]byte, bool)
+
+type validatorAttestationProtector struct {
+	protector   AttestationProtector
+	sharePubKey SharePubKeyProvider
+}
+
+// NewValidatorAttestationProtector returns an AttestationProtector of validator public keys, which checks
+// attestations against the history of the node's share of the validator. That's the history recorded
+// when signing with the share and covered by slashing protection interchange.
+func NewValidatorAttestationProtector(protector AttestationProtector, sharePubKey SharePubKeyProvider) AttestationProtector {
+	return &validatorAttestationProtector{
+		protector:   protector,
+		sharePubKey: sharePubKey,
+	}
+}
+
+func (p *validatorAttestationProtector) CheckAndSaveAttestation(validatorPubKey []byte, data *phase0.AttestationData, signingRoot phase0.Root) error {
+	sharePubKey, ok := p.sharePubKey(validatorPubKey)
+	if !ok {
+		return errors.Errorf("no share of validator %x", validatorPubKey)
+	}
+	return p.protector.CheckAndSaveAttestation(sharePubKey, data, signingRoot)
+}
+
+// Errors returned by CheckSlashableAttestation.
+var (
+	ErrInvalidAttestationEpochs = errors.New("attestation source epoch is higher than target epoch")
+	ErrDoubleVote               = errors.New("double vote: attestation with the same target epoch and a different signing root was already signed")
+	ErrSurroundingVote          = errors.New("surrounding vote: attestation surrounds a previously signed attestation")
+	ErrSurroundedVote           = errors.New("surrounded vote: attestation is surrounded by a previously signed attestation")
+	ErrAttestationTooOld        = errors.New("attestation target epoch is older than the attestation history")
+)
+
+// CheckSlashableAttestation checks if an attestation is slashable when compared to the given history.
+// It returns true if the exact same attestation (same target epoch and signing root) was already recorded.
+func CheckSlashableAttestation(history []*AttestationRecord, data *phase0.AttestationData, signingRoot phase0.Root) (bool, error) {
+	if err := validateAttestationData(data); err != nil {
+		return false, err
+	}
+	source, target := data.Source.Epoch, data.Target.Epoch
+
+	var (
+		lowestTarget phase0.Epoch
+		found        bool
+	)
+	for i, record := range history {
+		if i == 0 || record.TargetEpoch < lowestTarget {
+			lowestTarget = record.TargetEpoch
+		}
+		if record.TargetEpoch == target {
+			if record.SigningRoot == signingRoot {
+				found = true
+				continue
+			}
+			return false, ErrDoubleVote
+		}
+		if source < record.SourceEpoch && target > record.TargetEpoch {
+			return false, ErrSurroundingVote
+		}
+		if source > record.SourceEpoch && target < record.TargetEpoch {
+			return false, ErrSurroundedVote
+		}
+	}
+	if !found && len(history) > 0 && target < lowestTarget {
+		return false, ErrAttestationTooOld
+	}
+	return found, nil
+}
+
+func validateAttestationData(data *phase0.AttestationData) error {
+	if data == nil || data.Source == nil || data.Target == nil {
+		return errors.New("attestation data is incomplete")
+	}
+	if data.Source.Epoch > data.Target.Epoch {
+		return ErrInvalidAttestationEpochs
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package ekm
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSlashableAttestation(t *testing.T) {
+	history := []*AttestationRecord{
+		{SourceEpoch: 10, TargetEpoch: 11, SigningRoot: phase0.Root{1}},
+		{SourceEpoch: 11, TargetEpoch: 14, SigningRoot: phase0.Root{2}},
+	}
+
+	tests := []struct {
+		name          string
+		source        phase0.Epoch
+		target        phase0.Epoch
+		root          phase0.Root
+		alreadySigned bool
+		err           error
+	}{
+		{name: "new attestation", source: 14, target: 15, root: phase0.Root{3}},
+		{name: "same attestation", source: 11, target: 14, root: phase0.Root{2}, alreadySigned: true},
+		{name: "double vote", source: 11, target: 14, root: phase0.Root{3}, err: ErrDoubleVote},
+		{name: "surrounding vote", source: 9, target: 15, root: phase0.Root{3}, err: ErrSurroundingVote},
+		{name: "surrounded vote", source: 12, target: 13, root: phase0.Root{3}, err: ErrSurroundedVote},
+		{name: "too old", source: 1, target: 2, root: phase0.Root{3}, err: ErrAttestationTooOld},
+		{name: "source higher than target", source: 16, target: 15, root: phase0.Root{3}, err: ErrInvalidAttestationEpochs},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			data := &phase0.AttestationData{
+				Source: &phase0.Checkpoint{Epoch: test.source},
+				Target: &phase0.Checkpoint{Epoch: test.target},
+			}
+			alreadySigned, err := CheckSlashableAttestation(history, data, test.root)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.alreadySigned, alreadySigned)
+		})
+	}
+}
+
+func TestAttestationRecordEncoding(t *testing.T) {
+	record := &AttestationRecord{SourceEpoch: 1, TargetEpoch: 2, SigningRoot: phase0.Root{3}}
+
+	decoded := &AttestationRecord{}
+	require.NoError(t, decoded.Decode(record.Encode()))
+	require.Equal(t, record, decoded)
+
+	require.Error(t, decoded.Decode([]byte{1, 2, 3}))
+}
+
+func TestAttestationWatermarks(t *testing.T) {
+	watermarks := newAttestationWatermarks([]*AttestationRecord{
+		{SourceEpoch: 10, TargetEpoch: 11},
+		{SourceEpoch: 11, TargetEpoch: 14},
+	})
+	require.Equal(t, &attestationWatermarks{MinTarget: 11, MaxSource: 11, MaxTarget: 14}, watermarks)
+	require.Nil(t, newAttestationWatermarks(nil))
+
+	attestation := func(source, target phase0.Epoch) *phase0.AttestationData {
+		return &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: source},
+			Target: &phase0.Checkpoint{Epoch: target},
+		}
+	}
+	require.True(t, watermarks.precedes(attestation(11, 15)))
+	require.False(t, watermarks.precedes(attestation(10, 15)))
+	require.False(t, watermarks.precedes(attestation(11, 14)))
+
+	decoded := &attestationWatermarks{}
+	require.NoError(t, decoded.Decode(watermarks.Encode()))
+	require.Equal(t, watermarks, decoded)
+	require.Error(t, decoded.Decode([]byte{1, 2, 3}))
+}
@@ -47,7 +47,6 @@ type ethKeyManagerSigner struct {
 	storage           Storage
 	domain            spectypes.DomainType
 	slashingProtector core.SlashingProtector
-	builderProposals  bool
 }
 
 // StorageProvider provides the underlying KeyManager storage.
@@ -59,7 +58,7 @@ type StorageProvider interface {
 }
 
 // NewETHKeyManagerSigner returns a new instance of ethKeyManagerSigner
-func NewETHKeyManagerSigner(logger *zap.Logger, db basedb.Database, network networkconfig.NetworkConfig, builderProposals bool, encryptionKey string) (spectypes.KeyManager, error) {
+func NewETHKeyManagerSigner(logger *zap.Logger, db basedb.Database, network networkconfig.NetworkConfig, encryptionKey string) (spectypes.KeyManager, error) {
 	signerStore := NewSignerStorage(db, network.Beacon, logger)
 	if encryptionKey != "" {
 		err := signerStore.SetEncryptionKey(encryptionKey)
@@ -96,7 +95,6 @@ func NewETHKeyManagerSigner(logger *zap.Logger, db basedb.Database, network netw
 		storage:           signerStore,
 		domain:            network.Domain,
 		slashingProtector: slashingProtector,
-		builderProposals:  builderProposals,
 	}, nil
 }
 
@@ -132,24 +130,23 @@ func (km *ethKeyManagerSigner) signBeaconObject(obj ssz.HashRoot, domain phase0.
 		if !ok {
 			return nil, nil, errors.New("could not cast obj to AttestationData")
 		}
-		return km.signer.SignBeaconAttestation(data, domain, pk)
+		return km.signAttestation(data, domain, pk)
 	case spectypes.DomainProposer:
-		if km.builderProposals {
-			var vBlindedBlock *api.VersionedBlindedBeaconBlock
-			switch v := obj.(type) {
-			case *apiv1bellatrix.BlindedBeaconBlock:
-				vBlindedBlock = &api.VersionedBlindedBeaconBlock{
-					Version:   spec.DataVersionBellatrix,
-					Bellatrix: v,
-				}
-				return km.signer.SignBlindedBeaconBlock(vBlindedBlock, domain, pk)
-			case *apiv1capella.BlindedBeaconBlock:
-				vBlindedBlock = &api.VersionedBlindedBeaconBlock{
-					Version: spec.DataVersionCapella,
-					Capella: v,
-				}
-				return km.signer.SignBlindedBeaconBlock(vBlindedBlock, domain, pk)
+		// Blinded blocks are signed even if this operator doesn't propose them itself,
+		// since the committee may decide on the blinded block proposed by another operator.
+		switch v := obj.(type) {
+		case *apiv1bellatrix.BlindedBeaconBlock:
+			vBlindedBlock := &api.VersionedBlindedBeaconBlock{
+				Version:   spec.DataVersionBellatrix,
+				Bellatrix: v,
+			}
+			return km.signer.SignBlindedBeaconBlock(vBlindedBlock, domain, pk)
+		case *apiv1capella.BlindedBeaconBlock:
+			vBlindedBlock := &api.VersionedBlindedBeaconBlock{
+				Version: spec.DataVersionCapella,
+				Capella: v,
 			}
+			return km.signer.SignBlindedBeaconBlock(vBlindedBlock, domain, pk)
 		}
 
 		var vBlock *spec.VersionedBeaconBlock
@@ -240,6 +237,24 @@ func (km *ethKeyManagerSigner) signBeaconObject(obj ssz.HashRoot, domain phase0.
 	}
 }
 
+// signAttestation signs the attestation only if it passes both the highest attestation check
+// and the surround/double vote check against the attestation history of the share.
+func (km *ethKeyManagerSigner) signAttestation(data *phase0.AttestationData, domain phase0.Domain, pk []byte) (spectypes.Signature, []byte, error) {
+	if err := km.IsAttestationSlashable(pk, data); err != nil {
+		return nil, nil, err
+	}
+
+	signingRoot, err := spectypes.ComputeETHSigningRoot(data, domain)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not compute signing root")
+	}
+	if err := km.storage.CheckAndSaveAttestation(pk, data, signingRoot); err != nil {
+		return nil, nil, errors.Wrap(err, "slashable attestation, not signing")
+	}
+
+	return km.signer.SignBeaconAttestation(data, domain, pk)
+}
+
 func (km *ethKeyManagerSigner) IsAttestationSlashable(pk []byte, data *phase0.AttestationData) error {
 	if val, err := km.slashingProtector.IsSlashableAttestation(pk, data); err != nil || val != nil {
 		if err != nil {
@@ -323,6 +338,9 @@ func (km *ethKeyManagerSigner) RemoveShare(pubKey string) error {
 		if err := km.storage.RemoveHighestProposal(pkDecoded); err != nil {
 			return errors.Wrap(err, "could not remove highest proposal")
 		}
+		if err := km.storage.RemoveAttestationHistory(pkDecoded); err != nil {
+			return errors.Wrap(err, "could not remove attestation history")
+		}
 		if err := km.wallet.DeleteAccountByPublicKey(pubKey); err != nil {
 			return errors.Wrap(err, "could not delete share")
 		}
@@ -0,0 +1,444 @@
+package ekm
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	apiv1bellatrix "github.com/attestantio/go-eth2-client/api/v1/bellatrix"
+	apiv1capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	slashingprotection "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// remoteSignerTimeout is the timeout of requests to the remote signer.
+const remoteSignerTimeout = 5 * time.Second
+
+// Artifact types of the Web3Signer eth2 signing API.
+const (
+	artifactAttestation                       = "ATTESTATION"
+	artifactBlockV2                           = "BLOCK_V2"
+	artifactVoluntaryExit                     = "VOLUNTARY_EXIT"
+	artifactAggregateAndProof                 = "AGGREGATE_AND_PROOF"
+	artifactAggregationSlot                   = "AGGREGATION_SLOT"
+	artifactRandaoReveal                      = "RANDAO_REVEAL"
+	artifactSyncCommitteeMessage              = "SYNC_COMMITTEE_MESSAGE"
+	artifactSyncCommitteeSelectionProof       = "SYNC_COMMITTEE_SELECTION_PROOF"
+	artifactSyncCommitteeContributionAndProof = "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF"
+	artifactValidatorRegistration             = "VALIDATOR_REGISTRATION"
+
+	// artifactSigningRoot is not part of the Web3Signer API: it's used to sign the roots of SSV messages,
+	// which aren't beacon objects, so the remote signer must support it to run SSV duties.
+	artifactSigningRoot = "SIGNING_ROOT"
+)
+
+// remoteKeyManager is a KeyManager which forwards signing requests to a remote signer
+// compatible with the Web3Signer eth2 signing API, so that no share keys are kept by the node.
+// Signing requests carry the signing root computed by the node along with the signed object.
+// Slashing protection is enforced locally as well, in addition to the remote signer's own.
+type remoteKeyManager struct {
+	logger            *zap.Logger
+	url               string
+	client            *http.Client
+	storage           Storage
+	domain            spectypes.DomainType
+	slashingProtector core.SlashingProtector
+	// signLock serializes slashing checks with their updates.
+	signLock sync.Mutex
+}
+
+// NewRemoteKeyManager returns a KeyManager which signs with the share keys held by the remote signer at the given URL.
+func NewRemoteKeyManager(logger *zap.Logger, db basedb.Database, network networkconfig.NetworkConfig, url string) (spectypes.KeyManager, error) {
+	if url == "" {
+		return nil, errors.New("remote signer URL is empty")
+	}
+	signerStore := NewSignerStorage(db, network.Beacon, logger)
+	return &remoteKeyManager{
+		logger:            logger.Named("remote_signer"),
+		url:               strings.TrimSuffix(url, "/"),
+		client:            &http.Client{Timeout: remoteSignerTimeout},
+		storage:           signerStore,
+		domain:            network.Domain,
+		slashingProtector: slashingprotection.NewNormalProtection(signerStore),
+	}, nil
+}
+
+// ListAccounts returns no accounts, since the share keys are held by the remote signer.
+func (km *remoteKeyManager) ListAccounts() ([]core.ValidatorAccount, error) {
+	return nil, nil
+}
+
+func (km *remoteKeyManager) RetrieveHighestAttestation(pubKey []byte) (*phase0.AttestationData, bool, error) {
+	return km.storage.RetrieveHighestAttestation(pubKey)
+}
+
+func (km *remoteKeyManager) RetrieveHighestProposal(pubKey []byte) (phase0.Slot, bool, error) {
+	return km.storage.RetrieveHighestProposal(pubKey)
+}
+
+func (km *remoteKeyManager) SignBeaconObject(obj ssz.HashRoot, domain phase0.Domain, pk []byte, domainType phase0.DomainType) (spectypes.Signature, [32]byte, error) {
+	root, err := spectypes.ComputeETHSigningRoot(obj, domain)
+	if err != nil {
+		return nil, [32]byte{}, errors.Wrap(err, "could not compute signing root")
+	}
+
+	request := map[string]any{"signingRoot": hexutil(root[:])}
+	switch domainType {
+	case spectypes.DomainAttester:
+		data, ok := obj.(*phase0.AttestationData)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to AttestationData")
+		}
+		km.signLock.Lock()
+		defer km.signLock.Unlock()
+		if err := km.checkAttestation(pk, data, root); err != nil {
+			return nil, [32]byte{}, err
+		}
+		request["type"] = artifactAttestation
+		request["attestation"] = data
+	case spectypes.DomainProposer:
+		block, slot, err := blockRequest(obj)
+		if err != nil {
+			return nil, [32]byte{}, err
+		}
+		km.signLock.Lock()
+		defer km.signLock.Unlock()
+		if err := km.checkProposal(pk, slot); err != nil {
+			return nil, [32]byte{}, err
+		}
+		request["type"] = artifactBlockV2
+		request["beacon_block"] = block
+	case spectypes.DomainVoluntaryExit:
+		data, ok := obj.(*phase0.VoluntaryExit)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to VoluntaryExit")
+		}
+		request["type"] = artifactVoluntaryExit
+		request["voluntary_exit"] = data
+	case spectypes.DomainAggregateAndProof:
+		data, ok := obj.(*phase0.AggregateAndProof)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to AggregateAndProof")
+		}
+		request["type"] = artifactAggregateAndProof
+		request["aggregate_and_proof"] = data
+	case spectypes.DomainSelectionProof:
+		data, ok := obj.(spectypes.SSZUint64)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to SSZUint64")
+		}
+		request["type"] = artifactAggregationSlot
+		request["aggregation_slot"] = map[string]string{"slot": strconv.FormatUint(uint64(data), 10)}
+	case spectypes.DomainRandao:
+		data, ok := obj.(spectypes.SSZUint64)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to SSZUint64")
+		}
+		request["type"] = artifactRandaoReveal
+		request["randao_reveal"] = map[string]string{"epoch": strconv.FormatUint(uint64(data), 10)}
+	case spectypes.DomainSyncCommittee:
+		data, ok := obj.(spectypes.SSZBytes)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to SSZBytes")
+		}
+		// The slot isn't part of the signed object, so the current slot is given.
+		request["type"] = artifactSyncCommitteeMessage
+		request["sync_committee_message"] = map[string]string{
+			"beacon_block_root": hexutil(data),
+			"slot":              strconv.FormatUint(uint64(km.storage.BeaconNetwork().EstimatedCurrentSlot()), 10),
+		}
+	case spectypes.DomainSyncCommitteeSelectionProof:
+		data, ok := obj.(*altair.SyncAggregatorSelectionData)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to SyncAggregatorSelectionData")
+		}
+		request["type"] = artifactSyncCommitteeSelectionProof
+		request["sync_aggregator_selection_data"] = data
+	case spectypes.DomainContributionAndProof:
+		data, ok := obj.(*altair.ContributionAndProof)
+		if !ok {
+			return nil, [32]byte{}, errors.New("could not cast obj to ContributionAndProof")
+		}
+		request["type"] = artifactSyncCommitteeContributionAndProof
+		request["contribution_and_proof"] = data
+	case spectypes.DomainApplicationBuilder:
+		data, ok := obj.(*apiv1.ValidatorRegistration)
+		if !ok {
+			return nil, [32]byte{}, fmt.Errorf("obj type is unknown: %T", obj)
+		}
+		request["type"] = artifactValidatorRegistration
+		request["validator_registration"] = data
+	default:
+		return nil, [32]byte{}, errors.New("domain unknown")
+	}
+
+	sig, err := km.sign(pk, request)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	return sig, root, nil
+}
+
+// checkAttestation checks the attestation against the local slashing protection, and saves it if it's safe to sign.
+func (km *remoteKeyManager) checkAttestation(pk []byte, data *phase0.AttestationData, signingRoot phase0.Root) error {
+	if err := km.IsAttestationSlashable(pk, data); err != nil {
+		return err
+	}
+	if err := km.storage.CheckAndSaveAttestation(pk, data, signingRoot); err != nil {
+		return errors.Wrap(err, "slashable attestation, not signing")
+	}
+	return km.slashingProtector.UpdateHighestAttestation(pk, data)
+}
+
+// checkProposal checks the proposal against the local slashing protection, and saves it if it's safe to sign.
+func (km *remoteKeyManager) checkProposal(pk []byte, slot phase0.Slot) error {
+	if err := km.IsBeaconBlockSlashable(pk, slot); err != nil {
+		return err
+	}
+	return km.slashingProtector.UpdateHighestProposal(pk, slot)
+}
+
+func (km *remoteKeyManager) IsAttestationSlashable(pk []byte, data *phase0.AttestationData) error {
+	if val, err := km.slashingProtector.IsSlashableAttestation(pk, data); err != nil || val != nil {
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("slashable attestation (%s), not signing", val.Status)
+	}
+	return nil
+}
+
+func (km *remoteKeyManager) IsBeaconBlockSlashable(pk []byte, slot phase0.Slot) error {
+	status, err := km.slashingProtector.IsSlashableProposal(pk, slot)
+	if err != nil {
+		return err
+	}
+	if status.Status != core.ValidProposal {
+		return errors.Errorf("slashable proposal (%s), not signing", status.Status)
+	}
+	return nil
+}
+
+func (km *remoteKeyManager) SignRoot(data spectypes.Root, sigType spectypes.SignatureType, pk []byte) (spectypes.Signature, error) {
+	root, err := spectypes.ComputeSigningRoot(data, spectypes.ComputeSignatureDomain(km.domain, sigType))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	return km.sign(pk, map[string]any{
+		"type":        artifactSigningRoot,
+		"signingRoot": hexutil(root[:]),
+	})
+}
+
+// AddShare doesn't keep the share key, which is expected to be provisioned to the remote signer,
+// and only prepares the slashing protection of the share.
+func (km *remoteKeyManager) AddShare(shareKey *bls.SecretKey) error {
+	pubKey := shareKey.GetPublicKey().Serialize()
+	if err := km.BumpSlashingProtection(pubKey); err != nil {
+		return errors.Wrap(err, "could not bump slashing protection")
+	}
+
+	pubKeys, err := km.remotePubKeys()
+	if err != nil {
+		km.logger.Warn("could not list remote signer keys", zap.Error(err))
+		return nil
+	}
+	if _, ok := pubKeys[hexutil(pubKey)]; !ok {
+		km.logger.Warn("share key is missing from the remote signer", zap.String("pubkey", hex.EncodeToString(pubKey)))
+	}
+	return nil
+}
+
+func (km *remoteKeyManager) RemoveShare(pubKey string) error {
+	pkDecoded, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not hex decode share public key")
+	}
+	if err := km.storage.RemoveHighestAttestation(pkDecoded); err != nil {
+		return errors.Wrap(err, "could not remove highest attestation")
+	}
+	if err := km.storage.RemoveHighestProposal(pkDecoded); err != nil {
+		return errors.Wrap(err, "could not remove highest proposal")
+	}
+	if err := km.storage.RemoveAttestationHistory(pkDecoded); err != nil {
+		return errors.Wrap(err, "could not remove attestation history")
+	}
+	return nil
+}
+
+// BumpSlashingProtection updates the slashing protection data for a given public key.
+func (km *remoteKeyManager) BumpSlashingProtection(pubKey []byte) error {
+	currentSlot := km.storage.BeaconNetwork().EstimatedCurrentSlot()
+	currentEpoch := km.storage.BeaconNetwork().EstimatedEpochAtSlot(currentSlot)
+
+	highestAtt, found, err := km.RetrieveHighestAttestation(pubKey)
+	if err != nil {
+		return fmt.Errorf("could not retrieve highest attestation: %w", err)
+	}
+	minimalAtt := &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: currentEpoch + minSPAttestationEpochGap - 1},
+		Target: &phase0.Checkpoint{Epoch: currentEpoch + minSPAttestationEpochGap},
+	}
+	if !found || highestAtt == nil || (highestAtt.Source.Epoch < minimalAtt.Source.Epoch && highestAtt.Target.Epoch < minimalAtt.Target.Epoch) {
+		if err := km.storage.SaveHighestAttestation(pubKey, minimalAtt); err != nil {
+			return fmt.Errorf("could not save highest attestation: %w", err)
+		}
+	}
+
+	highestProposal, found, err := km.RetrieveHighestProposal(pubKey)
+	if err != nil {
+		return fmt.Errorf("could not retrieve highest proposal: %w", err)
+	}
+	minimalProposal := currentSlot + minSPProposalSlotGap
+	if !found || highestProposal < minimalProposal {
+		if err := km.storage.SaveHighestProposal(pubKey, minimalProposal); err != nil {
+			return fmt.Errorf("could not save highest proposal: %w", err)
+		}
+	}
+	return nil
+}
+
+// sign requests a signature from the remote signer.
+func (km *remoteKeyManager) sign(pk []byte, request map[string]any) (spectypes.Signature, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode signing request")
+	}
+
+	respBody, err := km.do(http.MethodPost, "/api/v1/eth2/sign/"+hexutil(pk), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "remote signer request failed")
+	}
+
+	// Web3Signer responds with either a JSON object or the signature in plain text.
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	sigHex := strings.TrimSpace(string(respBody))
+	if err := json.Unmarshal(respBody, &resp); err == nil {
+		sigHex = resp.Signature
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(sigHex, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode signature")
+	}
+	if len(sig) != len(phase0.BLSSignature{}) {
+		return nil, errors.Errorf("invalid signature length: %d", len(sig))
+	}
+	return sig, nil
+}
+
+// remotePubKeys returns the public keys held by the remote signer.
+func (km *remoteKeyManager) remotePubKeys() (map[string]struct{}, error) {
+	respBody, err := km.do(http.MethodGet, "/api/v1/eth2/publicKeys", nil)
+	if err != nil {
+		return nil, err
+	}
+	var pubKeys []string
+	if err := json.Unmarshal(respBody, &pubKeys); err != nil {
+		return nil, errors.Wrap(err, "could not decode public keys")
+	}
+	m := make(map[string]struct{}, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		m[strings.ToLower(pubKey)] = struct{}{}
+	}
+	return m, nil
+}
+
+func (km *remoteKeyManager) do(method, path string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSignerTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, km.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := km.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// blockRequest returns the beacon_block field of a BLOCK_V2 signing request for the given block,
+// and its slot. Blocks since Bellatrix are given by their header.
+func blockRequest(obj ssz.HashRoot) (any, phase0.Slot, error) {
+	type blockV2 struct {
+		Version     string                    `json:"version"`
+		Block       any                       `json:"block,omitempty"`
+		BlockHeader *phase0.BeaconBlockHeader `json:"block_header,omitempty"`
+	}
+	header := func(slot phase0.Slot, proposerIndex phase0.ValidatorIndex, parentRoot, stateRoot phase0.Root, body ssz.HashRoot) (*phase0.BeaconBlockHeader, error) {
+		bodyRoot, err := body.HashTreeRoot()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute block body root")
+		}
+		return &phase0.BeaconBlockHeader{
+			Slot:          slot,
+			ProposerIndex: proposerIndex,
+			ParentRoot:    parentRoot,
+			StateRoot:     stateRoot,
+			BodyRoot:      bodyRoot,
+		}, nil
+	}
+
+	switch v := obj.(type) {
+	case *phase0.BeaconBlock:
+		return &blockV2{Version: "PHASE0", Block: v}, v.Slot, nil
+	case *altair.BeaconBlock:
+		return &blockV2{Version: "ALTAIR", Block: v}, v.Slot, nil
+	case *bellatrix.BeaconBlock:
+		h, err := header(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+		return &blockV2{Version: "BELLATRIX", BlockHeader: h}, v.Slot, err
+	case *capella.BeaconBlock:
+		h, err := header(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+		return &blockV2{Version: "CAPELLA", BlockHeader: h}, v.Slot, err
+	case *apiv1bellatrix.BlindedBeaconBlock:
+		h, err := header(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+		return &blockV2{Version: "BELLATRIX", BlockHeader: h}, v.Slot, err
+	case *apiv1capella.BlindedBeaconBlock:
+		h, err := header(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+		return &blockV2{Version: "CAPELLA", BlockHeader: h}, v.Slot, err
+	default:
+		return nil, 0, fmt.Errorf("obj type is unknown: %T", obj)
+	}
+}
+
+func hexutil(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
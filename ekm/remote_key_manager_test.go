@@ -0,0 +1,163 @@
+package ekm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/utils"
+	"github.com/bloxapp/ssv/utils/threshold"
+)
+
+// remoteSigner is a stub of a Web3Signer remote signer, signing the given signing roots.
+type remoteSigner struct {
+	mu       sync.Mutex
+	keys     map[string]*bls.SecretKey
+	requests []map[string]any
+}
+
+func (s *remoteSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodGet && r.URL.Path == "/api/v1/eth2/publicKeys" {
+		var pubKeys []string
+		for pubKey := range s.keys {
+			pubKeys = append(pubKeys, pubKey)
+		}
+		_ = json.NewEncoder(w).Encode(pubKeys)
+		return
+	}
+
+	sk, ok := s.keys[strings.TrimPrefix(r.URL.Path, "/api/v1/eth2/sign/")]
+	if !ok {
+		http.Error(w, "key not found", http.StatusNotFound)
+		return
+	}
+	var request map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.requests = append(s.requests, request)
+
+	root, err := hex.DecodeString(strings.TrimPrefix(request["signingRoot"].(string), "0x"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"signature": "0x" + hex.EncodeToString(sk.SignByte(root).Serialize())})
+}
+
+func (s *remoteSigner) lastRequest() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func TestRemoteKeyManager(t *testing.T) {
+	threshold.Init()
+	logger := logging.TestLogger(t)
+
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk1Str))
+	pk := sk.GetPublicKey().Serialize()
+
+	signer := &remoteSigner{keys: map[string]*bls.SecretKey{"0x" + pk1Str: sk}}
+	server := httptest.NewServer(signer)
+	defer server.Close()
+
+	db, err := getBaseStorage(logger)
+	require.NoError(t, err)
+	network := networkconfig.NetworkConfig{
+		Beacon: utils.SetupMockBeaconNetwork(t, nil),
+		Domain: networkconfig.TestNetwork.Domain,
+	}
+	km, err := NewRemoteKeyManager(logger, db, network, server.URL+"/")
+	require.NoError(t, err)
+	require.NoError(t, km.AddShare(sk))
+
+	verify := func(sig spectypes.Signature, root [32]byte) {
+		s := &bls.Sign{}
+		require.NoError(t, s.Deserialize(sig))
+		require.True(t, s.VerifyByte(sk.GetPublicKey(), root[:]))
+	}
+
+	t.Run("attestation", func(t *testing.T) {
+		data := &phase0.AttestationData{
+			Slot:            64,
+			BeaconBlockRoot: phase0.Root{1},
+			Source:          &phase0.Checkpoint{Epoch: 1},
+			Target:          &phase0.Checkpoint{Epoch: 2},
+		}
+		sig, root, err := km.SignBeaconObject(data, phase0.Domain{}, pk, spectypes.DomainAttester)
+		require.NoError(t, err)
+		verify(sig, root)
+		require.Equal(t, artifactAttestation, signer.lastRequest()["type"])
+		require.NotNil(t, signer.lastRequest()["attestation"])
+
+		// A double vote is refused without reaching the remote signer.
+		data.BeaconBlockRoot = phase0.Root{2}
+		_, _, err = km.SignBeaconObject(data, phase0.Domain{}, pk, spectypes.DomainAttester)
+		require.ErrorContains(t, err, "slashable attestation")
+		require.Len(t, signer.requests, 1)
+	})
+
+	t.Run("proposal", func(t *testing.T) {
+		block := testingBellatrixBlock(40)
+		sig, root, err := km.SignBeaconObject(block, phase0.Domain{}, pk, spectypes.DomainProposer)
+		require.NoError(t, err)
+		verify(sig, root)
+		request := signer.lastRequest()
+		require.Equal(t, artifactBlockV2, request["type"])
+		beaconBlock := request["beacon_block"].(map[string]any)
+		require.Equal(t, "BELLATRIX", beaconBlock["version"])
+		require.NotNil(t, beaconBlock["block_header"])
+
+		_, _, err = km.SignBeaconObject(block, phase0.Domain{}, pk, spectypes.DomainProposer)
+		require.ErrorContains(t, err, "slashable proposal")
+	})
+
+	t.Run("root", func(t *testing.T) {
+		msg := &specqbft.Message{MsgType: specqbft.CommitMsgType, Height: 3, Round: 1, Identifier: []byte{1}, Root: [32]byte{1}}
+		sig, err := km.SignRoot(msg, spectypes.QBFTSignatureType, pk)
+		require.NoError(t, err)
+		root, err := spectypes.ComputeSigningRoot(msg, spectypes.ComputeSignatureDomain(network.Domain, spectypes.QBFTSignatureType))
+		require.NoError(t, err)
+		verify(sig, root)
+		require.Equal(t, artifactSigningRoot, signer.lastRequest()["type"])
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		sk2 := &bls.SecretKey{}
+		require.NoError(t, sk2.SetHexString(sk2Str))
+		_, err := km.SignRoot(&specqbft.Message{Identifier: []byte{1}}, spectypes.QBFTSignatureType, sk2.GetPublicKey().Serialize())
+		require.ErrorContains(t, err, "unexpected status 404")
+	})
+}
+
+func testingBellatrixBlock(slot phase0.Slot) *bellatrix.BeaconBlock {
+	return &bellatrix.BeaconBlock{
+		Slot: slot,
+		Body: &bellatrix.BeaconBlockBody{
+			ETH1Data:         &phase0.ETH1Data{BlockHash: make([]byte, 32)},
+			SyncAggregate:    &altair.SyncAggregate{SyncCommitteeBits: bitfield.NewBitvector512()},
+			ExecutionPayload: &bellatrix.ExecutionPayload{},
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,8 @@ const (
 	accountsPath          = "accounts_%s"
 	highestAttPrefix      = prefix + "highest_att-"
 	highestProposalPrefix = prefix + "highest_prop-"
+	attHistoryPrefix      = prefix + "att_history-"
+	attWatermarksPrefix   = prefix + "att_watermarks-"
 )
 
 // Storage represents the interface for ssv node storage
@@ -44,6 +47,16 @@ type Storage interface {
 
 	RemoveHighestAttestation(pubKey []byte) error
 	RemoveHighestProposal(pubKey []byte) error
+	AttestationProtector
+	ListAttestationHistory(pubKey []byte) ([]*AttestationRecord, error)
+	SaveAttestationRecord(pubKey []byte, record *AttestationRecord) error
+	SaveAttestationRecordTxn(rw basedb.ReadWriter, pubKey []byte, record *AttestationRecord) error
+	RetrieveHighestAttestationTxn(r basedb.Reader, pubKey []byte) (*phase0.AttestationData, bool, error)
+	SaveHighestAttestationTxn(rw basedb.ReadWriter, pubKey []byte, attestation *phase0.AttestationData) error
+	RetrieveHighestProposalTxn(r basedb.Reader, pubKey []byte) (phase0.Slot, bool, error)
+	SaveHighestProposalTxn(rw basedb.ReadWriter, pubKey []byte, slot phase0.Slot) error
+	ListSlashingProtectionPubKeys() ([][]byte, error)
+	RemoveAttestationHistory(pubKey []byte) error
 	SetEncryptionKey(newKey string) error
 	ListAccountsTxn(r basedb.Reader) ([]core.ValidatorAccount, error)
 	SaveAccountTxn(rw basedb.ReadWriter, account core.ValidatorAccount) error
@@ -245,6 +258,10 @@ func (s *storage) SetEncryptor(encryptor encryptor.Encryptor, password []byte) {
 }
 
 func (s *storage) SaveHighestAttestation(pubKey []byte, attestation *phase0.AttestationData) error {
+	return s.SaveHighestAttestationTxn(nil, pubKey, attestation)
+}
+
+func (s *storage) SaveHighestAttestationTxn(rw basedb.ReadWriter, pubKey []byte, attestation *phase0.AttestationData) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -261,10 +278,14 @@ func (s *storage) SaveHighestAttestation(pubKey []byte, attestation *phase0.Atte
 		return errors.Wrap(err, "failed to marshal attestation")
 	}
 
-	return s.db.Set(s.objPrefix(highestAttPrefix), pubKey, data)
+	return s.db.Using(rw).Set(s.objPrefix(highestAttPrefix), pubKey, data)
 }
 
 func (s *storage) RetrieveHighestAttestation(pubKey []byte) (*phase0.AttestationData, bool, error) {
+	return s.RetrieveHighestAttestationTxn(nil, pubKey)
+}
+
+func (s *storage) RetrieveHighestAttestationTxn(r basedb.Reader, pubKey []byte) (*phase0.AttestationData, bool, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -273,7 +294,7 @@ func (s *storage) RetrieveHighestAttestation(pubKey []byte) (*phase0.Attestation
 	}
 
 	// get wallet bytes
-	obj, found, err := s.db.Get(s.objPrefix(highestAttPrefix), pubKey)
+	obj, found, err := s.db.UsingReader(r).Get(s.objPrefix(highestAttPrefix), pubKey)
 	if err != nil {
 		return nil, found, errors.Wrap(err, "could not get highest attestation from db")
 	}
@@ -300,6 +321,10 @@ func (s *storage) RemoveHighestAttestation(pubKey []byte) error {
 }
 
 func (s *storage) SaveHighestProposal(pubKey []byte, slot phase0.Slot) error {
+	return s.SaveHighestProposalTxn(nil, pubKey, slot)
+}
+
+func (s *storage) SaveHighestProposalTxn(rw basedb.ReadWriter, pubKey []byte, slot phase0.Slot) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -314,10 +339,14 @@ func (s *storage) SaveHighestProposal(pubKey []byte, slot phase0.Slot) error {
 	var data []byte
 	data = ssz.MarshalUint64(data, uint64(slot))
 
-	return s.db.Set(s.objPrefix(highestProposalPrefix), pubKey, data)
+	return s.db.Using(rw).Set(s.objPrefix(highestProposalPrefix), pubKey, data)
 }
 
 func (s *storage) RetrieveHighestProposal(pubKey []byte) (phase0.Slot, bool, error) {
+	return s.RetrieveHighestProposalTxn(nil, pubKey)
+}
+
+func (s *storage) RetrieveHighestProposalTxn(r basedb.Reader, pubKey []byte) (phase0.Slot, bool, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -326,7 +355,7 @@ func (s *storage) RetrieveHighestProposal(pubKey []byte) (phase0.Slot, bool, err
 	}
 
 	// get wallet bytes
-	obj, found, err := s.db.Get(s.objPrefix(highestProposalPrefix), pubKey)
+	obj, found, err := s.db.UsingReader(r).Get(s.objPrefix(highestProposalPrefix), pubKey)
 	if err != nil {
 		return 0, found, errors.Wrap(err, "could not get highest proposal from db")
 	}
@@ -349,6 +378,279 @@ func (s *storage) RemoveHighestProposal(pubKey []byte) error {
 	return s.db.Delete(s.objPrefix(highestProposalPrefix), pubKey)
 }
 
+// CheckAndSaveAttestation checks the attestation against the attestation history of the given public key
+// and records it if it's not slashable. Records older than attestationHistoryRetention epochs are pruned.
+// The history is only loaded for attestations which don't precede it, since the watermarks suffice for the others.
+func (s *storage) CheckAndSaveAttestation(pubKey []byte, data *phase0.AttestationData, signingRoot phase0.Root) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if pubKey == nil {
+		return errors.New("pubKey must not be nil")
+	}
+	if err := validateAttestationData(data); err != nil {
+		return err
+	}
+
+	return s.db.Update(func(txn basedb.Txn) error {
+		watermarks, err := s.attestationWatermarks(txn, pubKey)
+		if err != nil {
+			return err
+		}
+		if watermarks != nil {
+			if data.Source.Epoch < watermarks.MinSource || data.Target.Epoch < watermarks.MinTarget {
+				return ErrAttestationTooOld
+			}
+			if !watermarks.precedes(data) {
+				// Attestations are checked again when submitted, so their own record is looked up first.
+				obj, found, err := txn.Get(s.attHistoryKeyPrefix(pubKey), attHistoryKey(data.Target.Epoch))
+				if err != nil {
+					return errors.Wrap(err, "could not get attestation record")
+				}
+				if found {
+					record := &AttestationRecord{}
+					if err := record.Decode(obj.Value); err != nil {
+						return errors.Wrap(err, "could not decode attestation record")
+					}
+					if record.SigningRoot == signingRoot {
+						return nil
+					}
+				}
+
+				history, err := s.listAttestationHistory(txn, pubKey)
+				if err != nil {
+					return err
+				}
+				alreadySigned, err := CheckSlashableAttestation(history, data, signingRoot)
+				if err != nil {
+					return err
+				}
+				if alreadySigned {
+					return nil
+				}
+			}
+		}
+
+		record := &AttestationRecord{
+			SourceEpoch: data.Source.Epoch,
+			TargetEpoch: data.Target.Epoch,
+			SigningRoot: signingRoot,
+		}
+		if err := txn.Set(s.attHistoryKeyPrefix(pubKey), attHistoryKey(record.TargetEpoch), record.Encode()); err != nil {
+			return errors.Wrap(err, "could not save attestation record")
+		}
+		watermarks = watermarks.add(record)
+		if err := s.pruneAttestationHistory(txn, pubKey, watermarks); err != nil {
+			return err
+		}
+		return s.saveAttestationWatermarks(txn, pubKey, watermarks)
+	})
+}
+
+// attestationPruneScanThreshold is the number of epochs to prune above which the whole history is scanned,
+// rather than the records of each epoch deleted one by one.
+const attestationPruneScanThreshold = phase0.Epoch(64)
+
+// pruneAttestationHistory deletes the records older than attestationHistoryRetention epochs
+// and raises the lowest epochs of the watermarks above them.
+func (s *storage) pruneAttestationHistory(txn basedb.Txn, pubKey []byte, watermarks *attestationWatermarks) error {
+	if watermarks.MaxTarget < attestationHistoryRetention {
+		return nil
+	}
+	oldestTarget := watermarks.MaxTarget - attestationHistoryRetention
+	if watermarks.MinTarget >= oldestTarget {
+		return nil
+	}
+
+	var pruned []*AttestationRecord
+	if oldestTarget-watermarks.MinTarget <= attestationPruneScanThreshold {
+		for target := watermarks.MinTarget; target < oldestTarget; target++ {
+			obj, found, err := txn.Get(s.attHistoryKeyPrefix(pubKey), attHistoryKey(target))
+			if err != nil {
+				return errors.Wrap(err, "could not get attestation record")
+			}
+			if !found {
+				continue
+			}
+			record := &AttestationRecord{}
+			if err := record.Decode(obj.Value); err != nil {
+				return errors.Wrap(err, "could not decode attestation record")
+			}
+			pruned = append(pruned, record)
+		}
+	} else {
+		history, err := s.listAttestationHistory(txn, pubKey)
+		if err != nil {
+			return err
+		}
+		for _, record := range history {
+			if record.TargetEpoch < oldestTarget {
+				pruned = append(pruned, record)
+			}
+		}
+	}
+
+	for _, record := range pruned {
+		if err := txn.Delete(s.attHistoryKeyPrefix(pubKey), attHistoryKey(record.TargetEpoch)); err != nil {
+			return errors.Wrap(err, "could not prune attestation record")
+		}
+		// Attestations with a lower source could surround the pruned record.
+		if record.SourceEpoch > watermarks.MinSource {
+			watermarks.MinSource = record.SourceEpoch
+		}
+	}
+	watermarks.MinTarget = oldestTarget
+	return nil
+}
+
+// attestationWatermarks returns the watermarks of the attestation history of the given public key,
+// or nil if it's empty. Histories recorded before the watermarks were introduced get them computed.
+func (s *storage) attestationWatermarks(r basedb.Reader, pubKey []byte) (*attestationWatermarks, error) {
+	obj, found, err := s.db.UsingReader(r).Get(s.objPrefix(attWatermarksPrefix), pubKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get attestation watermarks")
+	}
+	if found {
+		watermarks := &attestationWatermarks{}
+		if err := watermarks.Decode(obj.Value); err != nil {
+			return nil, errors.Wrap(err, "could not decode attestation watermarks")
+		}
+		return watermarks, nil
+	}
+
+	history, err := s.listAttestationHistory(r, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return newAttestationWatermarks(history), nil
+}
+
+func (s *storage) saveAttestationWatermarks(rw basedb.ReadWriter, pubKey []byte, watermarks *attestationWatermarks) error {
+	if err := rw.Set(s.objPrefix(attWatermarksPrefix), pubKey, watermarks.Encode()); err != nil {
+		return errors.Wrap(err, "could not save attestation watermarks")
+	}
+	return nil
+}
+
+// ListAttestationHistory returns the attestation history of the given public key.
+func (s *storage) ListAttestationHistory(pubKey []byte) ([]*AttestationRecord, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.listAttestationHistory(nil, pubKey)
+}
+
+func (s *storage) listAttestationHistory(r basedb.Reader, pubKey []byte) ([]*AttestationRecord, error) {
+	var history []*AttestationRecord
+	err := s.db.UsingReader(r).GetAll(s.attHistoryKeyPrefix(pubKey), func(i int, obj basedb.Obj) error {
+		record := &AttestationRecord{}
+		if err := record.Decode(obj.Value); err != nil {
+			return errors.Wrap(err, "could not decode attestation record")
+		}
+		history = append(history, record)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get attestation history from db")
+	}
+	return history, nil
+}
+
+// SaveAttestationRecord adds the record to the attestation history of the given public key,
+// unless a record with the same target epoch already exists.
+func (s *storage) SaveAttestationRecord(pubKey []byte, record *AttestationRecord) error {
+	return s.db.Update(func(txn basedb.Txn) error {
+		return s.SaveAttestationRecordTxn(txn, pubKey, record)
+	})
+}
+
+func (s *storage) SaveAttestationRecordTxn(rw basedb.ReadWriter, pubKey []byte, record *AttestationRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if pubKey == nil {
+		return errors.New("pubKey must not be nil")
+	}
+
+	_, found, err := rw.Get(s.attHistoryKeyPrefix(pubKey), attHistoryKey(record.TargetEpoch))
+	if err != nil {
+		return errors.Wrap(err, "could not get attestation record")
+	}
+	if found {
+		return nil
+	}
+	watermarks, err := s.attestationWatermarks(rw, pubKey)
+	if err != nil {
+		return err
+	}
+	if err := rw.Set(s.attHistoryKeyPrefix(pubKey), attHistoryKey(record.TargetEpoch), record.Encode()); err != nil {
+		return errors.Wrap(err, "could not save attestation record")
+	}
+	return s.saveAttestationWatermarks(rw, pubKey, watermarks.add(record))
+}
+
+// ListSlashingProtectionPubKeys returns the public keys which have any slashing protection data.
+func (s *storage) ListSlashingProtectionPubKeys() ([][]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	seen := make(map[string]struct{})
+	var pubKeys [][]byte
+	collect := func(pubKey []byte) {
+		if _, ok := seen[string(pubKey)]; ok {
+			return
+		}
+		seen[string(pubKey)] = struct{}{}
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	for _, p := range []string{highestAttPrefix, highestProposalPrefix} {
+		err := s.db.GetAll(s.objPrefix(p), func(i int, obj basedb.Obj) error {
+			collect(obj.Key)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := s.db.GetAll(s.objPrefix(attHistoryPrefix), func(i int, obj basedb.Obj) error {
+		if len(obj.Key) <= attHistoryKeySize {
+			return fmt.Errorf("invalid attestation history key size: %d", len(obj.Key))
+		}
+		collect(obj.Key[:len(obj.Key)-attHistoryKeySize])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKeys, nil
+}
+
+// RemoveAttestationHistory removes the attestation history of the given public key.
+func (s *storage) RemoveAttestationHistory(pubKey []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := s.db.DeletePrefix(s.attHistoryKeyPrefix(pubKey)); err != nil {
+		return err
+	}
+	return s.db.Delete(s.objPrefix(attWatermarksPrefix), pubKey)
+}
+
+func (s *storage) attHistoryKeyPrefix(pubKey []byte) []byte {
+	return append(s.objPrefix(attHistoryPrefix), pubKey...)
+}
+
+const attHistoryKeySize = 8
+
+// attHistoryKey encodes the target epoch in big-endian, so that records are ordered by target epoch.
+func attHistoryKey(targetEpoch phase0.Epoch) []byte {
+	key := make([]byte, attHistoryKeySize)
+	binary.BigEndian.PutUint64(key, uint64(targetEpoch))
+	return key
+}
+
 func (s *storage) decryptData(objectValue []byte) ([]byte, error) {
 	if s.encryptionKey == nil || len(s.encryptionKey) == 0 {
 		return objectValue, nil
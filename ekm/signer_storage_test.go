@@ -1,6 +1,7 @@
 package ekm
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -520,3 +521,127 @@ func TestRemovingHighestProposal(t *testing.T) {
 		})
 	}
 }
+
+func TestAttestationHistory(t *testing.T) {
+	signerStorage, done := newStorageForTest(t)
+	defer done()
+
+	pubKey := _byteArray(pk1Str)
+	attestation := func(source, target phase0.Epoch) *phase0.AttestationData {
+		return &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: source},
+			Target: &phase0.Checkpoint{Epoch: target},
+		}
+	}
+
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(10, 11), phase0.Root{1}))
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(10, 11), phase0.Root{1}))
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(11, 14), phase0.Root{2}))
+
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(10, 11), phase0.Root{3}), ErrDoubleVote)
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(9, 15), phase0.Root{3}), ErrSurroundingVote)
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(12, 13), phase0.Root{3}), ErrSurroundedVote)
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(5, 6), phase0.Root{3}), ErrAttestationTooOld)
+
+	// Other public keys are not affected.
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(_byteArray(pk2Str), attestation(12, 13), phase0.Root{3}))
+
+	history, err := signerStorage.ListAttestationHistory(pubKey)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	// Old records are pruned.
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(14, 14+attestationHistoryRetention), phase0.Root{4}))
+	history, err = signerStorage.ListAttestationHistory(pubKey)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	// Attestations which could conflict with pruned records are refused.
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(9, 15+attestationHistoryRetention), phase0.Root{5}), ErrAttestationTooOld)
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(12, 13), phase0.Root{5}), ErrAttestationTooOld)
+
+	require.NoError(t, signerStorage.RemoveAttestationHistory(pubKey))
+	history, err = signerStorage.ListAttestationHistory(pubKey)
+	require.NoError(t, err)
+	require.Empty(t, history)
+}
+
+func TestAttestationHistoryWithoutWatermarks(t *testing.T) {
+	signerStorage, done := newStorageForTest(t)
+	defer done()
+
+	pubKey := _byteArray(pk1Str)
+	attestation := func(source, target phase0.Epoch) *phase0.AttestationData {
+		return &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: source},
+			Target: &phase0.Checkpoint{Epoch: target},
+		}
+	}
+
+	// Histories recorded before the watermarks get them computed.
+	s := signerStorage.(*storage)
+	record := &AttestationRecord{SourceEpoch: 11, TargetEpoch: 14, SigningRoot: phase0.Root{1}}
+	require.NoError(t, s.db.Set(s.attHistoryKeyPrefix(pubKey), attHistoryKey(record.TargetEpoch), record.Encode()))
+
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(11, 14), phase0.Root{1}))
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(11, 14), phase0.Root{2}), ErrDoubleVote)
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(10, 15), phase0.Root{2}), ErrSurroundingVote)
+	require.ErrorIs(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(1, 2), phase0.Root{2}), ErrAttestationTooOld)
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(pubKey, attestation(14, 15), phase0.Root{2}))
+
+	watermarks, err := s.attestationWatermarks(nil, pubKey)
+	require.NoError(t, err)
+	require.Equal(t, &attestationWatermarks{MinTarget: 14, MaxSource: 14, MaxTarget: 15}, watermarks)
+
+	require.NoError(t, signerStorage.RemoveAttestationHistory(pubKey))
+	watermarks, err = s.attestationWatermarks(nil, pubKey)
+	require.NoError(t, err)
+	require.Nil(t, watermarks)
+}
+
+func TestAttestationHistoryOrder(t *testing.T) {
+	signerStorage, done := newStorageForTest(t)
+	defer done()
+
+	pubKey := _byteArray(pk1Str)
+	for _, target := range []phase0.Epoch{256, 255, 1} {
+		require.NoError(t, signerStorage.SaveAttestationRecord(pubKey, &AttestationRecord{SourceEpoch: target - 1, TargetEpoch: target}))
+	}
+
+	history, err := signerStorage.ListAttestationHistory(pubKey)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, phase0.Epoch(1), history[0].TargetEpoch)
+	require.Equal(t, phase0.Epoch(255), history[1].TargetEpoch)
+	require.Equal(t, phase0.Epoch(256), history[2].TargetEpoch)
+}
+
+func TestValidatorAttestationProtector(t *testing.T) {
+	signerStorage, done := newStorageForTest(t)
+	defer done()
+
+	validatorPubKey := _byteArray(pk1Str)
+	sharePubKey := _byteArray(pk2Str)
+	protector := NewValidatorAttestationProtector(signerStorage, func(pubKey []byte) ([]byte, bool) {
+		if !bytes.Equal(pubKey, validatorPubKey) {
+			return nil, false
+		}
+		return sharePubKey, true
+	})
+	data := &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: 10},
+		Target: &phase0.Checkpoint{Epoch: 11},
+	}
+
+	// The attestation signed with the share may be submitted for the validator, but no conflicting one.
+	require.NoError(t, signerStorage.CheckAndSaveAttestation(sharePubKey, data, phase0.Root{1}))
+	require.NoError(t, protector.CheckAndSaveAttestation(validatorPubKey, data, phase0.Root{1}))
+	require.ErrorIs(t, protector.CheckAndSaveAttestation(validatorPubKey, data, phase0.Root{2}), ErrDoubleVote)
+
+	history, err := signerStorage.ListAttestationHistory(validatorPubKey)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	// Attestations of validators without a share of the node are refused.
+	require.Error(t, protector.CheckAndSaveAttestation(sharePubKey, data, phase0.Root{1}))
+}
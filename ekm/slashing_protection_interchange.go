@@ -0,0 +1,283 @@
+package ekm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// InterchangeFormatVersion is the supported version of the EIP-3076 slashing protection interchange format.
+const InterchangeFormatVersion = "5"
+
+// Interchange is the EIP-3076 slashing protection interchange format.
+// See https://eips.ethereum.org/EIPS/eip-3076
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []*InterchangeData  `json:"data"`
+}
+
+// InterchangeMetadata is the metadata of an interchange file.
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// InterchangeData is the slashing protection data of a single public key.
+type InterchangeData struct {
+	PubKey             string                    `json:"pubkey"`
+	SignedBlocks       []*InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []*InterchangeAttestation `json:"signed_attestations"`
+}
+
+// InterchangeBlock is a signed block entry.
+type InterchangeBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// InterchangeAttestation is a signed attestation entry.
+type InterchangeAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// ShareKeys maps the validator public keys of the node's shares to their share public keys.
+// The storage records slashing protection by share public key, while the interchange format identifies validators.
+type ShareKeys map[phase0.BLSPubKey]phase0.BLSPubKey
+
+func (k ShareKeys) validatorPubKeys() map[phase0.BLSPubKey]phase0.BLSPubKey {
+	validatorPubKeys := make(map[phase0.BLSPubKey]phase0.BLSPubKey, len(k))
+	for validatorPubKey, sharePubKey := range k {
+		validatorPubKeys[sharePubKey] = validatorPubKey
+	}
+	return validatorPubKeys
+}
+
+// ExportSlashingProtection exports the slashing protection data of the given shares by their validator public keys.
+// Highest attestations and proposals are exported as signed entries without signing roots,
+// so that importers treat them as minimal watermarks, unless the history already covers them.
+func ExportSlashingProtection(s Storage, genesisValidatorsRoot phase0.Root, shareKeys ShareKeys) (*Interchange, error) {
+	pubKeys, err := s.ListSlashingProtectionPubKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list public keys")
+	}
+	validatorPubKeys := shareKeys.validatorPubKeys()
+
+	interchange := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    encodeHex(genesisValidatorsRoot[:]),
+		},
+		Data: make([]*InterchangeData, 0, len(pubKeys)),
+	}
+
+	for _, pubKey := range pubKeys {
+		// Shares of removed validators have no validator to be exported for.
+		validatorPubKey, ok := validatorPubKeys[phase0.BLSPubKey(pubKey)]
+		if !ok {
+			continue
+		}
+		data := &InterchangeData{
+			PubKey:             encodeHex(validatorPubKey[:]),
+			SignedBlocks:       []*InterchangeBlock{},
+			SignedAttestations: []*InterchangeAttestation{},
+		}
+
+		highestProposal, found, err := s.RetrieveHighestProposal(pubKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not retrieve highest proposal")
+		}
+		if found {
+			data.SignedBlocks = append(data.SignedBlocks, &InterchangeBlock{
+				Slot: strconv.FormatUint(uint64(highestProposal), 10),
+			})
+		}
+
+		history, err := s.ListAttestationHistory(pubKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list attestation history")
+		}
+		var highestTarget phase0.Epoch
+		for _, record := range history {
+			if record.TargetEpoch > highestTarget {
+				highestTarget = record.TargetEpoch
+			}
+			data.SignedAttestations = append(data.SignedAttestations, &InterchangeAttestation{
+				SourceEpoch: strconv.FormatUint(uint64(record.SourceEpoch), 10),
+				TargetEpoch: strconv.FormatUint(uint64(record.TargetEpoch), 10),
+				SigningRoot: encodeHex(record.SigningRoot[:]),
+			})
+		}
+
+		highestAttestation, found, err := s.RetrieveHighestAttestation(pubKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not retrieve highest attestation")
+		}
+		// An entry for a target of the history would conflict with its record.
+		if found && highestAttestation != nil && (len(history) == 0 || highestAttestation.Target.Epoch > highestTarget) {
+			data.SignedAttestations = append(data.SignedAttestations, &InterchangeAttestation{
+				SourceEpoch: strconv.FormatUint(uint64(highestAttestation.Source.Epoch), 10),
+				TargetEpoch: strconv.FormatUint(uint64(highestAttestation.Target.Epoch), 10),
+			})
+		}
+
+		interchange.Data = append(interchange.Data, data)
+	}
+
+	return interchange, nil
+}
+
+// ImportSlashingProtection imports the given interchange data of the given shares' validators into the storage
+// with the given transaction, so that a failure leaves it untouched once discarded. It returns the number of
+// imported validators, as the data of other validators is skipped.
+// Data is merged conservatively: highest attestations and proposals are only ever raised,
+// and existing attestation records are never overwritten.
+func ImportSlashingProtection(rw basedb.ReadWriter, s Storage, interchange *Interchange, genesisValidatorsRoot phase0.Root, shareKeys ShareKeys) (int, error) {
+	if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return 0, fmt.Errorf("unsupported interchange format version: %s", interchange.Metadata.InterchangeFormatVersion)
+	}
+	root, err := decodeHex(interchange.Metadata.GenesisValidatorsRoot)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid genesis validators root")
+	}
+	if len(root) != len(genesisValidatorsRoot) || root32(root) != genesisValidatorsRoot {
+		return 0, fmt.Errorf("genesis validators root mismatch: expected %s, got %s",
+			encodeHex(genesisValidatorsRoot[:]), interchange.Metadata.GenesisValidatorsRoot)
+	}
+
+	imported := 0
+	for _, data := range interchange.Data {
+		validatorPubKey, err := decodeHex(data.PubKey)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid public key %s", data.PubKey)
+		}
+		if len(validatorPubKey) != len(phase0.BLSPubKey{}) {
+			return 0, fmt.Errorf("invalid public key length of %s: %d", data.PubKey, len(validatorPubKey))
+		}
+		sharePubKey, ok := shareKeys[phase0.BLSPubKey(validatorPubKey)]
+		if !ok {
+			continue
+		}
+		if err := importInterchangeData(rw, s, sharePubKey[:], data); err != nil {
+			return 0, errors.Wrapf(err, "could not import data of %s", data.PubKey)
+		}
+		imported++
+	}
+	return imported, nil
+}
+
+func importInterchangeData(rw basedb.ReadWriter, s Storage, pubKey []byte, data *InterchangeData) error {
+
+	// Raise the highest proposal.
+	var highestSlot phase0.Slot
+	for _, block := range data.SignedBlocks {
+		slot, err := strconv.ParseUint(block.Slot, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "invalid block slot")
+		}
+		if phase0.Slot(slot) > highestSlot {
+			highestSlot = phase0.Slot(slot)
+		}
+	}
+	if highestSlot > 0 {
+		existing, found, err := s.RetrieveHighestProposalTxn(rw, pubKey)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve highest proposal")
+		}
+		if !found || highestSlot > existing {
+			if err := s.SaveHighestProposalTxn(rw, pubKey, highestSlot); err != nil {
+				return errors.Wrap(err, "could not save highest proposal")
+			}
+		}
+	}
+
+	if len(data.SignedAttestations) == 0 {
+		return nil
+	}
+
+	// Merge the attestation history and raise the highest attestation.
+	var highestSource, highestTarget phase0.Epoch
+	for _, attestation := range data.SignedAttestations {
+		source, err := strconv.ParseUint(attestation.SourceEpoch, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "invalid attestation source epoch")
+		}
+		target, err := strconv.ParseUint(attestation.TargetEpoch, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "invalid attestation target epoch")
+		}
+		if source > target {
+			return ErrInvalidAttestationEpochs
+		}
+
+		// A missing signing root is stored as a zero root, which conflicts with any attestation
+		// for the same target epoch.
+		record := &AttestationRecord{
+			SourceEpoch: phase0.Epoch(source),
+			TargetEpoch: phase0.Epoch(target),
+		}
+		if attestation.SigningRoot != "" {
+			signingRoot, err := decodeHex(attestation.SigningRoot)
+			if err != nil || len(signingRoot) != len(phase0.Root{}) {
+				return fmt.Errorf("invalid attestation signing root: %s", attestation.SigningRoot)
+			}
+			record.SigningRoot = root32(signingRoot)
+		}
+		if err := s.SaveAttestationRecordTxn(rw, pubKey, record); err != nil {
+			return errors.Wrap(err, "could not save attestation record")
+		}
+
+		if record.SourceEpoch > highestSource {
+			highestSource = record.SourceEpoch
+		}
+		if record.TargetEpoch > highestTarget {
+			highestTarget = record.TargetEpoch
+		}
+	}
+
+	existing, found, err := s.RetrieveHighestAttestationTxn(rw, pubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve highest attestation")
+	}
+	if found && existing != nil {
+		if existing.Source.Epoch >= highestSource && existing.Target.Epoch >= highestTarget {
+			return nil
+		}
+		if existing.Source.Epoch > highestSource {
+			highestSource = existing.Source.Epoch
+		}
+		if existing.Target.Epoch > highestTarget {
+			highestTarget = existing.Target.Epoch
+		}
+	}
+	highestAttestation := &phase0.AttestationData{
+		BeaconBlockRoot: phase0.Root{},
+		Source:          &phase0.Checkpoint{Epoch: highestSource, Root: phase0.Root{}},
+		Target:          &phase0.Checkpoint{Epoch: highestTarget, Root: phase0.Root{}},
+	}
+	if err := s.SaveHighestAttestationTxn(rw, pubKey, highestAttestation); err != nil {
+		return errors.Wrap(err, "could not save highest attestation")
+	}
+	return nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func root32(b []byte) [32]byte {
+	var root [32]byte
+	copy(root[:], b)
+	return root
+}
@@ -0,0 +1,184 @@
+package ekm
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+func TestSlashingProtectionInterchange(t *testing.T) {
+	source, done := newStorageForTest(t)
+	defer done()
+
+	genesisValidatorsRoot := phase0.Root{1, 2, 3}
+	pubKey := _byteArray(pk1Str)
+	validatorPubKey := phase0.BLSPubKey{1}
+	shareKeys := ShareKeys{validatorPubKey: phase0.BLSPubKey(pubKey)}
+
+	importInterchange := func(s Storage, interchange *Interchange, genesisValidatorsRoot phase0.Root) (int, error) {
+		var imported int
+		err := s.(*storage).db.Update(func(txn basedb.Txn) error {
+			var err error
+			imported, err = ImportSlashingProtection(txn, s, interchange, genesisValidatorsRoot, shareKeys)
+			return err
+		})
+		return imported, err
+	}
+
+	require.NoError(t, source.SaveHighestProposal(pubKey, 100))
+	require.NoError(t, source.SaveHighestAttestation(pubKey, &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: 20},
+		Target: &phase0.Checkpoint{Epoch: 21},
+	}))
+	require.NoError(t, source.CheckAndSaveAttestation(pubKey, &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: 10},
+		Target: &phase0.Checkpoint{Epoch: 11},
+	}, phase0.Root{4}))
+
+	// Data of shares of other validators isn't exported.
+	require.NoError(t, source.SaveHighestProposal(_byteArray(pk2Str), 100))
+
+	interchange, err := ExportSlashingProtection(source, genesisValidatorsRoot, shareKeys)
+	require.NoError(t, err)
+	require.Len(t, interchange.Data, 1)
+	require.Equal(t, encodeHex(validatorPubKey[:]), interchange.Data[0].PubKey)
+	require.Len(t, interchange.Data[0].SignedBlocks, 1)
+	require.Len(t, interchange.Data[0].SignedAttestations, 2)
+
+	t.Run("highest attestation of the history", func(t *testing.T) {
+		source, done := newStorageForTest(t)
+		defer done()
+
+		data := &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: 10},
+			Target: &phase0.Checkpoint{Epoch: 11},
+		}
+		require.NoError(t, source.SaveHighestAttestation(pubKey, data))
+		signingRoot := phase0.Root{4}
+		require.NoError(t, source.CheckAndSaveAttestation(pubKey, data, signingRoot))
+
+		interchange, err := ExportSlashingProtection(source, genesisValidatorsRoot, shareKeys)
+		require.NoError(t, err)
+		require.Len(t, interchange.Data, 1)
+		require.Equal(t, []*InterchangeAttestation{{SourceEpoch: "10", TargetEpoch: "11", SigningRoot: encodeHex(signingRoot[:])}}, interchange.Data[0].SignedAttestations)
+	})
+
+	t.Run("import into empty storage", func(t *testing.T) {
+		target, done := newStorageForTest(t)
+		defer done()
+
+		imported, err := importInterchange(target, interchange, genesisValidatorsRoot)
+		require.NoError(t, err)
+		require.Equal(t, 1, imported)
+
+		slot, found, err := target.RetrieveHighestProposal(pubKey)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, phase0.Slot(100), slot)
+
+		highestAttestation, found, err := target.RetrieveHighestAttestation(pubKey)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, phase0.Epoch(20), highestAttestation.Source.Epoch)
+		require.Equal(t, phase0.Epoch(21), highestAttestation.Target.Epoch)
+
+		history, err := target.ListAttestationHistory(pubKey)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+	})
+
+	t.Run("imported history protects submissions", func(t *testing.T) {
+		target, done := newStorageForTest(t)
+		defer done()
+
+		_, err := importInterchange(target, interchange, genesisValidatorsRoot)
+		require.NoError(t, err)
+
+		// Submissions are checked by validator public key, with a storage of their own like the node's.
+		submissionStorage := NewSignerStorage(target.(*storage).db, networkconfig.TestNetwork.Beacon.GetNetwork(), logging.TestLogger(t))
+		protector := NewValidatorAttestationProtector(submissionStorage, func(pubKey []byte) ([]byte, bool) {
+			sharePubKey, ok := shareKeys[phase0.BLSPubKey(pubKey)]
+			return sharePubKey[:], ok
+		})
+		data := &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: 10},
+			Target: &phase0.Checkpoint{Epoch: 11},
+		}
+		require.NoError(t, protector.CheckAndSaveAttestation(validatorPubKey[:], data, phase0.Root{4}))
+		require.ErrorIs(t, protector.CheckAndSaveAttestation(validatorPubKey[:], data, phase0.Root{5}), ErrDoubleVote)
+		require.ErrorIs(t, protector.CheckAndSaveAttestation(validatorPubKey[:], &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: 9},
+			Target: &phase0.Checkpoint{Epoch: 12},
+		}, phase0.Root{5}), ErrSurroundingVote)
+	})
+
+	t.Run("import keeps higher watermarks", func(t *testing.T) {
+		target, done := newStorageForTest(t)
+		defer done()
+
+		require.NoError(t, target.SaveHighestProposal(pubKey, 200))
+		require.NoError(t, target.SaveHighestAttestation(pubKey, &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: 15},
+			Target: &phase0.Checkpoint{Epoch: 30},
+		}))
+
+		_, err := importInterchange(target, interchange, genesisValidatorsRoot)
+		require.NoError(t, err)
+
+		slot, _, err := target.RetrieveHighestProposal(pubKey)
+		require.NoError(t, err)
+		require.Equal(t, phase0.Slot(200), slot)
+
+		highestAttestation, _, err := target.RetrieveHighestAttestation(pubKey)
+		require.NoError(t, err)
+		require.Equal(t, phase0.Epoch(20), highestAttestation.Source.Epoch)
+		require.Equal(t, phase0.Epoch(30), highestAttestation.Target.Epoch)
+	})
+
+	t.Run("different genesis validators root", func(t *testing.T) {
+		target, done := newStorageForTest(t)
+		defer done()
+
+		_, err := importInterchange(target, interchange, phase0.Root{9})
+		require.ErrorContains(t, err, "genesis validators root mismatch")
+	})
+
+	t.Run("failed import is discarded", func(t *testing.T) {
+		target, done := newStorageForTest(t)
+		defer done()
+
+		invalid := *interchange
+		invalid.Data = append(invalid.Data, &InterchangeData{
+			PubKey:             encodeHex(validatorPubKey[:]),
+			SignedAttestations: []*InterchangeAttestation{{SourceEpoch: "2", TargetEpoch: "1"}},
+		})
+		_, err := importInterchange(target, &invalid, genesisValidatorsRoot)
+		require.ErrorIs(t, err, ErrInvalidAttestationEpochs)
+
+		_, found, err := target.RetrieveHighestProposal(pubKey)
+		require.NoError(t, err)
+		require.False(t, found)
+		history, err := target.ListAttestationHistory(pubKey)
+		require.NoError(t, err)
+		require.Empty(t, history)
+	})
+
+	t.Run("other validators are skipped", func(t *testing.T) {
+		target, done := newStorageForTest(t)
+		defer done()
+
+		var imported int
+		err := target.(*storage).db.Update(func(txn basedb.Txn) error {
+			var err error
+			imported, err = ImportSlashingProtection(txn, target, interchange, genesisValidatorsRoot, ShareKeys{})
+			return err
+		})
+		require.NoError(t, err)
+		require.Zero(t, imported)
+	})
+}
@@ -3,10 +3,10 @@
 package eventhandler
 
 import (
-	"crypto/rsa"
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -22,6 +22,7 @@ import (
 	qbftstorage "github.com/bloxapp/ssv/ibft/storage"
 	"github.com/bloxapp/ssv/logging/fields"
 	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/operator/keys"
 	nodestorage "github.com/bloxapp/ssv/operator/storage"
 	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
@@ -39,6 +40,15 @@ const (
 	ClusterReactivated         = "ClusterReactivated"
 	FeeRecipientAddressUpdated = "FeeRecipientAddressUpdated"
 	ValidatorExited            = "ValidatorExited"
+
+	// Events which don't affect the registry kept by the node.
+	OperatorWhitelistUpdated        = "OperatorWhitelistUpdated"
+	OperatorFeeDeclared             = "OperatorFeeDeclared"
+	OperatorFeeDeclarationCancelled = "OperatorFeeDeclarationCancelled"
+	OperatorFeeExecuted             = "OperatorFeeExecuted"
+	OperatorWithdrawn               = "OperatorWithdrawn"
+	ClusterDeposited                = "ClusterDeposited"
+	ClusterWithdrawn                = "ClusterWithdrawn"
 )
 
 var (
@@ -56,7 +66,14 @@ type taskExecutor interface {
 	ExitValidator(pubKey phase0.BLSPubKey, blockNumber uint64, validatorIndex phase0.ValidatorIndex) error
 }
 
-type ShareEncryptionKeyProvider = func() (*rsa.PrivateKey, bool, error)
+// RecipientNotifier is notified of the executed tasks which change the fee recipients of the operator's validators,
+// so that their proposal preparations are submitted to the beacon node.
+type RecipientNotifier interface {
+	FeeRecipientUpdated(owner ethcommon.Address)
+	ValidatorsAdded(pubKeys ...[]byte)
+}
+
+type ShareEncryptionKeyProvider = func() (keys.OperatorPrivateKey, bool, error)
 
 type OperatorData interface {
 	GetOperatorData() *storage.OperatorData
@@ -74,9 +91,17 @@ type EventHandler struct {
 	beacon                     beaconprotocol.BeaconNode
 	storageMap                 *qbftstorage.QBFTStores
 
-	fullNode bool
-	logger   *zap.Logger
-	metrics  metrics
+	fullNode          bool
+	reorgWindow       uint64
+	recipientNotifier RecipientNotifier
+	logger            *zap.Logger
+	metrics           metrics
+
+	// processedBlocks are the numbers of the retained processed blocks, loaded lazily.
+	processedBlocks []uint64
+
+	// tasksMu serializes the execution of tasks between event processing and retries.
+	tasksMu sync.Mutex
 }
 
 func New(
@@ -101,6 +126,7 @@ func New(
 		keyManager:                 keyManager,
 		beacon:                     beacon,
 		storageMap:                 storageMap,
+		reorgWindow:                DefaultReorgWindow,
 		logger:                     zap.NewNop(),
 		metrics:                    nopMetrics{},
 	}
@@ -117,7 +143,7 @@ func (eh *EventHandler) HandleBlockEventsStream(logs <-chan executionclient.Bloc
 		logger := eh.logger.With(fields.BlockNumber(blockLogs.BlockNumber))
 
 		start := time.Now()
-		tasks, err := eh.processBlockEvents(blockLogs)
+		tasks, pendingTasks, err := eh.processBlockEvents(blockLogs, executeTasks)
 		logger.Debug("processed events from block",
 			fields.Count(len(blockLogs.Logs)),
 			fields.Took(time.Since(start)),
@@ -134,64 +160,74 @@ func (eh *EventHandler) HandleBlockEventsStream(logs <-chan executionclient.Bloc
 
 		logger.Debug("executing tasks", fields.Count(len(tasks)))
 
-		for _, task := range tasks {
-			logger = logger.With(fields.Type(task))
-			logger.Debug("executing task")
-			if err := task.Execute(); err != nil {
-				// TODO: We log failed task until we discuss how we want to handle this case. We likely need to crash the node in this case.
-				logger.Error("failed to execute task", zap.Error(err))
-			} else {
-				logger.Debug("executed task")
-			}
+		// Tasks which fail are left in the journal, and retried by RetryTasks.
+		eh.tasksMu.Lock()
+		for i, task := range tasks {
+			eh.executeTask(logger, pendingTasks[i], task)
 		}
+		eh.tasksMu.Unlock()
 	}
 
 	return
 }
 
-func (eh *EventHandler) processBlockEvents(block executionclient.BlockLogs) ([]Task, error) {
+// processBlockEvents processes the events of the given block in a single transaction.
+// If journalTasks is set, the tasks issued by the events are journaled as pending in the same transaction.
+func (eh *EventHandler) processBlockEvents(block executionclient.BlockLogs, journalTasks bool) ([]Task, []*nodestorage.PendingTask, error) {
 	txn := eh.nodeStorage.Begin()
 	defer txn.Discard()
 
 	lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(txn)
 	if err != nil {
-		return nil, fmt.Errorf("get last processed block: %w", err)
+		return nil, nil, fmt.Errorf("get last processed block: %w", err)
 	}
 	if !found {
 		lastProcessedBlock = new(big.Int).SetUint64(0)
 	} else if lastProcessedBlock == nil {
-		return nil, fmt.Errorf("last processed block is nil")
+		return nil, nil, fmt.Errorf("last processed block is nil")
 	}
 	if lastProcessedBlock.Uint64() >= block.BlockNumber {
 		// Same or higher block has already been processed, this should never happen!
-		// Returning an error to signal that we should stop processing and
+		// Reorgs are rolled back by the syncer before processing the new canonical blocks,
+		// so returning an error to signal that we should stop processing and
 		// investigate the issue.
-		//
-		// TODO: this may happen during reorgs, and we should either
-		// implement reorg support or only process finalized blocks.
-		return nil, ErrInferiorBlock
+		return nil, nil, ErrInferiorBlock
 	}
 
+	// Record the changes made by processing the block, so they can be undone if it's reorged out.
+	journal := newJournalTxn(txn)
+
 	var tasks []Task
 	for _, log := range block.Logs {
-		task, err := eh.processEvent(txn, log)
+		task, err := eh.processEvent(journal, log)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if task != nil {
 			tasks = append(tasks, task)
 		}
 	}
 
-	if err := eh.nodeStorage.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(block.BlockNumber)); err != nil {
-		return nil, fmt.Errorf("set last processed block: %w", err)
+	var pendingTasks []*nodestorage.PendingTask
+	if journalTasks && len(tasks) > 0 {
+		pendingTasks, err = eh.journalTasks(journal, block.BlockNumber, tasks)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := eh.nodeStorage.SaveLastProcessedBlock(journal, new(big.Int).SetUint64(block.BlockNumber)); err != nil {
+		return nil, nil, fmt.Errorf("set last processed block: %w", err)
+	}
+	if err := eh.saveProcessedBlock(txn, block.BlockNumber, block.BlockHash, journal.undo); err != nil {
+		return nil, nil, err
 	}
 
 	if err := txn.Commit(); err != nil {
-		return nil, fmt.Errorf("commit transaction: %w", err)
+		return nil, nil, fmt.Errorf("commit transaction: %w", err)
 	}
 
-	return tasks, nil
+	return tasks, pendingTasks, nil
 }
 
 func (eh *EventHandler) processEvent(txn basedb.Txn, event ethtypes.Log) (Task, error) {
@@ -430,85 +466,251 @@ func (eh *EventHandler) processEvent(txn basedb.Txn, event ethtypes.Log) (Task,
 		task := NewExitValidatorTask(eh.taskExecutor, exitDescriptor.PubKey, exitDescriptor.BlockNumber, exitDescriptor.ValidatorIndex)
 		return task, nil
 
+	case OperatorWhitelistUpdated, OperatorFeeDeclared, OperatorFeeDeclarationCancelled, OperatorFeeExecuted,
+		OperatorWithdrawn, ClusterDeposited, ClusterWithdrawn:
+		data, err := eh.parseInformationalEvent(abiEvent.Name, event)
+		if err != nil {
+			eh.logger.Warn("could not parse event",
+				fields.EventName(abiEvent.Name),
+				zap.Error(err))
+			eh.metrics.EventProcessingFailed(abiEvent.Name)
+			return nil, nil
+		}
+
+		eh.handleInformationalEvent(abiEvent.Name, data)
+		eh.metrics.EventProcessed(abiEvent.Name)
+		return nil, nil
+
 	default:
 		eh.logger.Warn("unknown event name", fields.Name(abiEvent.Name))
 		return nil, nil
 	}
 }
 
-func (eh *EventHandler) HandleLocalEvents(localEvents []localevents.Event) error {
+// parseInformationalEvent parses an event which doesn't affect the registry kept by the node.
+func (eh *EventHandler) parseInformationalEvent(name string, event ethtypes.Log) (interface{}, error) {
+	switch name {
+	case OperatorWhitelistUpdated:
+		data, err := eh.eventParser.ParseOperatorWhitelistUpdated(event)
+		if err != nil {
+			return nil, err
+		}
+		return *data, nil
+	case OperatorFeeDeclared:
+		data, err := eh.eventParser.ParseOperatorFeeDeclared(event)
+		if err != nil {
+			return nil, err
+		}
+		return *data, nil
+	case OperatorFeeDeclarationCancelled:
+		data, err := eh.eventParser.ParseOperatorFeeDeclarationCancelled(event)
+		if err != nil {
+			return nil, err
+		}
+		return *data, nil
+	case OperatorFeeExecuted:
+		data, err := eh.eventParser.ParseOperatorFeeExecuted(event)
+		if err != nil {
+			return nil, err
+		}
+		return *data, nil
+	case OperatorWithdrawn:
+		data, err := eh.eventParser.ParseOperatorWithdrawn(event)
+		if err != nil {
+			return nil, err
+		}
+		return *data, nil
+	case ClusterDeposited:
+		data, err := eh.eventParser.ParseClusterDeposited(event)
+		if err != nil {
+			return nil, err
+		}
+		return *data, nil
+	case ClusterWithdrawn:
+		data, err := eh.eventParser.ParseClusterWithdrawn(event)
+		if err != nil {
+			return nil, err
+		}
+		return *data, nil
+	default:
+		return nil, fmt.Errorf("not an informational event: %s", name)
+	}
+}
+
+// HandleLocalEvents processes the blocks of the given local events which are after the last processed block,
+// each in a single transaction, up to the first block with an event scheduled later than now.
+// If executeTasks is set, the tasks issued by the events are journaled and executed, as for ongoing contract events.
+func (eh *EventHandler) HandleLocalEvents(localEvents []localevents.Event, executeTasks bool) error {
+	lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(nil)
+	if err != nil {
+		return fmt.Errorf("get last processed block: %w", err)
+	}
+	if !found {
+		lastProcessedBlock = new(big.Int)
+	}
+
+	now := time.Now()
+	for i := 0; i < len(localEvents); {
+		block := localEvents[i].Block
+		j := i
+		due := true
+		for ; j < len(localEvents) && localEvents[j].Block == block; j++ {
+			if localEvents[j].Timestamp.After(now) {
+				due = false
+			}
+		}
+		if block <= lastProcessedBlock.Uint64() {
+			i = j
+			continue
+		}
+		if !due {
+			return nil
+		}
+		if err := eh.processLocalBlock(block, localEvents[i:j], executeTasks); err != nil {
+			return err
+		}
+		i = j
+	}
+
+	return nil
+}
+
+// processLocalBlock processes the local events of a block in a single transaction, and executes their tasks if requested.
+func (eh *EventHandler) processLocalBlock(block uint64, localEvents []localevents.Event, executeTasks bool) error {
+	logger := eh.logger.With(fields.BlockNumber(block))
+
 	txn := eh.nodeStorage.Begin()
 	defer txn.Discard()
 
+	var tasks []Task
 	for _, event := range localEvents {
-		if err := eh.processLocalEvent(txn, event); err != nil {
+		task, err := eh.processLocalEvent(txn, event)
+		if err != nil {
 			return fmt.Errorf("process local event: %w", err)
 		}
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+
+	var pendingTasks []*nodestorage.PendingTask
+	if executeTasks && len(tasks) > 0 {
+		var err error
+		pendingTasks, err = eh.journalTasks(txn, block, tasks)
+		if err != nil {
+			return err
+		}
 	}
 
+	if err := eh.nodeStorage.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(block)); err != nil {
+		return fmt.Errorf("set last processed block: %w", err)
+	}
 	if err := txn.Commit(); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
+	logger.Debug("processed local events from block", fields.Count(len(localEvents)))
+
+	if !executeTasks || len(tasks) == 0 {
+		return nil
+	}
 
+	// Tasks which fail are left in the journal, and retried by RetryTasks.
+	eh.tasksMu.Lock()
+	defer eh.tasksMu.Unlock()
+	for i, task := range tasks {
+		eh.executeTask(logger, pendingTasks[i], task)
+	}
 	return nil
 }
 
-func (eh *EventHandler) processLocalEvent(txn basedb.Txn, event localevents.Event) error {
+func (eh *EventHandler) processLocalEvent(txn basedb.Txn, event localevents.Event) (Task, error) {
 	switch event.Name {
 	case OperatorAdded:
 		data := event.Data.(contract.ContractOperatorAdded)
+		data.Raw.BlockNumber = event.Block
 		if err := eh.handleOperatorAdded(txn, &data); err != nil {
-			return fmt.Errorf("handle OperatorAdded: %w", err)
+			return nil, fmt.Errorf("handle OperatorAdded: %w", err)
 		}
-		return nil
+		return nil, nil
 	case OperatorRemoved:
 		data := event.Data.(contract.ContractOperatorRemoved)
+		data.Raw.BlockNumber = event.Block
 		if err := eh.handleOperatorRemoved(txn, &data); err != nil {
-			return fmt.Errorf("handle OperatorRemoved: %w", err)
+			return nil, fmt.Errorf("handle OperatorRemoved: %w", err)
 		}
-		return nil
+		return nil, nil
 	case ValidatorAdded:
 		data := event.Data.(contract.ContractValidatorAdded)
-		if _, err := eh.handleValidatorAdded(txn, &data); err != nil {
-			return fmt.Errorf("handle ValidatorAdded: %w", err)
+		data.Raw.BlockNumber = event.Block
+		share, err := eh.handleValidatorAdded(txn, &data)
+		if err != nil {
+			return nil, fmt.Errorf("handle ValidatorAdded: %w", err)
 		}
-		return nil
+		if share == nil {
+			return nil, nil
+		}
+		return NewStartValidatorTask(eh.taskExecutor, share), nil
 	case ValidatorRemoved:
 		data := event.Data.(contract.ContractValidatorRemoved)
-		if _, err := eh.handleValidatorRemoved(txn, &data); err != nil {
-			return fmt.Errorf("handle ValidatorRemoved: %w", err)
+		data.Raw.BlockNumber = event.Block
+		validatorPubKey, err := eh.handleValidatorRemoved(txn, &data)
+		if err != nil {
+			return nil, fmt.Errorf("handle ValidatorRemoved: %w", err)
 		}
-		return nil
+		if validatorPubKey == nil {
+			return nil, nil
+		}
+		return NewStopValidatorTask(eh.taskExecutor, validatorPubKey), nil
 	case ClusterLiquidated:
 		data := event.Data.(contract.ContractClusterLiquidated)
-		_, err := eh.handleClusterLiquidated(txn, &data)
+		data.Raw.BlockNumber = event.Block
+		sharesToLiquidate, err := eh.handleClusterLiquidated(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle ClusterLiquidated: %w", err)
+			return nil, fmt.Errorf("handle ClusterLiquidated: %w", err)
 		}
-		return nil
+		if len(sharesToLiquidate) == 0 {
+			return nil, nil
+		}
+		return NewLiquidateClusterTask(eh.taskExecutor, data.Owner, data.OperatorIds, sharesToLiquidate), nil
 	case ClusterReactivated:
 		data := event.Data.(contract.ContractClusterReactivated)
-		_, err := eh.handleClusterReactivated(txn, &data)
+		data.Raw.BlockNumber = event.Block
+		sharesToReactivate, err := eh.handleClusterReactivated(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle ClusterReactivated: %w", err)
+			return nil, fmt.Errorf("handle ClusterReactivated: %w", err)
 		}
-		return nil
+		if len(sharesToReactivate) == 0 {
+			return nil, nil
+		}
+		return NewReactivateClusterTask(eh.taskExecutor, data.Owner, data.OperatorIds, sharesToReactivate), nil
 	case FeeRecipientAddressUpdated:
 		data := event.Data.(contract.ContractFeeRecipientAddressUpdated)
-		_, err := eh.handleFeeRecipientAddressUpdated(txn, &data)
+		data.Raw.BlockNumber = event.Block
+		updated, err := eh.handleFeeRecipientAddressUpdated(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle FeeRecipientAddressUpdated: %w", err)
+			return nil, fmt.Errorf("handle FeeRecipientAddressUpdated: %w", err)
 		}
-		return nil
+		if !updated {
+			return nil, nil
+		}
+		return NewUpdateFeeRecipientTask(eh.taskExecutor, data.Owner, data.RecipientAddress), nil
 	case ValidatorExited:
 		data := event.Data.(contract.ContractValidatorExited)
-		_, err := eh.handleValidatorExited(txn, &data)
+		data.Raw.BlockNumber = event.Block
+		exitDescriptor, err := eh.handleValidatorExited(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle ValidatorExited: %w", err)
+			return nil, fmt.Errorf("handle ValidatorExited: %w", err)
 		}
-		return nil
+		if exitDescriptor == nil {
+			return nil, nil
+		}
+		return NewExitValidatorTask(eh.taskExecutor, exitDescriptor.PubKey, exitDescriptor.BlockNumber, exitDescriptor.ValidatorIndex), nil
+	case OperatorWhitelistUpdated, OperatorFeeDeclared, OperatorFeeDeclarationCancelled, OperatorFeeExecuted,
+		OperatorWithdrawn, ClusterDeposited, ClusterWithdrawn:
+		eh.handleInformationalEvent(event.Name, event.Data)
+		return nil, nil
 	default:
 		eh.logger.Warn("unknown local event name", fields.Name(event.Name))
-		return nil
+		return nil, nil
 	}
 }
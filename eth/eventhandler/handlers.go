@@ -20,7 +20,6 @@ import (
 	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
 	registrystorage "github.com/bloxapp/ssv/registry/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
-	"github.com/bloxapp/ssv/utils/rsaencryption"
 )
 
 // b64 encrypted key length is 256
@@ -252,6 +251,9 @@ func (eh *EventHandler) handleShareCreation(
 	if err := eh.nodeStorage.Shares().Save(txn, share); err != nil {
 		return nil, fmt.Errorf("could not save validator share: %w", err)
 	}
+	if err := eh.nodeStorage.SaveEncryptedShares(txn, share.ValidatorPubKey, encryptedKeys); err != nil {
+		return nil, fmt.Errorf("could not save encrypted shares: %w", err)
+	}
 
 	return share, nil
 }
@@ -299,7 +301,7 @@ func (eh *EventHandler) validatorAddedEventToShare(
 		}
 
 		shareSecret = &bls.SecretKey{}
-		decryptedSharePrivateKey, err := rsaencryption.DecodeKey(operatorPrivateKey, encryptedKeys[i])
+		decryptedSharePrivateKey, err := operatorPrivateKey.Decrypt(encryptedKeys[i])
 		if err != nil {
 			return nil, nil, &MalformedEventError{
 				Err: fmt.Errorf("could not decrypt share private key: %w", err),
@@ -367,6 +369,9 @@ func (eh *EventHandler) handleValidatorRemoved(txn basedb.Txn, event *contract.C
 	if err := eh.nodeStorage.Shares().Delete(txn, share.ValidatorPubKey); err != nil {
 		return nil, fmt.Errorf("could not remove validator share: %w", err)
 	}
+	if err := eh.nodeStorage.DeleteEncryptedShares(txn, share.ValidatorPubKey); err != nil {
+		return nil, fmt.Errorf("could not remove encrypted shares: %w", err)
+	}
 
 	isOperatorShare := share.BelongsToOperator(eh.operatorData.GetOperatorData().ID)
 	if isOperatorShare || eh.fullNode {
@@ -513,6 +518,55 @@ func (eh *EventHandler) handleValidatorExited(txn basedb.Txn, event *contract.Co
 	return ed, nil
 }
 
+// handleInformationalEvent logs an event which doesn't affect the registry kept by the node,
+// such as operator fee and whitelist changes and cluster balance changes.
+func (eh *EventHandler) handleInformationalEvent(name string, data interface{}) {
+	logger := eh.logger.With(fields.EventName(name))
+	switch event := data.(type) {
+	case contract.ContractOperatorWhitelistUpdated:
+		logger = logger.With(
+			fields.TxHash(event.Raw.TxHash),
+			fields.OperatorID(event.OperatorId),
+			zap.String("whitelisted", event.Whitelisted.String()))
+	case contract.ContractOperatorFeeDeclared:
+		logger = logger.With(
+			fields.TxHash(event.Raw.TxHash),
+			fields.Owner(event.Owner),
+			fields.OperatorID(event.OperatorId),
+			zap.Stringer("fee", event.Fee))
+	case contract.ContractOperatorFeeDeclarationCancelled:
+		logger = logger.With(
+			fields.TxHash(event.Raw.TxHash),
+			fields.Owner(event.Owner),
+			fields.OperatorID(event.OperatorId))
+	case contract.ContractOperatorFeeExecuted:
+		logger = logger.With(
+			fields.TxHash(event.Raw.TxHash),
+			fields.Owner(event.Owner),
+			fields.OperatorID(event.OperatorId),
+			zap.Stringer("fee", event.Fee))
+	case contract.ContractOperatorWithdrawn:
+		logger = logger.With(
+			fields.TxHash(event.Raw.TxHash),
+			fields.Owner(event.Owner),
+			fields.OperatorID(event.OperatorId),
+			zap.Stringer("value", event.Value))
+	case contract.ContractClusterDeposited:
+		logger = logger.With(
+			fields.TxHash(event.Raw.TxHash),
+			fields.Owner(event.Owner),
+			fields.OperatorIDs(event.OperatorIds),
+			zap.Stringer("value", event.Value))
+	case contract.ContractClusterWithdrawn:
+		logger = logger.With(
+			fields.TxHash(event.Raw.TxHash),
+			fields.Owner(event.Owner),
+			fields.OperatorIDs(event.OperatorIds),
+			zap.Stringer("value", event.Value))
+	}
+	logger.Debug("processed event")
+}
+
 func splitBytes(buf []byte, lim int) [][]byte {
 	var chunk []byte
 	chunks := make([][]byte, 0, len(buf)/lim+1)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/binary"
 	"testing"
+	"time"
 
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -51,7 +52,8 @@ func TestHandleLocalEvent(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		require.NoError(t, eh.HandleLocalEvents(parsedData))
+		parsedData[0].Block = 1
+		require.NoError(t, eh.HandleLocalEvents(parsedData, false))
 	})
 
 	// TODO: test correct signature
@@ -90,6 +92,59 @@ func TestHandleLocalEvent(t *testing.T) {
 			require.False(t, found)
 		}
 
-		require.ErrorIs(t, eh.HandleLocalEvents(parsedData), ErrSignatureVerification)
+		parsedData[0].Block = 1
+		require.ErrorIs(t, eh.HandleLocalEvents(parsedData, false), ErrSignatureVerification)
+	})
+	t.Run("events are processed by block once they're due", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		logger := zaptest.NewLogger(t)
+		eh, _, err := setupEventHandler(t, ctx, logger, nil, ops[0], false)
+		require.NoError(t, err)
+
+		operatorAdded := func(block uint64, id uint64, timestamp time.Time) localevents.Event {
+			return localevents.Event{
+				Name:      OperatorAdded,
+				Block:     block,
+				Timestamp: timestamp,
+				Data: contract.ContractOperatorAdded{
+					OperatorId: id,
+					PublicKey:  binary.LittleEndian.AppendUint64(nil, id),
+				},
+			}
+		}
+		localEvents := []localevents.Event{
+			operatorAdded(1, 1, time.Time{}),
+			operatorAdded(2, 2, time.Now().Add(-time.Minute)),
+			operatorAdded(3, 3, time.Now().Add(time.Hour)),
+			operatorAdded(3, 4, time.Time{}),
+		}
+
+		requireLastProcessedBlock := func(expected uint64) {
+			lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(nil)
+			require.NoError(t, err)
+			require.True(t, found)
+			require.EqualValues(t, expected, lastProcessedBlock.Uint64())
+		}
+		requireOperatorsExist := func(expected bool, ids ...uint64) {
+			for _, id := range ids {
+				_, found, err := eh.nodeStorage.GetOperatorData(nil, id)
+				require.NoError(t, err)
+				require.Equal(t, expected, found)
+			}
+		}
+
+		// The third block isn't due yet.
+		require.NoError(t, eh.HandleLocalEvents(localEvents, false))
+		requireLastProcessedBlock(2)
+		requireOperatorsExist(true, 1, 2)
+		requireOperatorsExist(false, 3, 4)
+
+		// Processed blocks are skipped, so handling the events again doesn't fail on existing operators.
+		localEvents[2].Timestamp = time.Now().Add(-time.Second)
+		require.NoError(t, eh.HandleLocalEvents(localEvents, false))
+		requireLastProcessedBlock(3)
+		requireOperatorsExist(true, 3, 4)
 	})
 }
@@ -28,3 +28,19 @@ func WithFullNode() Option {
 		eh.fullNode = true
 	}
 }
+
+// WithReorgWindow sets the number of blocks for which processed blocks are retained,
+// which bounds the depth of reorgs that can be rolled back.
+func WithReorgWindow(blocks uint64) Option {
+	return func(eh *EventHandler) {
+		eh.reorgWindow = blocks
+	}
+}
+
+// WithRecipientNotifier notifies the given RecipientNotifier of the executed tasks
+// which change the fee recipients of the operator's validators.
+func WithRecipientNotifier(notifier RecipientNotifier) Option {
+	return func(eh *EventHandler) {
+		eh.recipientNotifier = notifier
+	}
+}
@@ -0,0 +1,371 @@
+package eventhandler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/ekm"
+	"github.com/bloxapp/ssv/logging/fields"
+	nodestorage "github.com/bloxapp/ssv/operator/storage"
+	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// DefaultReorgWindow is the default number of blocks for which processed blocks are retained,
+// and thus the deepest reorg which can be rolled back.
+const DefaultReorgWindow = 256
+
+// journalTxn is a transaction which records the previous value of every key it changes,
+// so that the changes can be undone if the block they were made by is reorged out.
+type journalTxn struct {
+	basedb.Txn
+	undo    []nodestorage.UndoEntry
+	touched map[string]struct{}
+}
+
+func newJournalTxn(txn basedb.Txn) *journalTxn {
+	return &journalTxn{
+		Txn:     txn,
+		touched: make(map[string]struct{}),
+	}
+}
+
+func (j *journalTxn) Set(prefix []byte, key []byte, value []byte) error {
+	if err := j.record(prefix, key); err != nil {
+		return err
+	}
+	return j.Txn.Set(prefix, key, value)
+}
+
+func (j *journalTxn) SetMany(prefix []byte, n int, next func(int) (basedb.Obj, error)) error {
+	for i := 0; i < n; i++ {
+		obj, err := next(i)
+		if err != nil {
+			return err
+		}
+		if err := j.Set(prefix, obj.Key, obj.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *journalTxn) Delete(prefix []byte, key []byte) error {
+	if err := j.record(prefix, key); err != nil {
+		return err
+	}
+	return j.Txn.Delete(prefix, key)
+}
+
+// record saves the value of the key before its first change in this transaction.
+func (j *journalTxn) record(prefix []byte, key []byte) error {
+	fullKey := append(append([]byte{}, prefix...), key...)
+	if _, ok := j.touched[string(fullKey)]; ok {
+		return nil
+	}
+	obj, found, err := j.Txn.Get(nil, fullKey)
+	if err != nil {
+		return fmt.Errorf("could not read previous value: %w", err)
+	}
+	j.touched[string(fullKey)] = struct{}{}
+	j.undo = append(j.undo, nodestorage.UndoEntry{Key: fullKey, Value: obj.Value, Existed: found})
+	return nil
+}
+
+// saveProcessedBlock retains the given block along with its undo journal,
+// and deletes the blocks which fell out of the reorg window, except for the newest of them,
+// whose journal is dropped but whose hash is kept to detect reorgs deeper than the window.
+func (eh *EventHandler) saveProcessedBlock(txn basedb.Txn, number uint64, hash ethcommon.Hash, undo []nodestorage.UndoEntry) error {
+	if err := eh.loadProcessedBlocks(); err != nil {
+		return err
+	}
+	err := eh.nodeStorage.SaveProcessedBlock(txn, &nodestorage.ProcessedBlock{
+		Number: number,
+		Hash:   hash,
+		Undo:   undo,
+	})
+	if err != nil {
+		return fmt.Errorf("save processed block: %w", err)
+	}
+
+	retained := eh.processedBlocks[:0]
+	for i, n := range eh.processedBlocks {
+		if n+eh.reorgWindow >= number {
+			retained = append(retained, n)
+			continue
+		}
+		if i+1 < len(eh.processedBlocks) && eh.processedBlocks[i+1]+eh.reorgWindow < number {
+			if err := eh.nodeStorage.DeleteProcessedBlock(txn, n); err != nil {
+				return fmt.Errorf("delete processed block: %w", err)
+			}
+			continue
+		}
+		if err := eh.nodeStorage.PruneProcessedBlock(txn, n); err != nil {
+			return fmt.Errorf("prune processed block: %w", err)
+		}
+		retained = append(retained, n)
+	}
+	eh.processedBlocks = append(retained, number)
+	return nil
+}
+
+// loadProcessedBlocks loads the numbers of the retained processed blocks, if not loaded yet.
+func (eh *EventHandler) loadProcessedBlocks() error {
+	if eh.processedBlocks != nil {
+		return nil
+	}
+	blocks, err := eh.nodeStorage.GetProcessedBlocks(nil)
+	if err != nil {
+		return fmt.Errorf("get processed blocks: %w", err)
+	}
+	eh.processedBlocks = make([]uint64, 0, len(blocks))
+	for _, block := range blocks {
+		eh.processedBlocks = append(eh.processedBlocks, block.Number)
+	}
+	return nil
+}
+
+// ProcessedBlocks returns the retained processed blocks, ordered by their number.
+func (eh *EventHandler) ProcessedBlocks() ([]*nodestorage.ProcessedBlock, error) {
+	return eh.nodeStorage.GetProcessedBlocks(nil)
+}
+
+// Rollback reverts the registry to its state after processing the given block,
+// undoing the changes of the blocks processed after it, which were reorged out of the canonical chain.
+// If executeTasks is set, validators are stopped or started to match the reverted registry.
+func (eh *EventHandler) Rollback(toBlock uint64, executeTasks bool) error {
+	eh.tasksMu.Lock()
+	defer eh.tasksMu.Unlock()
+
+	blocks, err := eh.nodeStorage.GetProcessedBlocks(nil)
+	if err != nil {
+		return fmt.Errorf("get processed blocks: %w", err)
+	}
+	i := sort.Search(len(blocks), func(i int) bool { return blocks[i].Number > toBlock })
+	reverted := blocks[i:]
+	if len(reverted) == 0 {
+		return nil
+	}
+
+	logger := eh.logger.With(fields.BlockNumber(toBlock))
+	logger.Warn("rolling back reorged blocks",
+		zap.Uint64("from_block", reverted[0].Number),
+		zap.Uint64("to_block", reverted[len(reverted)-1].Number),
+		fields.Count(len(reverted)))
+
+	before, err := eh.ownRegistry(nil)
+	if err != nil {
+		return err
+	}
+
+	txn := eh.nodeStorage.Begin()
+	defer txn.Discard()
+
+	for i := len(reverted) - 1; i >= 0; i-- {
+		block := reverted[i]
+		if block.Pruned {
+			return fmt.Errorf("block %d can't be rolled back since it's out of the reorg window", block.Number)
+		}
+		for j := len(block.Undo) - 1; j >= 0; j-- {
+			if err := block.Undo[j].Apply(txn); err != nil {
+				return fmt.Errorf("undo block %d: %w", block.Number, err)
+			}
+		}
+		if err := eh.nodeStorage.DeleteProcessedBlock(txn, block.Number); err != nil {
+			return fmt.Errorf("delete processed block: %w", err)
+		}
+	}
+	if err := eh.nodeStorage.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(toBlock)); err != nil {
+		return fmt.Errorf("set last processed block: %w", err)
+	}
+
+	after, err := eh.ownRegistry(txn)
+	if err != nil {
+		return err
+	}
+	// The key manager isn't transactional, so its changes are applied before committing:
+	// if they fail, the rollback fails and is retried, and since they're idempotent,
+	// applying them again after a failed commit is harmless.
+	restored, err := eh.reconcileKeys(txn, before, after)
+	if err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	// Reload the in-memory state from the reverted registry.
+	eh.processedBlocks = nil
+	if err := eh.nodeStorage.Shares().Reload(); err != nil {
+		return fmt.Errorf("reload shares: %w", err)
+	}
+	if err := eh.reloadOperatorData(); err != nil {
+		return err
+	}
+
+	if executeTasks {
+		eh.reconcileValidators(before, after, restored)
+	}
+
+	logger.Info("rolled back reorged blocks")
+	return nil
+}
+
+// ownRegistry is the part of the registry relevant to this operator's validators.
+type ownRegistry struct {
+	// shares are copies of this operator's shares, by their validator public key.
+	shares map[string]ssvtypes.SSVShare
+	// recipients are the fee recipients of the owners of the shares.
+	recipients map[ethcommon.Address]bellatrix.ExecutionAddress
+}
+
+// ownRegistry reads this operator's part of the registry using the given reader.
+func (eh *EventHandler) ownRegistry(r basedb.Reader) (*ownRegistry, error) {
+	reg := &ownRegistry{shares: make(map[string]ssvtypes.SSVShare)}
+	od, found, err := eh.nodeStorage.GetOperatorDataByPubKey(r, eh.operatorData.GetOperatorData().PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("get operator data: %w", err)
+	}
+	if !found || od.ID == 0 {
+		return reg, nil
+	}
+	shares, err := eh.nodeStorage.Shares().ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read shares: %w", err)
+	}
+	var owners []ethcommon.Address
+	for _, share := range shares {
+		if !share.BelongsToOperator(od.ID) {
+			continue
+		}
+		reg.shares[hex.EncodeToString(share.ValidatorPubKey)] = *share
+		owners = append(owners, share.OwnerAddress)
+	}
+	reg.recipients, err = eh.nodeStorage.GetRecipientDataMany(r, owners)
+	if err != nil {
+		return nil, fmt.Errorf("get recipients: %w", err)
+	}
+	return reg, nil
+}
+
+// reloadOperatorData reloads this operator's data, which is reset if its registration was reverted.
+func (eh *EventHandler) reloadOperatorData() error {
+	current := eh.operatorData.GetOperatorData()
+	od, found, err := eh.nodeStorage.GetOperatorDataByPubKey(nil, current.PublicKey)
+	if err != nil {
+		return fmt.Errorf("get operator data: %w", err)
+	}
+	if !found {
+		od = &registrystorage.OperatorData{PublicKey: current.PublicKey}
+	}
+	eh.operatorData.SetOperatorData(od)
+	return nil
+}
+
+// reconcileKeys updates the key manager according to the changes in this operator's registry,
+// reading the reverted registry with the given reader. It returns the validator public keys
+// of the shares whose keys were restored.
+func (eh *EventHandler) reconcileKeys(r basedb.Reader, before, after *ownRegistry) (map[string]struct{}, error) {
+	for pk, prev := range before.shares {
+		cur, ok := after.shares[pk]
+		switch {
+		case !ok:
+			// Added by a reverted block.
+			if err := eh.keyManager.RemoveShare(hex.EncodeToString(prev.SharePubKey)); err != nil {
+				return nil, fmt.Errorf("remove share of validator %x from key manager: %w", prev.ValidatorPubKey, err)
+			}
+		case prev.Liquidated && !cur.Liquidated:
+			// Liquidated by a reverted block.
+			if err := eh.keyManager.(ekm.StorageProvider).BumpSlashingProtection(cur.SharePubKey); err != nil {
+				return nil, fmt.Errorf("bump slashing protection of validator %x: %w", cur.ValidatorPubKey, err)
+			}
+		}
+	}
+
+	restored := make(map[string]struct{})
+	for pk, cur := range after.shares {
+		if _, ok := before.shares[pk]; ok {
+			continue
+		}
+		// Removed by a reverted block, which also deleted its share key,
+		// so it's decrypted again from the encrypted shares restored along with the share.
+		encryptedKeys, found, err := eh.nodeStorage.GetEncryptedShares(r, cur.ValidatorPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("get encrypted shares of validator %x: %w", cur.ValidatorPubKey, err)
+		}
+		if !found {
+			eh.logger.Error("validator removed in a reorged block can't be restarted since its encrypted shares weren't saved, it must be re-registered",
+				fields.PubKey(cur.ValidatorPubKey))
+			continue
+		}
+		share := cur
+		shareSecret, err := eh.ownShare(&share, cur.OperatorID, encryptedKeys)
+		if err != nil {
+			return nil, fmt.Errorf("restore share key of validator %x: %w", cur.ValidatorPubKey, err)
+		}
+		if shareSecret == nil {
+			return nil, fmt.Errorf("restore share key of validator %x: operator isn't in the committee", cur.ValidatorPubKey)
+		}
+		// Adding the share also bumps its slashing protection, whose history was removed along with it.
+		if err := eh.keyManager.AddShare(shareSecret); err != nil {
+			return nil, fmt.Errorf("add share of validator %x to key manager: %w", cur.ValidatorPubKey, err)
+		}
+		restored[pk] = struct{}{}
+	}
+	return restored, nil
+}
+
+// reconcileValidators stops and starts validators according to the changes in this operator's registry,
+// starting the removed validators only if their share keys were restored.
+func (eh *EventHandler) reconcileValidators(before, after *ownRegistry, restored map[string]struct{}) {
+	var tasks []Task
+	for pk, prev := range before.shares {
+		prev := prev
+		cur, ok := after.shares[pk]
+		switch {
+		case !ok:
+			// Added by a reverted block.
+			tasks = append(tasks, NewStopValidatorTask(eh.taskExecutor, prev.ValidatorPubKey))
+		case !prev.Liquidated && cur.Liquidated:
+			// Reactivated by a reverted block.
+			tasks = append(tasks, NewLiquidateClusterTask(eh.taskExecutor, cur.OwnerAddress, committeeIDs(&cur), []*ssvtypes.SSVShare{&cur}))
+		case prev.Liquidated && !cur.Liquidated:
+			// Liquidated by a reverted block.
+			tasks = append(tasks, NewReactivateClusterTask(eh.taskExecutor, cur.OwnerAddress, committeeIDs(&cur), []*ssvtypes.SSVShare{&cur}))
+		}
+	}
+	for pk, cur := range after.shares {
+		cur := cur
+		if _, ok := restored[pk]; ok {
+			// Removed by a reverted block.
+			tasks = append(tasks, NewStartValidatorTask(eh.taskExecutor, &cur))
+		}
+	}
+	for owner, recipient := range after.recipients {
+		if before.recipients[owner] != recipient {
+			tasks = append(tasks, NewUpdateFeeRecipientTask(eh.taskExecutor, owner, ethcommon.Address(recipient)))
+		}
+	}
+
+	for _, task := range tasks {
+		if err := task.Execute(); err != nil {
+			eh.logger.Error("failed to execute task", fields.Type(task), zap.Error(err))
+		}
+	}
+}
+
+func committeeIDs(share *ssvtypes.SSVShare) []uint64 {
+	ids := make([]uint64, 0, len(share.Committee))
+	for _, operator := range share.Committee {
+		ids = append(ids, operator.OperatorID)
+	}
+	return ids
+}
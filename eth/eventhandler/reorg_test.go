@@ -0,0 +1,112 @@
+package eventhandler
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/eth/contract"
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/networkconfig"
+	nodestorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/operator/validator/mocks"
+	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
+	"github.com/bloxapp/ssv/utils"
+)
+
+func TestRollbackRemovedValidator(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ops, err := createOperators(4, 0)
+	require.NoError(t, err)
+
+	currentSlot := &utils.SlotValue{}
+	currentSlot.SetSlot(100)
+	network := &networkconfig.NetworkConfig{
+		Beacon: utils.SetupMockBeaconNetwork(t, currentSlot),
+	}
+	eh, _, err := setupEventHandler(t, ctx, logger, network, ops[0], false)
+	require.NoError(t, err)
+	ownPubKey := eh.operatorData.GetOperatorData().PublicKey
+
+	validatorData, err := createNewValidator(ops)
+	require.NoError(t, err)
+	sharesData, err := generateSharesData(validatorData, ops, testAddr, 0)
+	require.NoError(t, err)
+	pubKeysOffset := phase0.PublicKeyLength*len(ops) + phase0.SignatureLength
+	sharePublicKeys := splitBytes(sharesData[phase0.SignatureLength:pubKeysOffset], phase0.PublicKeyLength)
+	encryptedKeys := splitBytes(sharesData[pubKeysOffset:], len(sharesData[pubKeysOffset:])/len(ops))
+
+	// The validator is registered at block 100.
+	share := &ssvtypes.SSVShare{
+		Share: spectypes.Share{
+			OperatorID:      ops[0].id,
+			ValidatorPubKey: validatorData.masterPubKey.Serialize(),
+			SharePubKey:     sharePublicKeys[0],
+		},
+		Metadata: ssvtypes.Metadata{OwnerAddress: testAddr},
+	}
+	state := &nodestorage.RegistryState{Block: 100}
+	var operatorIDs []uint64
+	for i, op := range ops {
+		od := registrystorage.OperatorData{ID: op.id, PublicKey: op.rsaPub, OwnerAddress: testAddr}
+		if i == 0 {
+			od.PublicKey = ownPubKey
+		}
+		state.Operators = append(state.Operators, od)
+		share.Committee = append(share.Committee, &spectypes.Operator{OperatorID: op.id, PubKey: sharePublicKeys[i]})
+		operatorIDs = append(operatorIDs, op.id)
+	}
+	encodedShare, err := share.Encode()
+	require.NoError(t, err)
+	state.Shares = []*nodestorage.RegistryShare{{Share: encodedShare, EncryptedKeys: encryptedKeys}}
+	require.NoError(t, eh.ImportSnapshot(state))
+	requireKeyManagerDataToExist(t, eh, 1, validatorData)
+
+	// The validator is removed at block 101, which is later reorged out.
+	txn := eh.nodeStorage.Begin()
+	defer txn.Discard()
+	journal := newJournalTxn(txn)
+	_, err = eh.handleValidatorRemoved(journal, &contract.ContractValidatorRemoved{
+		Owner:       testAddr,
+		OperatorIds: operatorIDs,
+		PublicKey:   share.ValidatorPubKey,
+	})
+	require.NoError(t, err)
+	require.NoError(t, eh.nodeStorage.SaveLastProcessedBlock(journal, big.NewInt(101)))
+	require.NoError(t, eh.saveProcessedBlock(txn, 101, ethcommon.Hash{1}, journal.undo))
+	require.NoError(t, txn.Commit())
+	require.Nil(t, eh.nodeStorage.Shares().Get(nil, share.ValidatorPubKey))
+	requireKeyManagerDataToNotExist(t, eh, 0, validatorData)
+
+	// Rolling back restores the share along with its key, and starts the validator.
+	ctrl := gomock.NewController(t)
+	validatorCtrl := mocks.NewMockController(ctrl)
+	eh.taskExecutor = validatorCtrl
+	validatorCtrl.EXPECT().StartValidator(gomock.Any()).DoAndReturn(func(s *ssvtypes.SSVShare) error {
+		require.Equal(t, share.ValidatorPubKey, s.ValidatorPubKey)
+		require.Equal(t, sharePublicKeys[0], s.SharePubKey)
+		return nil
+	}).Times(1)
+
+	require.NoError(t, eh.Rollback(100, true))
+
+	restored := eh.nodeStorage.Shares().Get(nil, share.ValidatorPubKey)
+	require.NotNil(t, restored)
+	require.Equal(t, sharePublicKeys[0], restored.SharePubKey)
+	requireKeyManagerDataToExist(t, eh, 1, validatorData)
+
+	lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(nil)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 100, lastProcessedBlock.Uint64())
+}
@@ -0,0 +1,142 @@
+package eventhandler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	nodestorage "github.com/bloxapp/ssv/operator/storage"
+	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+// ImportSnapshot bootstraps the registry from the given registry state, which must not have been synced yet.
+// The share private keys of this operator's validators are decrypted from the state and added to the key manager.
+func (eh *EventHandler) ImportSnapshot(state *nodestorage.RegistryState) error {
+	_, found, err := eh.nodeStorage.GetLastProcessedBlock(nil)
+	if err != nil {
+		return fmt.Errorf("get last processed block: %w", err)
+	}
+	if found {
+		return errors.New("registry is already synced")
+	}
+
+	// The operator might've been registered in the imported blocks.
+	var operatorID uint64
+	ownPubKey := eh.operatorData.GetOperatorData().PublicKey
+	for _, od := range state.Operators {
+		if bytes.Equal(od.PublicKey, ownPubKey) {
+			operatorID = od.ID
+			break
+		}
+	}
+
+	imported := *state
+	imported.Shares = make([]*nodestorage.RegistryShare, 0, len(state.Shares))
+	var shareSecrets []*bls.SecretKey
+	for _, rs := range state.Shares {
+		share := &ssvtypes.SSVShare{}
+		if err := share.Decode(rs.Share); err != nil {
+			return err
+		}
+		shareSecret, err := eh.ownShare(share, operatorID, rs.EncryptedKeys)
+		if err != nil {
+			return fmt.Errorf("validator %x: %w", share.ValidatorPubKey, err)
+		}
+		if shareSecret != nil {
+			shareSecrets = append(shareSecrets, shareSecret)
+		}
+		encoded, err := share.Encode()
+		if err != nil {
+			return err
+		}
+		imported.Shares = append(imported.Shares, &nodestorage.RegistryShare{
+			Share:         encoded,
+			EncryptedKeys: rs.EncryptedKeys,
+		})
+	}
+
+	txn := eh.nodeStorage.Begin()
+	defer txn.Discard()
+
+	if err := eh.nodeStorage.ImportRegistry(txn, &imported); err != nil {
+		return fmt.Errorf("import registry: %w", err)
+	}
+
+	// The key manager isn't transactional, so the added shares are removed if the import fails,
+	// to not leave share keys behind which the registry doesn't reference.
+	var added []*bls.SecretKey
+	removeAdded := func() {
+		for _, shareSecret := range added {
+			if err := eh.keyManager.RemoveShare(shareSecret.GetPublicKey().SerializeToHexStr()); err != nil {
+				eh.logger.Error("could not remove share secret from key manager", zap.Error(err))
+			}
+		}
+	}
+	for _, shareSecret := range shareSecrets {
+		if err := eh.keyManager.AddShare(shareSecret); err != nil {
+			removeAdded()
+			return fmt.Errorf("could not add share secret to key manager: %w", err)
+		}
+		added = append(added, shareSecret)
+	}
+	if err := txn.Commit(); err != nil {
+		removeAdded()
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	if err := eh.reloadOperatorData(); err != nil {
+		return err
+	}
+
+	eh.logger.Info("imported registry snapshot",
+		fields.BlockNumber(state.Block),
+		zap.Int("operators", len(state.Operators)),
+		zap.Int("validators", len(state.Shares)),
+		zap.Int("my_validators", len(shareSecrets)))
+	return nil
+}
+
+// ownShare sets the fields of the share which are specific to the given operator, since the snapshot
+// could've been exported by another operator, and returns the operator's share private key, if it's in the committee.
+func (eh *EventHandler) ownShare(share *ssvtypes.SSVShare, operatorID uint64, encryptedKeys [][]byte) (*bls.SecretKey, error) {
+	share.OperatorID = 0
+	share.SharePubKey = nil
+	if operatorID == 0 {
+		return nil, nil
+	}
+	for i, operator := range share.Committee {
+		if operator.OperatorID != operatorID {
+			continue
+		}
+		if len(encryptedKeys) != len(share.Committee) {
+			return nil, errors.New("snapshot lacks the encrypted share keys")
+		}
+
+		operatorPrivateKey, found, err := eh.shareEncryptionKeyProvider()
+		if err != nil {
+			return nil, fmt.Errorf("could not get operator private key: %w", err)
+		}
+		if !found {
+			return nil, errors.New("could not find operator private key")
+		}
+		decryptedSharePrivateKey, err := operatorPrivateKey.Decrypt(encryptedKeys[i])
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt share private key: %w", err)
+		}
+		shareSecret := &bls.SecretKey{}
+		if err := shareSecret.SetHexString(string(decryptedSharePrivateKey)); err != nil {
+			return nil, fmt.Errorf("could not set decrypted share private key: %w", err)
+		}
+		if !bytes.Equal(shareSecret.GetPublicKey().Serialize(), operator.PubKey) {
+			return nil, errors.New("share private key does not match public key")
+		}
+
+		share.OperatorID = operatorID
+		share.SharePubKey = operator.PubKey
+		return shareSecret, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,98 @@
+package eventhandler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/networkconfig"
+	nodestorage "github.com/bloxapp/ssv/operator/storage"
+	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
+	"github.com/bloxapp/ssv/utils"
+)
+
+func TestImportSnapshot(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ops, err := createOperators(4, 0)
+	require.NoError(t, err)
+
+	currentSlot := &utils.SlotValue{}
+	currentSlot.SetSlot(100)
+	network := &networkconfig.NetworkConfig{
+		Beacon: utils.SetupMockBeaconNetwork(t, currentSlot),
+	}
+	eh, _, err := setupEventHandler(t, ctx, logger, network, ops[0], false)
+	require.NoError(t, err)
+	ownPubKey := eh.operatorData.GetOperatorData().PublicKey
+
+	validatorData, err := createNewValidator(ops)
+	require.NoError(t, err)
+	sharesData, err := generateSharesData(validatorData, ops, testAddr, 0)
+	require.NoError(t, err)
+	pubKeysOffset := phase0.PublicKeyLength*len(ops) + phase0.SignatureLength
+	sharePublicKeys := splitBytes(sharesData[phase0.SignatureLength:pubKeysOffset], phase0.PublicKeyLength)
+	encryptedKeys := splitBytes(sharesData[pubKeysOffset:], len(sharesData[pubKeysOffset:])/len(ops))
+
+	// The snapshot was exported by the second operator.
+	share := &ssvtypes.SSVShare{
+		Share: spectypes.Share{
+			OperatorID:      ops[1].id,
+			ValidatorPubKey: validatorData.masterPubKey.Serialize(),
+			SharePubKey:     sharePublicKeys[1],
+		},
+		Metadata: ssvtypes.Metadata{OwnerAddress: testAddr},
+	}
+	state := &nodestorage.RegistryState{Block: 100}
+	for i, op := range ops {
+		od := registrystorage.OperatorData{ID: op.id, PublicKey: op.rsaPub, OwnerAddress: testAddr}
+		if i == 0 {
+			od.PublicKey = ownPubKey
+		}
+		state.Operators = append(state.Operators, od)
+		share.Committee = append(share.Committee, &spectypes.Operator{OperatorID: op.id, PubKey: sharePublicKeys[i]})
+	}
+	encodedShare, err := share.Encode()
+	require.NoError(t, err)
+	state.Shares = []*nodestorage.RegistryShare{{Share: encodedShare, EncryptedKeys: encryptedKeys}}
+	nonce := registrystorage.Nonce(3)
+	state.Recipients = []*registrystorage.RecipientData{{Owner: testAddr, Nonce: &nonce}}
+
+	require.NoError(t, eh.ImportSnapshot(state))
+
+	lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(nil)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 100, lastProcessedBlock.Uint64())
+
+	// The share is imported as this operator's share, and its key is added to the key manager.
+	imported := eh.nodeStorage.Shares().Get(nil, share.ValidatorPubKey)
+	require.NotNil(t, imported)
+	require.Equal(t, ops[0].id, imported.OperatorID)
+	require.Equal(t, sharePublicKeys[0], imported.SharePubKey)
+	requireKeyManagerDataToExist(t, eh, 1, validatorData)
+
+	nextNonce, err := eh.nodeStorage.GetNextNonce(nil, testAddr)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, nextNonce)
+	require.Equal(t, ops[0].id, eh.operatorData.GetOperatorData().ID)
+
+	// The imported registry exports the same state.
+	exported, err := eh.nodeStorage.ExportRegistry(nil)
+	require.NoError(t, err)
+	require.Equal(t, state.Block, exported.Block)
+	require.Len(t, exported.Operators, len(ops))
+	require.Equal(t, state.Recipients, exported.Recipients)
+	require.Len(t, exported.Shares, 1)
+	require.Equal(t, encryptedKeys, exported.Shares[0].EncryptedKeys)
+
+	// Snapshots can't be imported into a synced registry.
+	require.ErrorContains(t, eh.ImportSnapshot(state), "registry is already synced")
+}
@@ -3,10 +3,13 @@ package eventhandler
 import (
 	"context"
 	"encoding/binary"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -197,6 +200,165 @@ func TestHandleBlockEventsStreamWithExecution(t *testing.T) {
 	require.Equal(t, happyFlow, observedLogsFlow)
 }
 
+func TestTaskJournal(t *testing.T) {
+	logger, _ := setupLogsCapture()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ops, err := createOperators(1, 0)
+	require.NoError(t, err)
+
+	eh, validatorCtrl, err := setupEventHandler(t, ctx, logger, nil, ops[0], true)
+	require.NoError(t, err)
+
+	share := &ssvtypes.SSVShare{
+		Share: spectypes.Share{
+			ValidatorPubKey: []byte{1, 2, 3},
+		},
+	}
+	require.NoError(t, eh.nodeStorage.Shares().Save(nil, share))
+
+	const block = 10
+	tasks := []Task{
+		NewStartValidatorTask(eh.taskExecutor, share),
+		// Obsolete on retry, since the validator isn't registered.
+		NewStartValidatorTask(eh.taskExecutor, &ssvtypes.SSVShare{Share: spectypes.Share{ValidatorPubKey: []byte{4, 5, 6}}}),
+	}
+	pendingTasks, err := eh.journalTasks(nil, block, tasks)
+	require.NoError(t, err)
+	require.Len(t, pendingTasks, 2)
+
+	// A failed task stays pending, and is scheduled for a retry.
+	validatorCtrl.EXPECT().StartValidator(gomock.Any()).Return(fmt.Errorf("failed to start")).Times(2)
+	for i, task := range tasks {
+		eh.executeTask(logger, pendingTasks[i], task)
+	}
+
+	stored, err := eh.nodeStorage.GetPendingTasks(nil)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	for i, task := range stored {
+		require.EqualValues(t, block, task.Block)
+		require.EqualValues(t, i, task.Index)
+		require.Equal(t, taskTypeStartValidator, task.Type)
+		require.Equal(t, 1, task.Attempts)
+		require.Equal(t, "failed to start", task.LastError)
+		require.True(t, task.NextAttempt.After(time.Now()))
+	}
+
+	// Tasks aren't retried before their next attempt.
+	eh.retryPendingTasks()
+
+	// Due tasks are retried, and obsolete tasks are dropped.
+	for _, task := range stored {
+		task.NextAttempt = time.Now().Add(-time.Second)
+		require.NoError(t, eh.nodeStorage.SavePendingTask(nil, task))
+	}
+	validatorCtrl.EXPECT().StartValidator(gomock.Any()).DoAndReturn(func(s *ssvtypes.SSVShare) error {
+		require.Equal(t, share.ValidatorPubKey, s.ValidatorPubKey)
+		return nil
+	}).Times(1)
+	eh.retryPendingTasks()
+
+	stored, err = eh.nodeStorage.GetPendingTasks(nil)
+	require.NoError(t, err)
+	require.Empty(t, stored)
+}
+
+func TestRecipientNotifier(t *testing.T) {
+	logger, _ := setupLogsCapture()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ops, err := createOperators(1, 0)
+	require.NoError(t, err)
+
+	eh, validatorCtrl, err := setupEventHandler(t, ctx, logger, nil, ops[0], true)
+	require.NoError(t, err)
+	notifier := &testRecipientNotifier{}
+	WithRecipientNotifier(notifier)(eh)
+
+	owner := ethcommon.HexToAddress("0x1")
+	share := &ssvtypes.SSVShare{Share: spectypes.Share{ValidatorPubKey: []byte{1, 2, 3}}}
+	tasks := []Task{
+		NewStartValidatorTask(eh.taskExecutor, share),
+		NewStartValidatorTask(eh.taskExecutor, share),
+		NewUpdateFeeRecipientTask(eh.taskExecutor, owner, ethcommon.HexToAddress("0x2")),
+		NewStopValidatorTask(eh.taskExecutor, []byte{4, 5, 6}),
+	}
+	pendingTasks, err := eh.journalTasks(nil, 1, tasks)
+	require.NoError(t, err)
+
+	// Only executed tasks are notified.
+	gomock.InOrder(
+		validatorCtrl.EXPECT().StartValidator(gomock.Any()).Return(fmt.Errorf("failed to start")),
+		validatorCtrl.EXPECT().StartValidator(gomock.Any()).Return(nil),
+	)
+	validatorCtrl.EXPECT().UpdateFeeRecipient(owner, gomock.Any()).Return(nil)
+	validatorCtrl.EXPECT().StopValidator(gomock.Any()).Return(nil)
+	for i, task := range tasks {
+		eh.executeTask(logger, pendingTasks[i], task)
+	}
+
+	require.Equal(t, [][]byte{share.ValidatorPubKey}, notifier.added)
+	require.Equal(t, []ethcommon.Address{owner}, notifier.owners)
+}
+
+type testRecipientNotifier struct {
+	owners []ethcommon.Address
+	added  [][]byte
+}
+
+func (n *testRecipientNotifier) FeeRecipientUpdated(owner ethcommon.Address) {
+	n.owners = append(n.owners, owner)
+}
+
+func (n *testRecipientNotifier) ValidatorsAdded(pubKeys ...[]byte) {
+	n.added = append(n.added, pubKeys...)
+}
+
+func TestTaskEncoding(t *testing.T) {
+	logger, _ := setupLogsCapture()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ops, err := createOperators(1, 0)
+	require.NoError(t, err)
+
+	eh, _, err := setupEventHandler(t, ctx, logger, nil, ops[0], true)
+	require.NoError(t, err)
+
+	owner := ethcommon.HexToAddress("0x1")
+	liquidated := &ssvtypes.SSVShare{
+		Share:    spectypes.Share{ValidatorPubKey: []byte{1}},
+		Metadata: ssvtypes.Metadata{OwnerAddress: owner, Liquidated: true},
+	}
+	require.NoError(t, eh.nodeStorage.Shares().Save(nil, liquidated))
+
+	tasks := []Task{
+		NewStopValidatorTask(eh.taskExecutor, []byte{2}),
+		NewLiquidateClusterTask(eh.taskExecutor, owner, []spectypes.OperatorID{1, 2, 3, 4}, []*ssvtypes.SSVShare{liquidated}),
+		NewExitValidatorTask(eh.taskExecutor, phase0.BLSPubKey{3}, 100, 5),
+	}
+	pendingTasks, err := eh.journalTasks(nil, 1, tasks)
+	require.NoError(t, err)
+
+	for i, pending := range pendingTasks {
+		decoded, err := eh.decodeTask(pending)
+		require.NoError(t, err)
+		require.Equal(t, tasks[i], decoded)
+	}
+
+	// Reactivating a cluster which is liquidated again since is obsolete.
+	pendingTasks, err = eh.journalTasks(nil, 2, []Task{
+		NewReactivateClusterTask(eh.taskExecutor, owner, []spectypes.OperatorID{1, 2, 3, 4}, []*ssvtypes.SSVShare{liquidated}),
+	})
+	require.NoError(t, err)
+	decoded, err := eh.decodeTask(pendingTasks[0])
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+}
+
 func setupLogsCapture() (*zap.Logger, *observer.ObservedLogs) {
 	core, logs := observer.New(zap.DebugLevel)
 	return zap.New(core), logs
@@ -0,0 +1,279 @@
+package eventhandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	nodestorage "github.com/bloxapp/ssv/operator/storage"
+	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+const (
+	// taskRetryInterval is how often pending tasks are checked for retries.
+	taskRetryInterval = 5 * time.Second
+	// taskRetryMinBackoff and taskRetryMaxBackoff bound the exponential backoff between retries of a task.
+	taskRetryMinBackoff = 5 * time.Second
+	taskRetryMaxBackoff = 5 * time.Minute
+)
+
+// Task types, as journaled in PendingTask.Type.
+const (
+	taskTypeStartValidator     = "StartValidator"
+	taskTypeStopValidator      = "StopValidator"
+	taskTypeLiquidateCluster   = "LiquidateCluster"
+	taskTypeReactivateCluster  = "ReactivateCluster"
+	taskTypeUpdateFeeRecipient = "UpdateFeeRecipient"
+	taskTypeExitValidator      = "ExitValidator"
+)
+
+// taskData is the journaled data of a task. Shares are journaled by their validator public keys
+// and loaded from storage when the task is retried, so that retries act on the current registry state.
+type taskData struct {
+	PubKeys        [][]byte               `json:"pub_keys,omitempty"`
+	Owner          ethcommon.Address      `json:"owner,omitempty"`
+	OperatorIDs    []spectypes.OperatorID `json:"operator_ids,omitempty"`
+	Recipient      ethcommon.Address      `json:"recipient,omitempty"`
+	BlockNumber    uint64                 `json:"block_number,omitempty"`
+	ValidatorIndex phase0.ValidatorIndex  `json:"validator_index,omitempty"`
+}
+
+// journalTasks saves the given tasks of a block as pending, so that they are executed even if the node crashes.
+func (eh *EventHandler) journalTasks(rw basedb.ReadWriter, block uint64, tasks []Task) ([]*nodestorage.PendingTask, error) {
+	pendingTasks := make([]*nodestorage.PendingTask, 0, len(tasks))
+	for i, task := range tasks {
+		taskType, data, err := encodeTask(task)
+		if err != nil {
+			return nil, err
+		}
+		pending := &nodestorage.PendingTask{
+			Block: block,
+			Index: uint32(i),
+			Type:  taskType,
+			Data:  data,
+		}
+		if err := eh.nodeStorage.SavePendingTask(rw, pending); err != nil {
+			return nil, fmt.Errorf("save pending task: %w", err)
+		}
+		pendingTasks = append(pendingTasks, pending)
+	}
+	return pendingTasks, nil
+}
+
+func encodeTask(task Task) (string, []byte, error) {
+	var taskType string
+	var data taskData
+	switch t := task.(type) {
+	case *StartValidatorTask:
+		taskType = taskTypeStartValidator
+		data.PubKeys = [][]byte{t.share.ValidatorPubKey}
+	case *StopValidatorTask:
+		taskType = taskTypeStopValidator
+		data.PubKeys = [][]byte{t.pubKey}
+	case *LiquidateClusterTask:
+		taskType = taskTypeLiquidateCluster
+		data.Owner = t.owner
+		data.OperatorIDs = t.operatorIDs
+		data.PubKeys = sharePubKeys(t.toLiquidate)
+	case *ReactivateClusterTask:
+		taskType = taskTypeReactivateCluster
+		data.Owner = t.owner
+		data.OperatorIDs = t.operatorIDs
+		data.PubKeys = sharePubKeys(t.toReactivate)
+	case *UpdateFeeRecipientTask:
+		taskType = taskTypeUpdateFeeRecipient
+		data.Owner = t.owner
+		data.Recipient = t.recipient
+	case *ExitValidatorTask:
+		taskType = taskTypeExitValidator
+		data.PubKeys = [][]byte{t.pubKey[:]}
+		data.BlockNumber = t.blockNumber
+		data.ValidatorIndex = t.validatorIndex
+	default:
+		return "", nil, fmt.Errorf("unknown task type %T", task)
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("marshal task: %w", err)
+	}
+	return taskType, b, nil
+}
+
+func sharePubKeys(shares []*ssvtypes.SSVShare) [][]byte {
+	pubKeys := make([][]byte, 0, len(shares))
+	for _, share := range shares {
+		pubKeys = append(pubKeys, share.ValidatorPubKey)
+	}
+	return pubKeys
+}
+
+// decodeTask rebuilds a pending task against the current registry state.
+// It returns nil if the task became obsolete, e.g. if the validator it should start was removed since.
+func (eh *EventHandler) decodeTask(pending *nodestorage.PendingTask) (Task, error) {
+	var data taskData
+	if err := json.Unmarshal(pending.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal task: %w", err)
+	}
+	shares := eh.nodeStorage.Shares()
+
+	switch pending.Type {
+	case taskTypeStartValidator:
+		share := shares.Get(nil, data.PubKeys[0])
+		if share == nil || share.Liquidated {
+			return nil, nil
+		}
+		return NewStartValidatorTask(eh.taskExecutor, share), nil
+
+	case taskTypeStopValidator:
+		if shares.Get(nil, data.PubKeys[0]) != nil {
+			// Added again since.
+			return nil, nil
+		}
+		return NewStopValidatorTask(eh.taskExecutor, data.PubKeys[0]), nil
+
+	case taskTypeLiquidateCluster, taskTypeReactivateCluster:
+		liquidate := pending.Type == taskTypeLiquidateCluster
+		var toUpdate []*ssvtypes.SSVShare
+		for _, pubKey := range data.PubKeys {
+			share := shares.Get(nil, pubKey)
+			if share != nil && share.Liquidated == liquidate {
+				toUpdate = append(toUpdate, share)
+			}
+		}
+		if len(toUpdate) == 0 {
+			return nil, nil
+		}
+		if liquidate {
+			return NewLiquidateClusterTask(eh.taskExecutor, data.Owner, data.OperatorIDs, toUpdate), nil
+		}
+		return NewReactivateClusterTask(eh.taskExecutor, data.Owner, data.OperatorIDs, toUpdate), nil
+
+	case taskTypeUpdateFeeRecipient:
+		recipient, found, err := eh.nodeStorage.GetRecipientData(nil, data.Owner)
+		if err != nil {
+			return nil, fmt.Errorf("get recipient data: %w", err)
+		}
+		if !found || ethcommon.Address(recipient.FeeRecipient) != data.Recipient {
+			// Updated again since.
+			return nil, nil
+		}
+		return NewUpdateFeeRecipientTask(eh.taskExecutor, data.Owner, data.Recipient), nil
+
+	case taskTypeExitValidator:
+		return NewExitValidatorTask(eh.taskExecutor, phase0.BLSPubKey(data.PubKeys[0]), data.BlockNumber, data.ValidatorIndex), nil
+
+	default:
+		return nil, fmt.Errorf("unknown task type %s", pending.Type)
+	}
+}
+
+// executeTask executes a journaled task, deleting it from the journal if it succeeds,
+// or scheduling its retry if it fails.
+func (eh *EventHandler) executeTask(logger *zap.Logger, pending *nodestorage.PendingTask, task Task) {
+	logger = logger.With(fields.Type(task), zap.Int("attempt", pending.Attempts+1))
+	logger.Debug("executing task")
+
+	err := task.Execute()
+	if err == nil {
+		logger.Debug("executed task")
+		eh.notifyRecipients(task)
+		if err := eh.nodeStorage.DeletePendingTask(nil, pending.Block, pending.Index); err != nil {
+			logger.Error("failed to delete executed task", zap.Error(err))
+		}
+		return
+	}
+
+	pending.Attempts++
+	pending.LastError = err.Error()
+	pending.LastAttempt = time.Now()
+	pending.NextAttempt = pending.LastAttempt.Add(taskRetryBackoff(pending.Attempts))
+	logger.Error("failed to execute task, will retry", zap.Time("next_attempt", pending.NextAttempt), zap.Error(err))
+
+	if err := eh.nodeStorage.SavePendingTask(nil, pending); err != nil {
+		logger.Error("failed to save failed task", zap.Error(err))
+	}
+}
+
+// notifyRecipients notifies the RecipientNotifier, if any, of an executed task which changes fee recipients.
+func (eh *EventHandler) notifyRecipients(task Task) {
+	if eh.recipientNotifier == nil {
+		return
+	}
+	switch t := task.(type) {
+	case *StartValidatorTask:
+		eh.recipientNotifier.ValidatorsAdded(t.share.ValidatorPubKey)
+	case *ReactivateClusterTask:
+		eh.recipientNotifier.ValidatorsAdded(sharePubKeys(t.toReactivate)...)
+	case *UpdateFeeRecipientTask:
+		eh.recipientNotifier.FeeRecipientUpdated(t.owner)
+	}
+}
+
+func taskRetryBackoff(attempts int) time.Duration {
+	backoff := taskRetryMinBackoff
+	for i := 1; i < attempts && backoff < taskRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > taskRetryMaxBackoff {
+		backoff = taskRetryMaxBackoff
+	}
+	return backoff
+}
+
+// RetryTasks executes the pending tasks left over from before a restart, and then retries failed tasks
+// with exponential backoff until they succeed or the context is done.
+func (eh *EventHandler) RetryTasks(ctx context.Context) {
+	ticker := time.NewTicker(taskRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		eh.retryPendingTasks()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (eh *EventHandler) retryPendingTasks() {
+	eh.tasksMu.Lock()
+	defer eh.tasksMu.Unlock()
+
+	pendingTasks, err := eh.nodeStorage.GetPendingTasks(nil)
+	if err != nil {
+		eh.logger.Error("failed to get pending tasks", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	for _, pending := range pendingTasks {
+		if pending.NextAttempt.After(now) {
+			continue
+		}
+		logger := eh.logger.With(fields.BlockNumber(pending.Block), zap.String("task", pending.Type))
+
+		task, err := eh.decodeTask(pending)
+		if err != nil {
+			logger.Error("failed to decode pending task", zap.Error(err))
+			continue
+		}
+		if task == nil {
+			logger.Debug("pending task is obsolete, dropping it")
+			if err := eh.nodeStorage.DeletePendingTask(nil, pending.Block, pending.Index); err != nil {
+				logger.Error("failed to delete obsolete task", zap.Error(err))
+			}
+			continue
+		}
+		eh.executeTask(logger, pending, task)
+	}
+}
@@ -32,6 +32,13 @@ type eventFilterer interface {
 	ParseClusterReactivated(log ethtypes.Log) (*contract.ContractClusterReactivated, error)
 	ParseFeeRecipientAddressUpdated(log ethtypes.Log) (*contract.ContractFeeRecipientAddressUpdated, error)
 	ParseValidatorExited(log ethtypes.Log) (*contract.ContractValidatorExited, error)
+	ParseOperatorWhitelistUpdated(log ethtypes.Log) (*contract.ContractOperatorWhitelistUpdated, error)
+	ParseOperatorFeeDeclared(log ethtypes.Log) (*contract.ContractOperatorFeeDeclared, error)
+	ParseOperatorFeeDeclarationCancelled(log ethtypes.Log) (*contract.ContractOperatorFeeDeclarationCancelled, error)
+	ParseOperatorFeeExecuted(log ethtypes.Log) (*contract.ContractOperatorFeeExecuted, error)
+	ParseOperatorWithdrawn(log ethtypes.Log) (*contract.ContractOperatorWithdrawn, error)
+	ParseClusterDeposited(log ethtypes.Log) (*contract.ContractClusterDeposited, error)
+	ParseClusterWithdrawn(log ethtypes.Log) (*contract.ContractClusterWithdrawn, error)
 }
 
 type eventByIDGetter interface {
@@ -6,30 +6,53 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"sync/atomic"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv/eth/executionclient"
 	"github.com/bloxapp/ssv/logging/fields"
 	nodestorage "github.com/bloxapp/ssv/operator/storage"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 )
 
 // TODO: check if something from these PRs need to be ported:
 // https://github.com/bloxapp/ssv/pull/1053
 
+// DefaultMaxFinalityDelay is the default number of epochs that finality may lag behind
+// before falling back to the follow distance. Normally, the finalized checkpoint is 2 epochs behind.
+const DefaultMaxFinalityDelay phase0.Epoch = 4
+
 var (
 	// ErrNodeNotReady is returned when node is not ready.
 	ErrNodeNotReady = fmt.Errorf("node not ready")
+
+	// ErrReorgTooDeep is returned when a processed block whose journal was pruned isn't in the canonical chain,
+	// so the registry can't be rolled back to the fork point.
+	ErrReorgTooDeep = fmt.Errorf("reorg is deeper than the retained processed blocks")
 )
 
 type ExecutionClient interface {
-	FetchHistoricalLogs(ctx context.Context, fromBlock uint64) (logs <-chan executionclient.BlockLogs, errors <-chan error, err error)
+	FetchHistoricalLogsUntil(ctx context.Context, fromBlock, untilBlock uint64) (logs <-chan executionclient.BlockLogs, errors <-chan error, err error)
 	StreamLogs(ctx context.Context, fromBlock uint64) <-chan executionclient.BlockLogs
+	HeaderByNumber(ctx context.Context, blockNumber *big.Int) (*ethtypes.Header, error)
 }
 
 type EventHandler interface {
 	HandleBlockEventsStream(logs <-chan executionclient.BlockLogs, executeTasks bool) (uint64, error)
+	ProcessedBlocks() ([]*nodestorage.ProcessedBlock, error)
+	Rollback(toBlock uint64, executeTasks bool) error
+}
+
+// FinalityProvider provides the latest execution block finalized by the consensus layer.
+type FinalityProvider interface {
+	FinalizedExecutionBlock(ctx context.Context) (number uint64, epoch phase0.Epoch, err error)
 }
 
 // EventSyncer syncs registry contract events from the given ExecutionClient
@@ -43,6 +66,16 @@ type EventSyncer struct {
 	metrics            metrics
 	stalenessThreshold time.Duration
 
+	// finality is set to process ongoing blocks only once they're finalized.
+	finality             FinalityProvider
+	beaconNetwork        beaconprotocol.BeaconNetwork
+	maxFinalityDelay     phase0.Epoch
+	finalityPollInterval time.Duration
+	finalityDelayed      bool
+	// finalityWaitPolled is the time in nanoseconds of the last finality poll while a block is waiting
+	// to be finalized, or zero if no block is waiting.
+	finalityWaitPolled atomic.Int64
+
 	lastProcessedBlock       uint64
 	lastProcessedBlockChange time.Time
 }
@@ -80,15 +113,41 @@ func (es *EventSyncer) Healthy(ctx context.Context) error {
 		return nil
 	}
 	if time.Since(es.lastProcessedBlockChange) > es.stalenessThreshold {
+		if polled := es.finalityWaitPolled.Load(); polled != 0 && time.Since(time.Unix(0, polled)) <= es.stalenessThreshold {
+			// Not stuck, but waiting for the next block to be finalized, which normally takes up to 2 epochs.
+			return nil
+		}
 		return fmt.Errorf("syncing is stuck at block %d", lastProcessedBlock.Uint64())
 	}
 	return nil
 }
 
 // SyncHistory reads and processes historical events since the given fromBlock.
+// If the processed blocks were reorged out of the canonical chain, they are rolled back first.
 func (es *EventSyncer) SyncHistory(ctx context.Context, fromBlock uint64) (lastProcessedBlock uint64, err error) {
-	fetchLogs, fetchError, err := es.executionClient.FetchHistoricalLogs(ctx, fromBlock)
+	forkBlock, reorged, err := es.rollbackReorg(ctx, false)
+	if err != nil {
+		return 0, err
+	}
+	if reorged && forkBlock+1 < fromBlock {
+		fromBlock = forkBlock + 1
+	}
+
+	untilBlock := uint64(math.MaxUint64)
+	if es.finality != nil {
+		// Blocks after the finalized checkpoint are synced by SyncOngoing once they're finalized.
+		untilBlock, err = es.historyBound(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	fetchLogs, fetchError, err := es.executionClient.FetchHistoricalLogsUntil(ctx, fromBlock, untilBlock)
 	if errors.Is(err, executionclient.ErrNothingToSync) {
+		if reorged {
+			// Nothing to sync after the rollback, should keep ongoing sync from the fork point.
+			return forkBlock, nil
+		}
 		// Nothing to sync, should keep ongoing sync from the given fromBlock.
 		return 0, executionclient.ErrNothingToSync
 	}
@@ -120,10 +179,210 @@ func (es *EventSyncer) SyncHistory(ctx context.Context, fromBlock uint64) (lastP
 }
 
 // SyncOngoing streams and processes ongoing events as they come since the given fromBlock.
+// Before processing each block, the processed blocks are checked against the canonical chain,
+// and if they were reorged out, they are rolled back and streaming restarts from the fork point.
 func (es *EventSyncer) SyncOngoing(ctx context.Context, fromBlock uint64) error {
-	es.logger.Info("subscribing to ongoing registry events", fields.FromBlock(fromBlock))
+	for {
+		es.logger.Info("subscribing to ongoing registry events", fields.FromBlock(fromBlock))
+
+		forkBlock, reorged, err := es.syncOngoing(ctx, fromBlock)
+		if err != nil || !reorged {
+			return err
+		}
+		fromBlock = forkBlock + 1
+	}
+}
+
+// syncOngoing processes the streamed blocks one at a time until the stream ends or a reorg is rolled back.
+func (es *EventSyncer) syncOngoing(ctx context.Context, fromBlock uint64) (forkBlock uint64, reorged bool, err error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	logs := es.executionClient.StreamLogs(streamCtx, fromBlock)
+	defer func() {
+		cancel()
+		// Unblock the stream until it notices the cancellation.
+		go func() {
+			for range logs {
+			}
+		}()
+	}()
+
+	// verified is set once the processed blocks were checked against the canonical chain,
+	// after which only blocks which the stream couldn't link to the previous block are checked again.
+	verified := false
+	for blockLogs := range logs {
+		if es.finality != nil {
+			if err := es.waitFinalized(ctx, blockLogs.BlockNumber); err != nil {
+				return 0, false, err
+			}
+		}
+
+		if !verified || !blockLogs.Linked {
+			forkBlock, reorged, err = es.rollbackReorg(ctx, true)
+			if errors.Is(err, ErrReorgTooDeep) {
+				return 0, false, err
+			}
+			if err != nil {
+				// The next block is checked again, so a temporary failure doesn't stop syncing.
+				es.logger.Warn("could not check for reorg", fields.BlockNumber(blockLogs.BlockNumber), zap.Error(err))
+			}
+			if reorged {
+				return forkBlock, true, nil
+			}
+			verified = err == nil
+		}
+		if es.finality != nil {
+			// The block may have been reorged out while waiting for it to be finalized,
+			// in which case streaming restarts from it to fetch its canonical logs.
+			canonical, err := es.isCanonical(ctx, blockLogs)
+			if err != nil {
+				return 0, false, err
+			}
+			if !canonical {
+				es.logger.Warn("streamed block was reorged out, restarting from it", fields.BlockNumber(blockLogs.BlockNumber))
+				return blockLogs.BlockNumber - 1, true, nil
+			}
+		}
+
+		block := make(chan executionclient.BlockLogs, 1)
+		block <- blockLogs
+		close(block)
+		if _, err := es.eventHandler.HandleBlockEventsStream(block, true); err != nil {
+			return 0, false, err
+		}
+	}
+	return 0, false, nil
+}
 
-	logs := es.executionClient.StreamLogs(ctx, fromBlock)
-	_, err := es.eventHandler.HandleBlockEventsStream(logs, true)
-	return err
+// waitFinalized waits until the given block is finalized by the consensus layer,
+// or returns right away if finality is delayed by more than maxFinalityDelay,
+// in which case blocks are processed at the follow distance of the execution client.
+func (es *EventSyncer) waitFinalized(ctx context.Context, blockNumber uint64) error {
+	defer es.finalityWaitPolled.Store(0)
+	for {
+		finalizedBlock, delayed, err := es.finalizedBlock(ctx)
+		if err != nil {
+			es.logger.Warn("could not get finalized checkpoint", zap.Error(err))
+		} else {
+			if delayed || finalizedBlock >= blockNumber {
+				return nil
+			}
+			es.finalityWaitPolled.Store(time.Now().UnixNano())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(es.finalityPollInterval):
+		}
+	}
+}
+
+// historyBound returns the last block to sync historical events up to, which is the finalized block,
+// unless finality is delayed by more than maxFinalityDelay.
+func (es *EventSyncer) historyBound(ctx context.Context) (uint64, error) {
+	for {
+		finalizedBlock, delayed, err := es.finalizedBlock(ctx)
+		if err == nil {
+			if delayed {
+				return math.MaxUint64, nil
+			}
+			return finalizedBlock, nil
+		}
+		es.logger.Warn("could not get finalized checkpoint", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(es.finalityPollInterval):
+		}
+	}
+}
+
+// finalizedBlock returns the latest finalized execution block,
+// and whether finality is delayed by more than maxFinalityDelay.
+func (es *EventSyncer) finalizedBlock(ctx context.Context) (number uint64, delayed bool, err error) {
+	number, finalizedEpoch, err := es.finality.FinalizedExecutionBlock(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	currentEpoch := es.beaconNetwork.EstimatedCurrentEpoch()
+	delayed = currentEpoch > finalizedEpoch && currentEpoch-finalizedEpoch > es.maxFinalityDelay
+	es.setFinalityDelayed(delayed, finalizedEpoch)
+	return number, delayed, nil
+}
+
+func (es *EventSyncer) setFinalityDelayed(delayed bool, finalizedEpoch phase0.Epoch) {
+	if delayed == es.finalityDelayed {
+		return
+	}
+	es.finalityDelayed = delayed
+	if delayed {
+		es.logger.Warn("finality is delayed, falling back to follow distance", fields.Epoch(finalizedEpoch))
+	} else {
+		es.logger.Info("finality recovered, following finalized checkpoint", fields.Epoch(finalizedEpoch))
+	}
+}
+
+// isCanonical returns whether the given block is in the canonical chain.
+func (es *EventSyncer) isCanonical(ctx context.Context, block executionclient.BlockLogs) (bool, error) {
+	if block.BlockHash == (ethcommon.Hash{}) {
+		return true, nil
+	}
+	header, err := es.executionClient.HeaderByNumber(ctx, new(big.Int).SetUint64(block.BlockNumber))
+	if err != nil {
+		return false, fmt.Errorf("failed to get header of block %d: %w", block.BlockNumber, err)
+	}
+	return header.Hash() == block.BlockHash, nil
+}
+
+// rollbackReorg rolls back the processed blocks which were reorged out of the canonical chain, if any,
+// and returns the fork point, which is the last processed block remaining in the canonical chain.
+func (es *EventSyncer) rollbackReorg(ctx context.Context, executeTasks bool) (forkBlock uint64, reorged bool, err error) {
+	forkBlock, reorged, err = es.findForkPoint(ctx)
+	if err != nil || !reorged {
+		return forkBlock, reorged, err
+	}
+
+	es.logger.Warn("execution chain reorg detected", zap.Uint64("fork_block", forkBlock))
+	if err := es.eventHandler.Rollback(forkBlock, executeTasks); err != nil {
+		return 0, false, fmt.Errorf("failed to roll back reorged blocks: %w", err)
+	}
+	es.metrics.LastBlockProcessed(forkBlock)
+	return forkBlock, true, nil
+}
+
+// findForkPoint returns the newest processed block which is still in the canonical chain,
+// and whether any processed block after it was reorged out.
+// Since only blocks with events are retained, if none of the retained blocks is canonical,
+// the fork point is assumed to precede the oldest of them, unless its journal was pruned,
+// in which case the reorg is deeper than the reorg window.
+func (es *EventSyncer) findForkPoint(ctx context.Context) (forkBlock uint64, reorged bool, err error) {
+	blocks, err := es.eventHandler.ProcessedBlocks()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get processed blocks: %w", err)
+	}
+
+	var oldest *nodestorage.ProcessedBlock
+	for i := len(blocks) - 1; i >= 0; i-- {
+		block := blocks[i]
+		if block.Hash == (ethcommon.Hash{}) {
+			// Hash is unknown, e.g. for blocks processed by older versions.
+			continue
+		}
+		header, err := es.executionClient.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to get header of block %d: %w", block.Number, err)
+		}
+		if header.Hash() == block.Hash {
+			return block.Number, i != len(blocks)-1, nil
+		}
+		if block.Pruned {
+			return 0, false, ErrReorgTooDeep
+		}
+		oldest = block
+	}
+	if oldest != nil {
+		return oldest.Number - 1, true, nil
+	}
+	return 0, false, nil
 }
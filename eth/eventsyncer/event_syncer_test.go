@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/bloxapp/ssv/operator/validatorsmap"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -115,7 +117,7 @@ func TestEventSyncer(t *testing.T) {
 		require.Equal(t, uint64(0x1), receipt.Status)
 	}
 
-	eh := setupEventHandler(t, ctx, logger)
+	eh, _ := setupEventHandler(t, ctx, logger)
 	eventSyncer := New(
 		nil,
 		client,
@@ -129,7 +131,338 @@ func TestEventSyncer(t *testing.T) {
 	require.NoError(t, eventSyncer.SyncOngoing(ctx, lastProcessedBlock+1))
 }
 
-func setupEventHandler(t *testing.T, ctx context.Context, logger *zap.Logger) *eventhandler.EventHandler {
+func TestEventSyncerReorg(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const testTimeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	sim := simTestBackend(testAddr)
+
+	rpcServer, _ := sim.Node.RPCHandler()
+	httpSrv := httptest.NewServer(rpcServer.WebsocketHandler([]string{"*"}))
+	defer rpcServer.Stop()
+	defer httpSrv.Close()
+
+	parsed, _ := abi.JSON(strings.NewReader(simcontract.SimcontractMetaData.ABI))
+	auth, _ := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+	contractAddr, _, _, err := bind.DeployContract(auth, parsed, ethcommon.FromHex(simcontract.SimcontractMetaData.Bin), sim)
+	require.NoError(t, err)
+	sim.Commit()
+
+	boundContract, err := simcontract.NewSimcontract(contractAddr, sim)
+	require.NoError(t, err)
+
+	addr := "ws:" + strings.TrimPrefix(httpSrv.URL, "http:")
+	client, err := executionclient.New(ctx, addr, contractAddr,
+		executionclient.WithLogger(logger),
+		executionclient.WithFollowDistance(0))
+	require.NoError(t, err)
+	defer client.Close()
+
+	registerOperator := func() []byte {
+		pubKey, _, err := rsaencryption.GenerateKeys()
+		require.NoError(t, err)
+		pkstr := base64.StdEncoding.EncodeToString(pubKey)
+		packed, err := eventparser.PackOperatorPublicKey([]byte(pkstr))
+		require.NoError(t, err)
+		_, err = boundContract.SimcontractTransactor.RegisterOperator(auth, packed, big.NewInt(100_000_000))
+		require.NoError(t, err)
+		return []byte(pkstr)
+	}
+
+	// Block 2 stays in the canonical chain, block 3 is reorged out.
+	kept := registerOperator()
+	forkBlockHash := sim.Commit()
+	reorged := registerOperator()
+	sim.Commit()
+
+	eh, nodeStorage := setupEventHandler(t, ctx, logger)
+	eventSyncer := New(nodeStorage, client, eh, WithLogger(logger))
+
+	lastProcessedBlock, err := eventSyncer.SyncHistory(ctx, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, lastProcessedBlock)
+
+	// Build a longer side chain from block 2.
+	require.NoError(t, sim.Fork(ctx, forkBlockHash))
+	added := registerOperator()
+	sim.Commit()
+	sim.Commit()
+
+	lastProcessedBlock, err = eventSyncer.SyncHistory(ctx, lastProcessedBlock+1)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, lastProcessedBlock)
+
+	for pubKey, expected := range map[string]bool{string(kept): true, string(reorged): false, string(added): true} {
+		_, found, err := nodeStorage.GetOperatorDataByPubKey(nil, []byte(pubKey))
+		require.NoError(t, err)
+		require.Equal(t, expected, found)
+	}
+
+	blocks, err := eh.ProcessedBlocks()
+	require.NoError(t, err)
+	require.NotEmpty(t, blocks)
+	for _, block := range blocks {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+		require.NoError(t, err)
+		require.Equal(t, header.Hash(), block.Hash)
+	}
+}
+
+func TestEventSyncerReorgBeforeRetainedBlocks(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const testTimeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	setup := func(t *testing.T) (*simulator.SimulatedBackend, *executionclient.ExecutionClient, func() []byte, ethcommon.Hash) {
+		sim := simTestBackend(testAddr)
+
+		rpcServer, _ := sim.Node.RPCHandler()
+		httpSrv := httptest.NewServer(rpcServer.WebsocketHandler([]string{"*"}))
+		t.Cleanup(httpSrv.Close)
+		t.Cleanup(rpcServer.Stop)
+
+		parsed, _ := abi.JSON(strings.NewReader(simcontract.SimcontractMetaData.ABI))
+		auth, _ := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+		contractAddr, _, _, err := bind.DeployContract(auth, parsed, ethcommon.FromHex(simcontract.SimcontractMetaData.Bin), sim)
+		require.NoError(t, err)
+		deployBlockHash := sim.Commit()
+
+		boundContract, err := simcontract.NewSimcontract(contractAddr, sim)
+		require.NoError(t, err)
+
+		addr := "ws:" + strings.TrimPrefix(httpSrv.URL, "http:")
+		client, err := executionclient.New(ctx, addr, contractAddr,
+			executionclient.WithLogger(logger),
+			executionclient.WithFollowDistance(0))
+		require.NoError(t, err)
+		t.Cleanup(func() { client.Close() })
+
+		registerOperator := func() []byte {
+			pubKey, _, err := rsaencryption.GenerateKeys()
+			require.NoError(t, err)
+			pkstr := base64.StdEncoding.EncodeToString(pubKey)
+			packed, err := eventparser.PackOperatorPublicKey([]byte(pkstr))
+			require.NoError(t, err)
+			_, err = boundContract.SimcontractTransactor.RegisterOperator(auth, packed, big.NewInt(100_000_000))
+			require.NoError(t, err)
+			return []byte(pkstr)
+		}
+		return sim, client, registerOperator, deployBlockHash
+	}
+
+	requireOperators := func(t *testing.T, nodeStorage operatorstorage.Storage, expected map[string]bool) {
+		for pubKey, exists := range expected {
+			_, found, err := nodeStorage.GetOperatorDataByPubKey(nil, []byte(pubKey))
+			require.NoError(t, err)
+			require.Equal(t, exists, found)
+		}
+	}
+
+	t.Run("only retained block is reorged", func(t *testing.T) {
+		sim, client, registerOperator, deployBlockHash := setup(t)
+
+		// Block 2 is the only block with events, and is reorged out.
+		reorged := registerOperator()
+		sim.Commit()
+
+		eh, nodeStorage := setupEventHandler(t, ctx, logger)
+		eventSyncer := New(nodeStorage, client, eh, WithLogger(logger))
+
+		lastProcessedBlock, err := eventSyncer.SyncHistory(ctx, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, lastProcessedBlock)
+
+		require.NoError(t, sim.Fork(ctx, deployBlockHash))
+		added := registerOperator()
+		sim.Commit()
+		sim.Commit()
+
+		lastProcessedBlock, err = eventSyncer.SyncHistory(ctx, lastProcessedBlock+1)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, lastProcessedBlock)
+		requireOperators(t, nodeStorage, map[string]bool{string(reorged): false, string(added): true})
+	})
+
+	t.Run("pruned block is the fork point", func(t *testing.T) {
+		sim, client, registerOperator, _ := setup(t)
+
+		// Block 2 falls out of the reorg window once block 5 is processed, and stays canonical.
+		kept := registerOperator()
+		forkBlockHash := sim.Commit()
+		sim.Commit()
+		sim.Commit()
+		reorged := registerOperator()
+		sim.Commit()
+
+		eh, nodeStorage := setupEventHandler(t, ctx, logger, eventhandler.WithReorgWindow(1))
+		eventSyncer := New(nodeStorage, client, eh, WithLogger(logger))
+
+		lastProcessedBlock, err := eventSyncer.SyncHistory(ctx, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, lastProcessedBlock)
+
+		blocks, err := eh.ProcessedBlocks()
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+		require.True(t, blocks[0].Pruned)
+		require.Empty(t, blocks[0].Undo)
+
+		require.NoError(t, sim.Fork(ctx, forkBlockHash))
+		added := registerOperator()
+		for i := 0; i < 4; i++ {
+			sim.Commit()
+		}
+
+		_, err = eventSyncer.SyncHistory(ctx, lastProcessedBlock+1)
+		require.NoError(t, err)
+		requireOperators(t, nodeStorage, map[string]bool{string(kept): true, string(reorged): false, string(added): true})
+	})
+
+	t.Run("reorg deeper than the reorg window", func(t *testing.T) {
+		sim, client, registerOperator, deployBlockHash := setup(t)
+
+		registerOperator()
+		sim.Commit()
+		sim.Commit()
+		sim.Commit()
+		registerOperator()
+		sim.Commit()
+
+		eh, nodeStorage := setupEventHandler(t, ctx, logger, eventhandler.WithReorgWindow(1))
+		eventSyncer := New(nodeStorage, client, eh, WithLogger(logger))
+
+		lastProcessedBlock, err := eventSyncer.SyncHistory(ctx, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, lastProcessedBlock)
+
+		require.NoError(t, sim.Fork(ctx, deployBlockHash))
+		for i := 0; i < 6; i++ {
+			sim.Commit()
+		}
+
+		_, err = eventSyncer.SyncHistory(ctx, lastProcessedBlock+1)
+		require.ErrorIs(t, err, ErrReorgTooDeep)
+	})
+}
+
+// testFinality is a FinalityProvider with a settable finalized checkpoint.
+type testFinality struct {
+	mu    sync.Mutex
+	block uint64
+	epoch phase0.Epoch
+}
+
+func (f *testFinality) FinalizedExecutionBlock(ctx context.Context) (uint64, phase0.Epoch, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.block, f.epoch, nil
+}
+
+func (f *testFinality) set(block uint64, epoch phase0.Epoch) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.block = block
+	f.epoch = epoch
+}
+
+func TestEventSyncerFinalizedCheckpoint(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const testTimeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	sim := simTestBackend(testAddr)
+
+	rpcServer, _ := sim.Node.RPCHandler()
+	httpSrv := httptest.NewServer(rpcServer.WebsocketHandler([]string{"*"}))
+	defer rpcServer.Stop()
+	defer httpSrv.Close()
+
+	parsed, _ := abi.JSON(strings.NewReader(simcontract.SimcontractMetaData.ABI))
+	auth, _ := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+	contractAddr, _, _, err := bind.DeployContract(auth, parsed, ethcommon.FromHex(simcontract.SimcontractMetaData.Bin), sim)
+	require.NoError(t, err)
+	sim.Commit()
+
+	boundContract, err := simcontract.NewSimcontract(contractAddr, sim)
+	require.NoError(t, err)
+
+	addr := "ws:" + strings.TrimPrefix(httpSrv.URL, "http:")
+	client, err := executionclient.New(ctx, addr, contractAddr,
+		executionclient.WithLogger(logger),
+		executionclient.WithFollowDistance(0))
+	require.NoError(t, err)
+	defer client.Close()
+
+	registerOperator := func() []byte {
+		pubKey, _, err := rsaencryption.GenerateKeys()
+		require.NoError(t, err)
+		pkstr := base64.StdEncoding.EncodeToString(pubKey)
+		packed, err := eventparser.PackOperatorPublicKey([]byte(pkstr))
+		require.NoError(t, err)
+		_, err = boundContract.SimcontractTransactor.RegisterOperator(auth, packed, big.NewInt(100_000_000))
+		require.NoError(t, err)
+		sim.Commit()
+		return []byte(pkstr)
+	}
+	operatorExists := func(nodeStorage operatorstorage.Storage, pubKey []byte) func() bool {
+		return func() bool {
+			_, found, err := nodeStorage.GetOperatorDataByPubKey(nil, pubKey)
+			require.NoError(t, err)
+			return found
+		}
+	}
+
+	registerOperator()
+
+	beaconNetwork := networkconfig.TestNetwork.Beacon
+	currentEpoch := beaconNetwork.EstimatedCurrentEpoch()
+	finality := &testFinality{block: 2, epoch: currentEpoch}
+
+	eh, nodeStorage := setupEventHandler(t, ctx, logger)
+	eventSyncer := New(nodeStorage, client, eh,
+		WithLogger(logger),
+		WithFinalizedCheckpoint(finality, beaconNetwork, DefaultMaxFinalityDelay))
+	eventSyncer.finalityPollInterval = 10 * time.Millisecond
+	eventSyncer.stalenessThreshold = 200 * time.Millisecond
+
+	// History isn't synced beyond the finalized block 2.
+	pending := registerOperator()
+	lastProcessedBlock, err := eventSyncer.SyncHistory(ctx, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, lastProcessedBlock)
+	require.False(t, operatorExists(nodeStorage, pending)())
+
+	syncCtx, stopSync := context.WithCancel(ctx)
+	syncDone := make(chan error)
+	go func() {
+		syncDone <- eventSyncer.SyncOngoing(syncCtx, lastProcessedBlock+1)
+	}()
+
+	// Block 3 isn't processed until it's finalized, while the syncer remains healthy.
+	require.NoError(t, eventSyncer.Healthy(ctx))
+	require.Eventually(t, func() bool {
+		sim.Commit() // Heads are needed for block 3 to be streamed.
+		return eventSyncer.finalityWaitPolled.Load() != 0
+	}, 2*time.Second, 50*time.Millisecond)
+	require.Never(t, operatorExists(nodeStorage, pending), 500*time.Millisecond, 50*time.Millisecond)
+	require.NoError(t, eventSyncer.Healthy(ctx))
+	finality.set(3, currentEpoch)
+	require.Eventually(t, operatorExists(nodeStorage, pending), 2*time.Second, 50*time.Millisecond)
+
+	// When finality is delayed, blocks are processed at the follow distance.
+	finality.set(3, currentEpoch-DefaultMaxFinalityDelay-1)
+	delayed := registerOperator()
+	require.Eventually(t, operatorExists(nodeStorage, delayed), 2*time.Second, 50*time.Millisecond)
+
+	stopSync()
+	require.NoError(t, <-syncDone)
+}
+
+func setupEventHandler(t *testing.T, ctx context.Context, logger *zap.Logger, opts ...eventhandler.Option) (*eventhandler.EventHandler, operatorstorage.Storage) {
 	db, err := kv.NewInMemory(logger, basedb.Options{
 		Ctx: ctx,
 	})
@@ -139,7 +472,7 @@ func setupEventHandler(t *testing.T, ctx context.Context, logger *zap.Logger) *e
 	nodeStorage, operatorData := setupOperatorStorage(logger, db)
 	testNetworkConfig := networkconfig.TestNetwork
 
-	keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, testNetworkConfig, true, "")
+	keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, testNetworkConfig, "")
 	if err != nil {
 		logger.Fatal("could not create new eth-key-manager signer", zap.Error(err))
 	}
@@ -171,13 +504,15 @@ func setupEventHandler(t *testing.T, ctx context.Context, logger *zap.Logger) *e
 		keyManager,
 		bc,
 		storageMap,
-		eventhandler.WithFullNode(),
-		eventhandler.WithLogger(logger))
+		append([]eventhandler.Option{
+			eventhandler.WithFullNode(),
+			eventhandler.WithLogger(logger),
+		}, opts...)...)
 
 	if err != nil {
 		t.Fatal(err)
 	}
-	return eh
+	return eh, nodeStorage
 }
 
 func simTestBackend(testAddr ethcommon.Address) *simulator.SimulatedBackend {
@@ -3,7 +3,10 @@ package eventsyncer
 import (
 	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"go.uber.org/zap"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 )
 
 // Option defines EventSyncer configuration option.
@@ -28,3 +31,15 @@ func WithStalenessThreshold(threshold time.Duration) Option {
 		es.stalenessThreshold = threshold
 	}
 }
+
+// WithFinalizedCheckpoint makes ongoing sync process each block only once the consensus layer has finalized it,
+// so that all operators see the same registry state. If finality is delayed by more than maxFinalityDelay epochs,
+// blocks are processed at the follow distance of the execution client until finality recovers.
+func WithFinalizedCheckpoint(provider FinalityProvider, beaconNetwork beaconprotocol.BeaconNetwork, maxFinalityDelay phase0.Epoch) Option {
+	return func(es *EventSyncer) {
+		es.finality = provider
+		es.beaconNetwork = beaconNetwork
+		es.maxFinalityDelay = maxFinalityDelay
+		es.finalityPollInterval = beaconNetwork.SlotDurationSec()
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 // ExecutionOptions contains config configurations related to Ethereum execution client.
 type ExecutionOptions struct {
-	Addr              string        `yaml:"ETH1Addr" env:"ETH_1_ADDR" env-required:"true" env-description:"Execution client WebSocket address"`
+	Addr              string        `yaml:"ETH1Addr" env:"ETH_1_ADDR" env-required:"true" env-description:"Execution client WebSocket address, or a comma-separated list of addresses to fail over between"`
 	ConnectionTimeout time.Duration `yaml:"ETH1ConnectionTimeout" env:"ETH_1_CONNECTION_TIMEOUT" env-default:"10s" env-description:"Execution client connection timeout"`
+	FollowFinalized   bool          `yaml:"ETH1FollowFinalized" env:"ETH_1_FOLLOW_FINALIZED" env-default:"false" env-description:"Process registry events only once the consensus client finalized their block, falling back to the follow distance when finality is delayed"`
 }
@@ -5,7 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -27,29 +30,51 @@ var (
 )
 
 // ExecutionClient represents a client for interacting with Ethereum execution client.
+// It may be configured with multiple endpoints, in which case requests fail over
+// to the next endpoint whenever the current one fails.
 type ExecutionClient struct {
 	// mandatory
-	nodeAddr        string
+	endpoints       []*endpoint
 	contractAddress ethcommon.Address
 
 	// optional
 	logger                      *zap.Logger
 	metrics                     metrics
-	followDistance              uint64 // TODO: consider reading the finalized checkpoint from consensus layer
+	followDistance              uint64 // EventSyncer may further wait for the finalized checkpoint of the consensus layer
 	connectionTimeout           time.Duration
 	reconnectionInitialInterval time.Duration
 	reconnectionMaxInterval     time.Duration
 	logBatchSize                uint64
 
 	// variables
-	client *ethclient.Client
-	closed chan struct{}
+	clientMu   sync.RWMutex
+	client     *ethclient.Client // client of the current endpoint
+	current    int               // index of the current endpoint
+	failoverMu sync.Mutex        // serializes failing over and reconnecting
+	closed     chan struct{}
+}
+
+// endpoint is a single execution client endpoint.
+type endpoint struct {
+	addr   string
+	client *ethclient.Client // lazily dialed, nil if not connected
 }
 
 // New creates a new instance of ExecutionClient.
+// nodeAddr may contain multiple comma-separated addresses to fail over between.
 func New(ctx context.Context, nodeAddr string, contractAddr ethcommon.Address, opts ...Option) (*ExecutionClient, error) {
+	var endpoints []*endpoint
+	for _, addr := range strings.Split(nodeAddr, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			endpoints = append(endpoints, &endpoint{addr: addr})
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("%w: no execution client address", ErrBadInput)
+	}
+
 	client := &ExecutionClient{
-		nodeAddr:                    nodeAddr,
+		endpoints:                   endpoints,
 		contractAddress:             contractAddr,
 		logger:                      zap.NewNop(),
 		metrics:                     nopMetrics{},
@@ -73,13 +98,30 @@ func New(ctx context.Context, nodeAddr string, contractAddr ethcommon.Address, o
 // Close shuts down ExecutionClient.
 func (ec *ExecutionClient) Close() error {
 	close(ec.closed)
-	ec.client.Close()
+
+	ec.clientMu.Lock()
+	defer ec.clientMu.Unlock()
+	for _, e := range ec.endpoints {
+		if e.client != nil {
+			e.client.Close()
+		}
+	}
 	return nil
 }
 
 // FetchHistoricalLogs retrieves historical logs emitted by the contract starting from fromBlock.
 func (ec *ExecutionClient) FetchHistoricalLogs(ctx context.Context, fromBlock uint64) (logs <-chan BlockLogs, errors <-chan error, err error) {
-	currentBlock, err := ec.client.BlockNumber(ctx)
+	return ec.FetchHistoricalLogsUntil(ctx, fromBlock, math.MaxUint64)
+}
+
+// FetchHistoricalLogsUntil retrieves historical logs emitted by the contract starting from fromBlock,
+// up to untilBlock or the follow distance, whichever is lower.
+func (ec *ExecutionClient) FetchHistoricalLogsUntil(ctx context.Context, fromBlock, untilBlock uint64) (logs <-chan BlockLogs, errors <-chan error, err error) {
+	var currentBlock uint64
+	err = ec.withFailover(ctx, func(client *ethclient.Client) (err error) {
+		currentBlock, err = client.BlockNumber(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get current block: %w", err)
 	}
@@ -87,16 +129,20 @@ func (ec *ExecutionClient) FetchHistoricalLogs(ctx context.Context, fromBlock ui
 		return nil, nil, ErrNothingToSync
 	}
 	toBlock := currentBlock - ec.followDistance
+	if toBlock > untilBlock {
+		toBlock = untilBlock
+	}
 	if toBlock < fromBlock {
 		return nil, nil, ErrNothingToSync
 	}
 
-	logs, errors = ec.fetchLogsInBatches(ctx, fromBlock, toBlock)
+	logs, errors = ec.fetchLogsInBatches(ctx, fromBlock, toBlock, nil)
 	return
 }
 
-// Calls FilterLogs multiple times and batches results to avoid fetching enormous amount of events
-func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, endBlock uint64) (<-chan BlockLogs, <-chan error) {
+// Calls FilterLogs multiple times and batches results to avoid fetching enormous amount of events.
+// If headers is given, the headers of empty batches are taken from it when available.
+func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, endBlock uint64, headers *headerChain) (<-chan BlockLogs, <-chan error) {
 	logs := make(chan BlockLogs, defaultLogBuf)
 	errors := make(chan error, 1)
 
@@ -116,10 +162,14 @@ func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, e
 			}
 
 			start := time.Now()
-			results, err := ec.client.FilterLogs(ctx, ethereum.FilterQuery{
-				Addresses: []ethcommon.Address{ec.contractAddress},
-				FromBlock: new(big.Int).SetUint64(fromBlock),
-				ToBlock:   new(big.Int).SetUint64(toBlock),
+			var results []ethtypes.Log
+			err := ec.withFailover(ctx, func(client *ethclient.Client) (err error) {
+				results, err = client.FilterLogs(ctx, ethereum.FilterQuery{
+					Addresses: []ethcommon.Address{ec.contractAddress},
+					FromBlock: new(big.Int).SetUint64(fromBlock),
+					ToBlock:   new(big.Int).SetUint64(toBlock),
+				})
+				return err
 			})
 			if err != nil {
 				errors <- err
@@ -159,7 +209,19 @@ func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, e
 				}
 				if len(validLogs) == 0 {
 					// Emit empty block logs to indicate that we have advanced to this block.
-					logs <- BlockLogs{BlockNumber: toBlock}
+					var header *ethtypes.Header
+					if headers != nil {
+						header = headers.header(toBlock)
+					}
+					if header == nil {
+						var err error
+						header, err = ec.HeaderByNumber(ctx, new(big.Int).SetUint64(toBlock))
+						if err != nil {
+							errors <- fmt.Errorf("failed to get block header: %w", err)
+							return
+						}
+					}
+					logs <- BlockLogs{BlockNumber: toBlock, BlockHash: header.Hash()}
 				} else {
 					for _, blockLogs := range PackLogs(validLogs) {
 						logs <- blockLogs
@@ -180,6 +242,7 @@ func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-c
 
 	go func() {
 		defer close(logs)
+		headers := newHeaderChain()
 		tries := 0
 		for {
 			select {
@@ -188,7 +251,8 @@ func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-c
 			case <-ec.closed:
 				return
 			default:
-				lastBlock, err := ec.streamLogsToChan(ctx, logs, fromBlock)
+				client := ec.currentClient()
+				lastBlock, err := ec.streamLogsToChan(ctx, client, headers, logs, fromBlock)
 				if errors.Is(err, ErrClosed) || errors.Is(err, context.Canceled) {
 					// Closed gracefully.
 					return
@@ -210,7 +274,7 @@ func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-c
 				}
 
 				ec.logger.Error("failed to stream registry events, reconnecting", zap.Error(err))
-				ec.reconnect(ctx)
+				ec.reconnect(ctx, client)
 				fromBlock = lastBlock + 1
 			}
 		}
@@ -220,32 +284,92 @@ func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-c
 }
 
 // Healthy returns if execution client is currently healthy: responds to requests and not in the syncing state.
+// With multiple endpoints, it's enough for any endpoint to be healthy, since requests fail over to it.
 func (ec *ExecutionClient) Healthy(ctx context.Context) error {
 	if ec.isClosed() {
 		return ErrClosed
 	}
 
+	if len(ec.endpoints) == 1 {
+		ctx, cancel := context.WithTimeout(ctx, ec.connectionTimeout)
+		defer cancel()
+
+		err := ec.clientHealthy(ctx, ec.currentClient())
+		ec.reportHealth(err)
+		return err
+	}
+
+	var errs []string
+	for addr, err := range ec.EndpointsHealthy(ctx) {
+		if err != nil {
+			ec.logger.Warn("execution client endpoint is not healthy", fields.Address(addr), zap.Error(err))
+			errs = append(errs, fmt.Sprintf("%s: %s", addr, err))
+		}
+	}
+	if len(errs) == len(ec.endpoints) {
+		ec.metrics.ExecutionClientFailure()
+		return fmt.Errorf("no healthy endpoints: %s", strings.Join(errs, "; "))
+	}
+
+	ec.metrics.ExecutionClientReady()
+	return nil
+}
+
+// EndpointsHealthy returns the health of each endpoint by its address.
+func (ec *ExecutionClient) EndpointsHealthy(ctx context.Context) map[string]error {
 	ctx, cancel := context.WithTimeout(ctx, ec.connectionTimeout)
 	defer cancel()
 
-	sp, err := ec.client.SyncProgress(ctx)
+	health := make(map[string]error, len(ec.endpoints))
+	for i := range ec.endpoints {
+		client, err := ec.endpointClient(ctx, i)
+		if err == nil {
+			err = ec.clientHealthy(ctx, client)
+		}
+		health[ec.endpoints[i].addr] = err
+	}
+	return health
+}
+
+func (ec *ExecutionClient) clientHealthy(ctx context.Context, client *ethclient.Client) error {
+	sp, err := client.SyncProgress(ctx)
 	if err != nil {
-		ec.metrics.ExecutionClientFailure()
 		return err
 	}
-
 	if sp != nil {
-		ec.metrics.ExecutionClientSyncing()
-		return fmt.Errorf("syncing")
+		return errSyncing
 	}
+	return nil
+}
 
-	ec.metrics.ExecutionClientReady()
+var errSyncing = fmt.Errorf("syncing")
 
-	return nil
+func (ec *ExecutionClient) reportHealth(err error) {
+	switch {
+	case err == nil:
+		ec.metrics.ExecutionClientReady()
+	case errors.Is(err, errSyncing):
+		ec.metrics.ExecutionClientSyncing()
+	default:
+		ec.metrics.ExecutionClientFailure()
+	}
 }
 
-func (ec *ExecutionClient) BlockByNumber(ctx context.Context, blockNumber *big.Int) (*ethtypes.Block, error) {
-	return ec.client.BlockByNumber(ctx, blockNumber)
+func (ec *ExecutionClient) BlockByNumber(ctx context.Context, blockNumber *big.Int) (block *ethtypes.Block, err error) {
+	err = ec.withFailover(ctx, func(client *ethclient.Client) (err error) {
+		block, err = client.BlockByNumber(ctx, blockNumber)
+		return err
+	})
+	return block, err
+}
+
+// HeaderByNumber returns the header of the canonical block with the given number.
+func (ec *ExecutionClient) HeaderByNumber(ctx context.Context, blockNumber *big.Int) (header *ethtypes.Header, err error) {
+	err = ec.withFailover(ctx, func(client *ethclient.Client) (err error) {
+		header, err = client.HeaderByNumber(ctx, blockNumber)
+		return err
+	})
+	return header, err
 }
 
 func (ec *ExecutionClient) isClosed() bool {
@@ -260,10 +384,10 @@ func (ec *ExecutionClient) isClosed() bool {
 // streamLogsToChan streams ongoing logs from the given block to the given channel.
 // streamLogsToChan *always* returns the last block it fetched, even if it errored.
 // TODO: consider handling "websocket: read limit exceeded" error and reducing batch size (syncSmartContractsEvents has code for this)
-func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- BlockLogs, fromBlock uint64) (lastBlock uint64, err error) {
+func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, client *ethclient.Client, headers *headerChain, logs chan<- BlockLogs, fromBlock uint64) (lastBlock uint64, err error) {
 	heads := make(chan *ethtypes.Header)
 
-	sub, err := ec.client.SubscribeNewHead(ctx, heads)
+	sub, err := client.SubscribeNewHead(ctx, heads)
 	if err != nil {
 		return fromBlock, fmt.Errorf("subscribe heads: %w", err)
 	}
@@ -284,6 +408,7 @@ func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- Blo
 			return fromBlock, fmt.Errorf("subscription: %w", err)
 
 		case header := <-heads:
+			headers.add(header)
 			if header.Number.Uint64() < ec.followDistance {
 				continue
 			}
@@ -291,8 +416,10 @@ func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- Blo
 			if toBlock < fromBlock {
 				continue
 			}
-			logStream, fetchErrors := ec.fetchLogsInBatches(ctx, fromBlock, toBlock)
+			ec.checkConsistency(ctx, toBlock)
+			logStream, fetchErrors := ec.fetchLogsInBatches(ctx, fromBlock, toBlock, headers)
 			for block := range logStream {
+				headers.link(&block)
 				logs <- block
 				lastBlock = block.BlockNumber
 			}
@@ -306,30 +433,59 @@ func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- Blo
 	}
 }
 
-// connect connects to Ethereum execution client.
-// It must not be called twice in parallel.
+// connect connects to Ethereum execution client, starting from the current endpoint.
+// With multiple endpoints, endpoints which fail to connect or are syncing are skipped.
 func (ec *ExecutionClient) connect(ctx context.Context) error {
-	logger := ec.logger.With(fields.Address(ec.nodeAddr))
-
 	ctx, cancel := context.WithTimeout(ctx, ec.connectionTimeout)
 	defer cancel()
 
-	start := time.Now()
+	ec.clientMu.RLock()
+	current := ec.current
+	ec.clientMu.RUnlock()
+
 	var err error
-	ec.client, err = ethclient.DialContext(ctx, ec.nodeAddr)
-	if err != nil {
-		return err
-	}
+	for i := 0; i < len(ec.endpoints); i++ {
+		idx := (current + i) % len(ec.endpoints)
+		logger := ec.logger.With(fields.Address(ec.endpoints[idx].addr))
+
+		start := time.Now()
+		var client *ethclient.Client
+		client, err = ec.endpointClient(ctx, idx)
+		if err != nil {
+			logger.Warn("could not connect to execution client", zap.Error(err))
+			continue
+		}
+		if len(ec.endpoints) > 1 {
+			if err = ec.clientHealthy(ctx, client); err != nil {
+				logger.Warn("execution client is not healthy, skipping", zap.Error(err))
+				continue
+			}
+		}
 
-	logger.Info("connected to execution client", zap.Duration("took", time.Since(start)))
-	return nil
+		ec.clientMu.Lock()
+		ec.client = client
+		ec.current = idx
+		ec.clientMu.Unlock()
+
+		logger.Info("connected to execution client", zap.Duration("took", time.Since(start)))
+		return nil
+	}
+	return err
 }
 
-// reconnect tries to reconnect multiple times with an exponent interval.
+// reconnect tries to reconnect multiple times with an exponent interval,
+// moving on to the next endpoint if multiple endpoints are configured.
+// It does nothing if the given failed client was already replaced by a failover.
 // It panics when reconnecting limit is reached.
-// It must not be called twice in parallel.
-func (ec *ExecutionClient) reconnect(ctx context.Context) {
-	logger := ec.logger.With(fields.Address(ec.nodeAddr))
+func (ec *ExecutionClient) reconnect(ctx context.Context, failed *ethclient.Client) {
+	ec.failoverMu.Lock()
+	defer ec.failoverMu.Unlock()
+
+	if ec.currentClient() != failed {
+		return
+	}
+	ec.dropCurrent()
+	logger := ec.logger.With(fields.Address(ec.currentAddr()))
 
 	start := time.Now()
 	tasks.ExecWithInterval(func(lastTick time.Duration) (stop bool, cont bool) {
@@ -352,6 +508,146 @@ func (ec *ExecutionClient) reconnect(ctx context.Context) {
 	logger.Info("reconnected to execution client", zap.Duration("took", time.Since(start)))
 }
 
+// withFailover calls f with the client of the current endpoint. If it fails and multiple endpoints
+// are configured, it fails over to the next endpoint and retries, until every endpoint was tried.
+func (ec *ExecutionClient) withFailover(ctx context.Context, f func(client *ethclient.Client) error) error {
+	var err error
+	for attempt := 0; attempt < len(ec.endpoints); attempt++ {
+		client := ec.currentClient()
+		if err = f(client); err == nil {
+			return nil
+		}
+		if len(ec.endpoints) == 1 || ctx.Err() != nil || ec.isClosed() {
+			return err
+		}
+
+		ec.logger.Warn("execution client request failed, failing over to the next endpoint",
+			fields.Address(ec.currentAddr()),
+			zap.Error(err))
+		if connectErr := ec.failover(ctx, client); connectErr != nil {
+			return fmt.Errorf("%w (failover: %v)", err, connectErr)
+		}
+	}
+	return err
+}
+
+// failover disconnects from the endpoint of the given failed client and connects to the next one.
+// It does nothing if the client was already replaced, so that callers which failed with the same client
+// concurrently, such as the log stream and a request, fail over only once.
+func (ec *ExecutionClient) failover(ctx context.Context, failed *ethclient.Client) error {
+	ec.failoverMu.Lock()
+	defer ec.failoverMu.Unlock()
+
+	if ec.currentClient() != failed {
+		return nil
+	}
+	ec.dropCurrent()
+	return ec.connect(ctx)
+}
+
+// dropCurrent disconnects from the current endpoint, so that it's dialed again when connecting to it,
+// and advances to the next one.
+func (ec *ExecutionClient) dropCurrent() {
+	ec.clientMu.Lock()
+	defer ec.clientMu.Unlock()
+
+	if e := ec.endpoints[ec.current]; e.client != nil {
+		e.client.Close()
+		e.client = nil
+	}
+	ec.current = (ec.current + 1) % len(ec.endpoints)
+}
+
+// currentClient returns the client of the current endpoint.
+func (ec *ExecutionClient) currentClient() *ethclient.Client {
+	ec.clientMu.RLock()
+	defer ec.clientMu.RUnlock()
+
+	return ec.client
+}
+
+// currentAddr returns the address of the current endpoint.
+func (ec *ExecutionClient) currentAddr() string {
+	ec.clientMu.RLock()
+	defer ec.clientMu.RUnlock()
+
+	return ec.endpoints[ec.current].addr
+}
+
+// endpointClient returns the client of the endpoint at the given index, dialing it if needed.
+func (ec *ExecutionClient) endpointClient(ctx context.Context, idx int) (*ethclient.Client, error) {
+	e := ec.endpoints[idx]
+
+	ec.clientMu.RLock()
+	client := e.client
+	ec.clientMu.RUnlock()
+	if client != nil {
+		return client, nil
+	}
+
+	// Dial without holding the lock, which would block every request for up to the connection timeout.
+	client, err := ethclient.DialContext(ctx, e.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ec.clientMu.Lock()
+	defer ec.clientMu.Unlock()
+	if e.client != nil {
+		// Dialed concurrently by another caller.
+		client.Close()
+		return e.client, nil
+	}
+	e.client = client
+	return client, nil
+}
+
+// checkConsistency compares the hash of the given block across all connected endpoints
+// and warns about endpoints which disagree with the current one.
+// Endpoints which aren't connected are skipped rather than dialed, since it runs on every new head.
+func (ec *ExecutionClient) checkConsistency(ctx context.Context, blockNumber uint64) {
+	if len(ec.endpoints) == 1 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ec.connectionTimeout)
+	defer cancel()
+
+	number := new(big.Int).SetUint64(blockNumber)
+	expected, err := ec.currentClient().HeaderByNumber(ctx, number)
+	if err != nil {
+		ec.logger.Debug("could not get block header for consistency check", fields.BlockNumber(blockNumber), zap.Error(err))
+		return
+	}
+
+	ec.clientMu.RLock()
+	current := ec.current
+	clients := make([]*ethclient.Client, len(ec.endpoints))
+	for i, e := range ec.endpoints {
+		clients[i] = e.client
+	}
+	ec.clientMu.RUnlock()
+
+	for i, client := range clients {
+		if i == current || client == nil {
+			continue
+		}
+		header, err := client.HeaderByNumber(ctx, number)
+		if err != nil {
+			continue
+		}
+		if header.Hash() != expected.Hash() {
+			ec.metrics.ExecutionClientInconsistent()
+			ec.logger.Warn("execution client endpoints disagree on block hash",
+				fields.BlockNumber(blockNumber),
+				zap.String("current_endpoint", ec.endpoints[current].addr),
+				zap.String("current_hash", expected.Hash().Hex()),
+				zap.String("endpoint", ec.endpoints[i].addr),
+				zap.String("hash", header.Hash().Hex()))
+		}
+	}
+}
+
 func (ec *ExecutionClient) Filterer() (*contract.ContractFilterer, error) {
-	return contract.NewContractFilterer(ec.contractAddress, ec.client)
+	return contract.NewContractFilterer(ec.contractAddress, ec.currentClient())
 }
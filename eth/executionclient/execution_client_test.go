@@ -3,6 +3,8 @@ package executionclient
 import (
 	"context"
 	"math/big"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
@@ -239,7 +241,7 @@ func TestFetchLogsInBatches(t *testing.T) {
 	}
 
 	t.Run("startBlock is greater than endBlock", func(t *testing.T) {
-		logChan, errChan := client.fetchLogsInBatches(ctx, 10, 5)
+		logChan, errChan := client.fetchLogsInBatches(ctx, 10, 5, nil)
 		select {
 		case <-logChan:
 			require.Fail(t, "Should not receive log when startBlock > endBlock")
@@ -253,7 +255,7 @@ func TestFetchLogsInBatches(t *testing.T) {
 	t.Run("startBlock is same as endBlock", func(t *testing.T) {
 		var blockNumbers []uint64
 
-		logChan, errChan := client.fetchLogsInBatches(ctx, 5, 5)
+		logChan, errChan := client.fetchLogsInBatches(ctx, 5, 5, nil)
 		select {
 		case block := <-logChan:
 			blockNumbers = append(blockNumbers, block.BlockNumber)
@@ -269,7 +271,7 @@ func TestFetchLogsInBatches(t *testing.T) {
 	t.Run("startBlock is less than endBlock", func(t *testing.T) {
 		var blockNumbers []uint64
 
-		logChan, errChan := client.fetchLogsInBatches(ctx, 3, 11)
+		logChan, errChan := client.fetchLogsInBatches(ctx, 3, 11, nil)
 		for block := range logChan {
 			blockNumbers = append(blockNumbers, block.BlockNumber)
 		}
@@ -286,7 +288,7 @@ func TestFetchLogsInBatches(t *testing.T) {
 		canceledCtx, cancel := context.WithCancel(ctx)
 		cancel()
 
-		logChan, errChan := client.fetchLogsInBatches(canceledCtx, 0, 5)
+		logChan, errChan := client.fetchLogsInBatches(canceledCtx, 0, 5, nil)
 		select {
 		case <-logChan:
 			require.Fail(t, "Should not receive log when context is canceled")
@@ -300,6 +302,96 @@ func TestFetchLogsInBatches(t *testing.T) {
 	require.NoError(t, sim.Close())
 }
 
+func TestFailover(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const testTimeout = 2 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	sim := simTestBackend(testAddr)
+
+	rpcServer, _ := sim.Node.RPCHandler()
+	defer rpcServer.Stop()
+
+	// Expose the simulator on two endpoints, keeping track of the first one's connections
+	// since websocket connections are hijacked and aren't closed by httptest.
+	var (
+		connsMu sync.Mutex
+		conns   []net.Conn
+	)
+	httpsrv1 := httptest.NewUnstartedServer(rpcServer.WebsocketHandler([]string{"*"}))
+	httpsrv1.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connsMu.Lock()
+			conns = append(conns, conn)
+			connsMu.Unlock()
+		}
+	}
+	httpsrv1.Start()
+	httpsrv2 := httptest.NewServer(rpcServer.WebsocketHandler([]string{"*"}))
+	defer httpsrv2.Close()
+	addr1 := httpToWebSocketURL(httpsrv1.URL)
+	addr2 := httpToWebSocketURL(httpsrv2.URL)
+
+	parsed, _ := abi.JSON(strings.NewReader(callableAbi))
+	auth, _ := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+	contractAddr, _, contract, err := bind.DeployContract(auth, parsed, ethcommon.FromHex(callableBin), sim)
+	if err != nil {
+		t.Errorf("deploying contract: %v", err)
+	}
+	sim.Commit()
+
+	client, err := New(ctx, addr1+","+addr2, contractAddr, WithLogger(logger), WithFollowDistance(0))
+	require.NoError(t, err)
+	require.Equal(t, addr1, client.currentAddr())
+	firstClient := client.currentClient()
+
+	for i := 0; i < blocksWithLogsLength; i++ {
+		_, err := contract.Transact(auth, "Call")
+		if err != nil {
+			t.Errorf("transacting: %v", err)
+		}
+		sim.Commit()
+	}
+
+	// Take down the first endpoint.
+	httpsrv1.Close()
+	connsMu.Lock()
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	connsMu.Unlock()
+
+	health := client.EndpointsHealthy(ctx)
+	require.Error(t, health[addr1])
+	require.NoError(t, health[addr2])
+	require.NoError(t, client.Healthy(ctx))
+
+	// Fetching logs should fail over to the second endpoint.
+	var fetchedLogs []ethtypes.Log
+	logs, fetchErrCh, err := client.FetchHistoricalLogs(ctx, 0)
+	require.NoError(t, err)
+	for block := range logs {
+		fetchedLogs = append(fetchedLogs, block.Logs...)
+	}
+	require.Equal(t, blocksWithLogsLength, len(fetchedLogs))
+	select {
+	case err := <-fetchErrCh:
+		require.NoError(t, err)
+	case <-ctx.Done():
+		require.Fail(t, "timeout")
+	}
+	require.Equal(t, addr2, client.currentAddr())
+
+	// Other callers which failed with the first endpoint, like the log stream, don't fail over again.
+	require.NoError(t, client.failover(ctx, firstClient))
+	client.reconnect(ctx, firstClient)
+	require.Equal(t, addr2, client.currentAddr())
+
+	require.NoError(t, client.Close())
+	require.NoError(t, sim.Close())
+}
+
 // TestChainReorganizationLogs check that the client receives removed logs correctly.
 // Steps:
 //  1. Deploy the Callable contract.
@@ -0,0 +1,79 @@
+package executionclient
+
+import (
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// headerChain holds the heads received while streaming, so that the headers of streamed blocks
+// aren't fetched again, and to tell whether a streamed block extends the block streamed before it.
+type headerChain struct {
+	mu      sync.Mutex
+	headers map[uint64]*ethtypes.Header
+
+	// lastNumber and lastHash identify the last streamed block, if any.
+	lastNumber uint64
+	lastHash   ethcommon.Hash
+}
+
+func newHeaderChain() *headerChain {
+	return &headerChain{headers: make(map[uint64]*ethtypes.Header)}
+}
+
+// add adds a received head, replacing the header of the same number if it was reorged out.
+func (c *headerChain) add(header *ethtypes.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if header.Number.Uint64() < c.lastNumber {
+		return
+	}
+	c.headers[header.Number.Uint64()] = header
+}
+
+// header returns the received head of the given number, or nil if it wasn't received.
+func (c *headerChain) header(number uint64) *ethtypes.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.headers[number]
+}
+
+// link sets whether the given block extends the last streamed block according to the received heads,
+// and records it as the last streamed block.
+func (c *headerChain) link(block *BlockLogs) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	block.Linked = c.lastHash != (ethcommon.Hash{}) && c.extendsLast(block.BlockNumber, block.BlockHash)
+	c.lastNumber = block.BlockNumber
+	c.lastHash = block.BlockHash
+
+	// Headers before the last streamed block aren't needed anymore.
+	for number := range c.headers {
+		if number < c.lastNumber {
+			delete(c.headers, number)
+		}
+	}
+}
+
+// extendsLast returns whether the received heads link the given block to the last streamed block.
+func (c *headerChain) extendsLast(number uint64, hash ethcommon.Hash) bool {
+	if number <= c.lastNumber {
+		return false
+	}
+	header := c.headers[number]
+	if header == nil || header.Hash() != hash {
+		return false
+	}
+	for header.Number.Uint64() > c.lastNumber {
+		parent := c.headers[header.Number.Uint64()-1]
+		if parent == nil || parent.Hash() != header.ParentHash {
+			return false
+		}
+		header = parent
+	}
+	return header.Hash() == c.lastHash
+}
@@ -0,0 +1,62 @@
+package executionclient
+
+import (
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderChain(t *testing.T) {
+	// chain returns headers of the given numbers, each a child of the previous one.
+	chain := func(parent ethcommon.Hash, extra byte, from, to uint64) []*types.Header {
+		var headers []*types.Header
+		for n := from; n <= to; n++ {
+			header := &types.Header{Number: new(big.Int).SetUint64(n), ParentHash: parent, Extra: []byte{extra}}
+			headers = append(headers, header)
+			parent = header.Hash()
+		}
+		return headers
+	}
+	block := func(header *types.Header) BlockLogs {
+		return BlockLogs{BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()}
+	}
+
+	c := newHeaderChain()
+	headers := chain(ethcommon.Hash{}, 0, 1, 10)
+	for _, header := range headers {
+		c.add(header)
+	}
+	require.Equal(t, headers[4], c.header(5))
+
+	// The first streamed block can't be linked to a previous one.
+	b := block(headers[2])
+	c.link(&b)
+	require.False(t, b.Linked)
+
+	// Skipped blocks are linked through their headers.
+	b = block(headers[5])
+	c.link(&b)
+	require.True(t, b.Linked)
+	require.Nil(t, c.header(3), "headers before the last streamed block should be pruned")
+
+	// A reorg replaces the following heads, which no longer link to the last streamed block.
+	reorged := chain(headers[6].Hash(), 1, 8, 10)
+	for _, header := range reorged {
+		c.add(header)
+	}
+	b = block(headers[8])
+	c.link(&b)
+	require.False(t, b.Linked, "block of the reorged out chain shouldn't be linked")
+
+	b = block(reorged[2])
+	c.link(&b)
+	require.False(t, b.Linked, "block after a different block than the last streamed one shouldn't be linked")
+
+	// Unknown headers break the link.
+	b = BlockLogs{BlockNumber: 12, BlockHash: ethcommon.Hash{1}}
+	c.link(&b)
+	require.False(t, b.Linked)
+}
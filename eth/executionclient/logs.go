@@ -3,13 +3,19 @@ package executionclient
 import (
 	"sort"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
-// BlockLogs holds a block's number and it's logs.
+// BlockLogs holds a block's number, hash and it's logs.
 type BlockLogs struct {
 	BlockNumber uint64
+	BlockHash   ethcommon.Hash
 	Logs        []ethtypes.Log
+
+	// Linked is set by StreamLogs when the heads it received link this block to the block streamed before it,
+	// in which case the block is known to extend it without a reorg in between.
+	Linked bool
 }
 
 // PackLogs packs logs into []BlockLogs by their block number.
@@ -28,6 +34,7 @@ func PackLogs(logs []ethtypes.Log) []BlockLogs {
 		if len(all) == 0 || all[len(all)-1].BlockNumber != log.BlockNumber {
 			all = append(all, BlockLogs{
 				BlockNumber: log.BlockNumber,
+				BlockHash:   log.BlockHash,
 			})
 		}
 
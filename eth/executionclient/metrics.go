@@ -4,6 +4,7 @@ type metrics interface {
 	ExecutionClientReady()
 	ExecutionClientSyncing()
 	ExecutionClientFailure()
+	ExecutionClientInconsistent()
 	ExecutionClientLastFetchedBlock(block uint64)
 }
 
@@ -13,4 +14,5 @@ type nopMetrics struct{}
 func (nopMetrics) ExecutionClientReady()                    {}
 func (nopMetrics) ExecutionClientSyncing()                  {}
 func (nopMetrics) ExecutionClientFailure()                  {}
+func (nopMetrics) ExecutionClientInconsistent()             {}
 func (nopMetrics) ExecutionClientLastFetchedBlock(_ uint64) {}
@@ -3,9 +3,12 @@ package localevents
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"gopkg.in/yaml.v3"
@@ -20,8 +23,14 @@ type Event struct {
 	Name string
 	// Data is the parsed event
 	Data interface{}
+	// Block is the number of the block the event is processed in.
+	// Events without a block are each processed in the block following the previous event's block.
+	Block uint64
+	// Timestamp is the time before which the event isn't processed, if set.
+	Timestamp time.Time
 }
 
+// Load loads the events of a local events file, with their blocks assigned.
 func Load(path string) ([]Event, error) {
 	yamlFile, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
@@ -32,10 +41,28 @@ func Load(path string) ([]Event, error) {
 	if err := yaml.Unmarshal(yamlFile, &events); err != nil {
 		return nil, err
 	}
+	if err := assignBlocks(events); err != nil {
+		return nil, err
+	}
 
 	return events, nil
 }
 
+// assignBlocks assigns blocks to the events without one, and checks that the blocks are ordered.
+func assignBlocks(events []Event) error {
+	var prev uint64
+	for i := range events {
+		if events[i].Block == 0 {
+			events[i].Block = prev + 1
+		} else if events[i].Block < prev {
+			return fmt.Errorf("event %d (%s) is in block %d, lower than the previous event's block %d",
+				i, events[i].Name, events[i].Block, prev)
+		}
+		prev = events[i].Block
+	}
+	return nil
+}
+
 type eventData interface {
 	toEventData() (interface{}, error)
 }
@@ -84,10 +111,51 @@ type FeeRecipientAddressUpdatedEventYAML struct {
 }
 
 type ValidatorExitedEventYAML struct {
+	Owner       string   `yaml:"Owner"`
 	PublicKey   string   `yaml:"PublicKey"`
 	OperatorIds []uint64 `yaml:"OperatorIds"`
 }
 
+type OperatorWhitelistUpdatedEventYAML struct {
+	ID          uint64 `yaml:"ID"`
+	Whitelisted string `yaml:"Whitelisted"`
+}
+
+type OperatorFeeDeclaredEventYAML struct {
+	Owner string `yaml:"Owner"`
+	ID    uint64 `yaml:"ID"`
+	Fee   string `yaml:"Fee"`
+}
+
+type OperatorFeeDeclarationCancelledEventYAML struct {
+	Owner string `yaml:"Owner"`
+	ID    uint64 `yaml:"ID"`
+}
+
+type OperatorFeeExecutedEventYAML struct {
+	Owner string `yaml:"Owner"`
+	ID    uint64 `yaml:"ID"`
+	Fee   string `yaml:"Fee"`
+}
+
+type OperatorWithdrawnEventYAML struct {
+	Owner string `yaml:"Owner"`
+	ID    uint64 `yaml:"ID"`
+	Value string `yaml:"Value"`
+}
+
+type ClusterDepositedEventYAML struct {
+	Owner       string   `yaml:"Owner"`
+	OperatorIds []uint64 `yaml:"OperatorIds"`
+	Value       string   `yaml:"Value"`
+}
+
+type ClusterWithdrawnEventYAML struct {
+	Owner       string   `yaml:"Owner"`
+	OperatorIds []uint64 `yaml:"OperatorIds"`
+	Value       string   `yaml:"Value"`
+}
+
 func (e *OperatorAddedEventYAML) toEventData() (interface{}, error) {
 	return contract.ContractOperatorAdded{
 		OperatorId: e.ID,
@@ -122,10 +190,15 @@ func (e *ValidatorAddedEventYAML) toEventData() (interface{}, error) {
 }
 
 func (e *ValidatorRemovedEventYAML) toEventData() (interface{}, error) {
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(e.PublicKey, "0x"))
+	if err != nil {
+		return nil, err
+	}
+
 	return contract.ContractValidatorRemoved{
 		Owner:       ethcommon.HexToAddress(e.Owner),
 		OperatorIds: e.OperatorIds,
-		PublicKey:   []byte(strings.TrimPrefix(e.PublicKey, "0x")),
+		PublicKey:   pubKey,
 	}, nil
 }
 
@@ -151,12 +224,104 @@ func (e *FeeRecipientAddressUpdatedEventYAML) toEventData() (interface{}, error)
 }
 
 func (e *ValidatorExitedEventYAML) toEventData() (interface{}, error) {
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(e.PublicKey, "0x"))
+	if err != nil {
+		return nil, err
+	}
+
 	return contract.ContractValidatorExited{
-		PublicKey:   []byte(strings.TrimPrefix(e.PublicKey, "0x")),
+		Owner:       ethcommon.HexToAddress(e.Owner),
+		PublicKey:   pubKey,
+		OperatorIds: e.OperatorIds,
+	}, nil
+}
+
+func (e *OperatorWhitelistUpdatedEventYAML) toEventData() (interface{}, error) {
+	return contract.ContractOperatorWhitelistUpdated{
+		OperatorId:  e.ID,
+		Whitelisted: ethcommon.HexToAddress(e.Whitelisted),
+	}, nil
+}
+
+func (e *OperatorFeeDeclaredEventYAML) toEventData() (interface{}, error) {
+	fee, err := parseAmount(e.Fee)
+	if err != nil {
+		return nil, err
+	}
+	return contract.ContractOperatorFeeDeclared{
+		Owner:      ethcommon.HexToAddress(e.Owner),
+		OperatorId: e.ID,
+		Fee:        fee,
+	}, nil
+}
+
+func (e *OperatorFeeDeclarationCancelledEventYAML) toEventData() (interface{}, error) {
+	return contract.ContractOperatorFeeDeclarationCancelled{
+		Owner:      ethcommon.HexToAddress(e.Owner),
+		OperatorId: e.ID,
+	}, nil
+}
+
+func (e *OperatorFeeExecutedEventYAML) toEventData() (interface{}, error) {
+	fee, err := parseAmount(e.Fee)
+	if err != nil {
+		return nil, err
+	}
+	return contract.ContractOperatorFeeExecuted{
+		Owner:      ethcommon.HexToAddress(e.Owner),
+		OperatorId: e.ID,
+		Fee:        fee,
+	}, nil
+}
+
+func (e *OperatorWithdrawnEventYAML) toEventData() (interface{}, error) {
+	value, err := parseAmount(e.Value)
+	if err != nil {
+		return nil, err
+	}
+	return contract.ContractOperatorWithdrawn{
+		Owner:      ethcommon.HexToAddress(e.Owner),
+		OperatorId: e.ID,
+		Value:      value,
+	}, nil
+}
+
+func (e *ClusterDepositedEventYAML) toEventData() (interface{}, error) {
+	value, err := parseAmount(e.Value)
+	if err != nil {
+		return nil, err
+	}
+	return contract.ContractClusterDeposited{
+		Owner:       ethcommon.HexToAddress(e.Owner),
 		OperatorIds: e.OperatorIds,
+		Value:       value,
 	}, nil
 }
 
+func (e *ClusterWithdrawnEventYAML) toEventData() (interface{}, error) {
+	value, err := parseAmount(e.Value)
+	if err != nil {
+		return nil, err
+	}
+	return contract.ContractClusterWithdrawn{
+		Owner:       ethcommon.HexToAddress(e.Owner),
+		OperatorIds: e.OperatorIds,
+		Value:       value,
+	}, nil
+}
+
+// parseAmount parses a decimal amount of wei, which may exceed uint64. An empty amount is zero.
+func parseAmount(s string) (*big.Int, error) {
+	if s == "" {
+		return new(big.Int), nil
+	}
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
 func (u *eventDataUnmarshaler) UnmarshalYAML(value *yaml.Node) error {
 	var err error
 	switch u.name {
@@ -192,6 +357,34 @@ func (u *eventDataUnmarshaler) UnmarshalYAML(value *yaml.Node) error {
 		var v ValidatorExitedEventYAML
 		err = value.Decode(&v)
 		u.data = &v
+	case "OperatorWhitelistUpdated":
+		var v OperatorWhitelistUpdatedEventYAML
+		err = value.Decode(&v)
+		u.data = &v
+	case "OperatorFeeDeclared":
+		var v OperatorFeeDeclaredEventYAML
+		err = value.Decode(&v)
+		u.data = &v
+	case "OperatorFeeDeclarationCancelled":
+		var v OperatorFeeDeclarationCancelledEventYAML
+		err = value.Decode(&v)
+		u.data = &v
+	case "OperatorFeeExecuted":
+		var v OperatorFeeExecutedEventYAML
+		err = value.Decode(&v)
+		u.data = &v
+	case "OperatorWithdrawn":
+		var v OperatorWithdrawnEventYAML
+		err = value.Decode(&v)
+		u.data = &v
+	case "ClusterDeposited":
+		var v ClusterDepositedEventYAML
+		err = value.Decode(&v)
+		u.data = &v
+	case "ClusterWithdrawn":
+		var v ClusterWithdrawnEventYAML
+		err = value.Decode(&v)
+		u.data = &v
 	default:
 		return errors.New("event unknown")
 	}
@@ -201,7 +394,9 @@ func (u *eventDataUnmarshaler) UnmarshalYAML(value *yaml.Node) error {
 
 func (e *Event) UnmarshalYAML(value *yaml.Node) error {
 	var evName struct {
-		Name string `yaml:"Name"`
+		Name      string    `yaml:"Name"`
+		Block     uint64    `yaml:"Block"`
+		Timestamp time.Time `yaml:"Timestamp"`
 	}
 	err := value.Decode(&evName)
 	if err != nil {
@@ -222,6 +417,8 @@ func (e *Event) UnmarshalYAML(value *yaml.Node) error {
 		return errors.New("event data is nil")
 	}
 	e.Name = ev.Data.name
+	e.Block = evName.Block
+	e.Timestamp = evName.Timestamp
 	data, err := ev.Data.data.toEventData()
 	if err != nil {
 		return err
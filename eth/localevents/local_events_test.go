@@ -1,7 +1,10 @@
 package localevents_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/bloxapp/ssv/eth/contract"
 	"github.com/bloxapp/ssv/eth/localevents"
@@ -83,3 +86,73 @@ func TestUnmarshalYAMLOperatorAddedEvent(t *testing.T) {
 		require.EqualError(t, err, "yaml: unmarshal errors:\n  line 5: cannot unmarshal !!str `id` into uint64")
 	})
 }
+
+func TestLoad(t *testing.T) {
+	t.Run("Successfully load events with blocks and timestamps", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+- Log:
+  Name: OperatorWhitelistUpdated
+  Data:
+    ID: 1
+    Whitelisted: 0x97a6C1f3aaB5427B901fb135ED492749191C0f1F
+- Log:
+  Name: OperatorFeeDeclared
+  Block: 5
+  Data:
+    Owner: 0x97a6C1f3aaB5427B901fb135ED492749191C0f1F
+    ID: 1
+    Fee: "100000000000000000000"
+- Log:
+  Name: ClusterLiquidated
+  Block: 5
+  Timestamp: 2024-01-01T12:00:00Z
+  Data:
+    Owner: 0x97a6C1f3aaB5427B901fb135ED492749191C0f1F
+    OperatorIds: [1, 2, 3, 4]
+- Log:
+  Name: ClusterReactivated
+  Data:
+    Owner: 0x97a6C1f3aaB5427B901fb135ED492749191C0f1F
+    OperatorIds: [1, 2, 3, 4]
+`), 0600))
+
+		events, err := localevents.Load(path)
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+
+		var blocks []uint64
+		for _, event := range events {
+			blocks = append(blocks, event.Block)
+		}
+		require.Equal(t, []uint64{1, 5, 5, 6}, blocks)
+		require.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), events[2].Timestamp)
+		require.True(t, events[3].Timestamp.IsZero())
+
+		whitelistUpdated, ok := events[0].Data.(contract.ContractOperatorWhitelistUpdated)
+		require.True(t, ok)
+		require.Equal(t, "0x97a6C1f3aaB5427B901fb135ED492749191C0f1F", whitelistUpdated.Whitelisted.String())
+		feeDeclared, ok := events[1].Data.(contract.ContractOperatorFeeDeclared)
+		require.True(t, ok)
+		require.Equal(t, "100000000000000000000", feeDeclared.Fee.String())
+	})
+
+	t.Run("Fail to load events with decreasing blocks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+- Log:
+  Name: OperatorRemoved
+  Block: 5
+  Data:
+    ID: 1
+- Log:
+  Name: OperatorRemoved
+  Block: 4
+  Data:
+    ID: 2
+`), 0600))
+
+		_, err := localevents.Load(path)
+		require.EqualError(t, err, "event 1 (OperatorRemoved) is in block 4, lower than the previous event's block 5")
+	})
+}
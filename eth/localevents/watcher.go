@@ -0,0 +1,55 @@
+package localevents
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+)
+
+// DefaultWatchInterval is the default interval at which a watched local events file is checked.
+const DefaultWatchInterval = time.Second
+
+// Watch calls handle with the events of the file at path every interval until the context is done,
+// starting with the given events loaded from it, and reloading them whenever the file changes,
+// so that events appended to the file are handled while the node runs.
+// handle is called even if the file didn't change, since events may be scheduled for a later time.
+// A file which fails to load, e.g. while it's being written, is retried at the next interval.
+func Watch(ctx context.Context, logger *zap.Logger, path string, interval time.Duration, events []Event, handle func([]Event) error) error {
+	var modTime time.Time
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		modTime, size = info.ModTime(), info.Size()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Warn("could not stat local events file", zap.Error(err))
+		} else if !info.ModTime().Equal(modTime) || info.Size() != size {
+			reloaded, err := Load(path)
+			if err != nil {
+				logger.Warn("could not reload local events", zap.Error(err))
+			} else {
+				events = reloaded
+				modTime, size = info.ModTime(), info.Size()
+				logger.Debug("reloaded local events", fields.Count(len(events)))
+			}
+		}
+
+		if err := handle(events); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+)
+
+// DefaultPruneInterval is the default interval at which the history is pruned.
+const DefaultPruneInterval = time.Hour
+
+// Retention is the retention policy of the decided history of a role.
+type Retention struct {
+	// Epochs is the number of recent epochs to keep the history of, or 0 to keep all of it.
+	Epochs uint64 `yaml:"Epochs"`
+	// MaxSizeMB is the size in megabytes to trim the history to, removing the oldest epochs first, or 0 for no limit.
+	MaxSizeMB uint64 `yaml:"MaxSizeMB"`
+}
+
+// Enabled returns whether the retention policy removes anything.
+func (r Retention) Enabled() bool {
+	return r.Epochs > 0 || r.MaxSizeMB > 0
+}
+
+// RetentionOptions configures the pruning of the decided history saved by full nodes.
+type RetentionOptions struct {
+	Epochs    uint64        `yaml:"Epochs" env:"HISTORY_RETENTION_EPOCHS" env-default:"0" env-description:"Number of recent epochs to keep the decided history of, or 0 to keep all of it"`
+	MaxSizeMB uint64        `yaml:"MaxSizeMB" env:"HISTORY_RETENTION_MAX_SIZE_MB" env-default:"0" env-description:"Size in megabytes to trim the decided history of each role to, or 0 for no limit"`
+	Interval  time.Duration `yaml:"PruneInterval" env:"HISTORY_PRUNE_INTERVAL" env-default:"1h" env-description:"Interval for pruning the decided history"`
+	// Roles overrides the retention of the roles with the given names, such as PROPOSER.
+	Roles map[string]Retention `yaml:"Roles"`
+}
+
+// Validate returns an error if a role override doesn't name a known role.
+func (o RetentionOptions) Validate() error {
+	for name := range o.Roles {
+		if _, ok := roleByName(name); !ok {
+			return fmt.Errorf("unknown role %q in history retention", name)
+		}
+	}
+	return nil
+}
+
+// Enabled returns whether the history of any role is pruned.
+func (o RetentionOptions) Enabled() bool {
+	if (Retention{Epochs: o.Epochs, MaxSizeMB: o.MaxSizeMB}).Enabled() {
+		return true
+	}
+	for _, retention := range o.Roles {
+		if retention.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// For returns the retention policy of the given role.
+func (o RetentionOptions) For(role spectypes.BeaconRole) Retention {
+	if retention, ok := o.Roles[role.String()]; ok {
+		return retention
+	}
+	return Retention{Epochs: o.Epochs, MaxSizeMB: o.MaxSizeMB}
+}
+
+// Pruner removes the decided history of the stores which is no longer retained by the retention policy.
+type Pruner struct {
+	logger  *zap.Logger
+	stores  *QBFTStores
+	network beacon.BeaconNetwork
+	opts    RetentionOptions
+}
+
+// NewPruner creates a new Pruner.
+func NewPruner(logger *zap.Logger, stores *QBFTStores, network beacon.BeaconNetwork, opts RetentionOptions) *Pruner {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultPruneInterval
+	}
+	return &Pruner{
+		logger:  logger.Named("HistoryPruner"),
+		stores:  stores,
+		network: network,
+		opts:    opts,
+	}
+}
+
+// Start prunes the history every interval until the context is done.
+func (p *Pruner) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Prune(p.network.EstimatedCurrentSlot()); err != nil {
+			p.logger.Warn("failed to prune decided history", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune removes the history of every role which isn't retained at the given slot.
+func (p *Pruner) Prune(currentSlot phase0.Slot) error {
+	return p.stores.Each(func(role spectypes.BeaconRole, store qbftstorage.QBFTStore) error {
+		retention := p.opts.For(role)
+		if !retention.Enabled() {
+			return nil
+		}
+
+		start := time.Now()
+		below, err := p.cutoff(store, retention, currentSlot)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find %s history to prune", role)
+		}
+		if below == 0 {
+			return nil
+		}
+		pruned, size, err := store.PruneHistory(below)
+		metricsPrunedInstances.WithLabelValues(role.String()).Add(float64(pruned))
+		metricsPrunedBytes.WithLabelValues(role.String()).Add(float64(size))
+		if err != nil {
+			return errors.Wrapf(err, "failed to prune %s history", role)
+		}
+
+		p.logger.Debug("pruned decided history",
+			fields.Role(role),
+			zap.Uint64("below_height", uint64(below)),
+			fields.Count(pruned),
+			zap.Int64("bytes", size),
+			fields.Duration(start))
+		return nil
+	})
+}
+
+// cutoff returns the height below which the history of the store isn't retained at the given slot.
+// Since the height of the duty instances is their slot, the history is retained by whole epochs.
+func (p *Pruner) cutoff(store qbftstorage.QBFTStore, retention Retention, currentSlot phase0.Slot) (specqbft.Height, error) {
+	slotsPerEpoch := p.network.SlotsPerEpoch()
+	currentEpoch := uint64(currentSlot) / slotsPerEpoch
+
+	var below specqbft.Height
+	if retention.Epochs > 0 && currentEpoch+1 > retention.Epochs {
+		below = specqbft.Height((currentEpoch + 1 - retention.Epochs) * slotsPerEpoch)
+	}
+
+	if retention.MaxSizeMB > 0 {
+		epochSizes := make(map[uint64]uint64)
+		err := store.WalkHistory(func(height specqbft.Height, size int) error {
+			epochSizes[uint64(height)/slotsPerEpoch] += uint64(size)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		epochs := make([]uint64, 0, len(epochSizes))
+		for epoch := range epochSizes {
+			epochs = append(epochs, epoch)
+		}
+		sort.Slice(epochs, func(i, j int) bool { return epochs[i] > epochs[j] })
+
+		// Keep the most recent epochs which fit in the size limit.
+		var total uint64
+		for _, epoch := range epochs {
+			total += epochSizes[epoch]
+			if total > retention.MaxSizeMB<<20 {
+				if sizeBelow := specqbft.Height((epoch + 1) * slotsPerEpoch); sizeBelow > below {
+					below = sizeBelow
+				}
+				break
+			}
+		}
+	}
+
+	return below, nil
+}
+
+func roleByName(name string) (spectypes.BeaconRole, bool) {
+	for _, role := range []spectypes.BeaconRole{
+		spectypes.BNRoleAttester,
+		spectypes.BNRoleAggregator,
+		spectypes.BNRoleProposer,
+		spectypes.BNRoleSyncCommittee,
+		spectypes.BNRoleSyncCommitteeContribution,
+		spectypes.BNRoleValidatorRegistration,
+		spectypes.BNRoleVoluntaryExit,
+	} {
+		if role.String() == name {
+			return role, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+func TestPruner(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	roles := []spectypes.BeaconRole{spectypes.BNRoleAttester, spectypes.BNRoleProposer, spectypes.BNRoleAggregator}
+	stores := NewStoresFromRoles(db, roles...)
+
+	// An instance at the first slot of each of the epochs 0 to 3, each taking up about 400KB.
+	bigValue := bytes.Repeat([]byte{1}, 300<<10)
+	for _, role := range roles {
+		msgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), role)
+		for h := specqbft.Height(0); h < 128; h += 32 {
+			instance := generateInstance(msgID, h)
+			instance.State.DecidedValue = bigValue
+			require.NoError(t, stores.Get(role).SaveInstance(instance))
+		}
+	}
+
+	opts := RetentionOptions{
+		Epochs: 2,
+		Roles: map[string]Retention{
+			spectypes.BNRoleProposer.String():   {MaxSizeMB: 1},
+			spectypes.BNRoleAggregator.String(): {},
+		},
+	}
+	require.NoError(t, opts.Validate())
+	require.True(t, opts.Enabled())
+
+	pruner := NewPruner(logger, stores, networkconfig.TestNetwork.Beacon, opts)
+	require.NoError(t, pruner.Prune(100))
+
+	heights := func(role spectypes.BeaconRole) []specqbft.Height {
+		var heights []specqbft.Height
+		require.NoError(t, stores.Get(role).WalkHistory(func(height specqbft.Height, size int) error {
+			heights = append(heights, height)
+			return nil
+		}))
+		return heights
+	}
+	// The current epoch 3 and the one before it are retained.
+	require.Equal(t, []specqbft.Height{64, 96}, heights(spectypes.BNRoleAttester))
+	// The 2 most recent epochs fit in 1MB.
+	require.Equal(t, []specqbft.Height{64, 96}, heights(spectypes.BNRoleProposer))
+	// The role's override retains everything.
+	require.Equal(t, []specqbft.Height{0, 32, 64, 96}, heights(spectypes.BNRoleAggregator))
+
+	opts.Roles = map[string]Retention{"UNKNOWN": {Epochs: 1}}
+	require.ErrorContains(t, opts.Validate(), `unknown role "UNKNOWN"`)
+	require.False(t, RetentionOptions{}.Enabled())
+}
@@ -1,9 +1,12 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/binary"
+	"math"
 
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -16,7 +19,15 @@ import (
 
 const (
 	highestInstanceKey = "highest_instance"
-	instanceKey        = "instance"
+	// instanceKey is followed by the big-endian height of the instance, so that the history is ordered by height.
+	instanceKey = "historical_instance"
+	// legacyInstanceKey was followed by the little-endian height of the instance, before the history was ordered.
+	legacyInstanceKey = "instance"
+
+	// legacyMigrationBatchSize is the number of historical instances to migrate in each transaction.
+	legacyMigrationBatchSize = 1000
+	// pruneBatchSize is the number of historical instances to remove in each transaction.
+	pruneBatchSize = 1000
 )
 
 var (
@@ -24,6 +35,14 @@ var (
 		Name: "ssv:validator:ibft_highest_decided",
 		Help: "The highest decided sequence number",
 	}, []string{"identifier", "pubKey"})
+	metricsPrunedInstances = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:ibft_pruned_instances",
+		Help: "The number of historical instances removed by the retention policy",
+	}, []string{"role"})
+	metricsPrunedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:validator:ibft_pruned_bytes",
+		Help: "The encoded size of the historical instances removed by the retention policy",
+	}, []string{"role"})
 )
 
 func init() {
@@ -92,7 +111,7 @@ func (i *ibftStorage) saveInstance(inst *qbftstorage.StoredInstance, toHistory,
 	}
 
 	if toHistory {
-		err = i.save(value, instanceKey, inst.State.ID, uInt64ToByteSlice(uint64(inst.State.Height)))
+		err = i.save(value, instanceKey, inst.State.ID, heightKey(inst.State.Height))
 		if err != nil {
 			return errors.Wrap(err, "could not save historical instance")
 		}
@@ -103,7 +122,7 @@ func (i *ibftStorage) saveInstance(inst *qbftstorage.StoredInstance, toHistory,
 
 // GetInstance returns historical StoredInstance for the given identifier and height.
 func (i *ibftStorage) GetInstance(identifier []byte, height specqbft.Height) (*qbftstorage.StoredInstance, error) {
-	val, found, err := i.get(instanceKey, identifier[:], uInt64ToByteSlice(uint64(height)))
+	val, found, err := i.get(instanceKey, identifier[:], heightKey(height))
 	if !found {
 		return nil, nil
 	}
@@ -119,16 +138,33 @@ func (i *ibftStorage) GetInstance(identifier []byte, height specqbft.Height) (*q
 
 // GetInstancesInRange returns historical StoredInstance's in the given range.
 func (i *ibftStorage) GetInstancesInRange(identifier []byte, from specqbft.Height, to specqbft.Height) ([]*qbftstorage.StoredInstance, error) {
+	return i.GetInstances(identifier, from, to, 0)
+}
+
+// GetInstances returns up to limit historical StoredInstance's in the given range, in ascending order of height.
+func (i *ibftStorage) GetInstances(identifier []byte, from specqbft.Height, to specqbft.Height, limit int) ([]*qbftstorage.StoredInstance, error) {
 	instances := make([]*qbftstorage.StoredInstance, 0)
+	if to < from {
+		return instances, nil
+	}
 
-	for seq := from; seq <= to; seq++ {
-		instance, err := i.GetInstance(identifier, seq)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get instance")
-		}
-		if instance != nil {
-			instances = append(instances, instance)
+	opts := basedb.IterateOptions{
+		Start: heightKey(from),
+		Limit: limit,
+	}
+	if to < math.MaxUint64 {
+		opts.End = heightKey(to + 1)
+	}
+	err := i.db.Iterate(i.historyPrefix(identifier), opts, func(obj basedb.Obj) error {
+		instance := &qbftstorage.StoredInstance{}
+		if err := instance.Decode(obj.Value); err != nil {
+			return errors.Wrap(err, "could not decode instance")
 		}
+		instances = append(instances, instance)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get instances")
 	}
 
 	return instances, nil
@@ -136,10 +172,7 @@ func (i *ibftStorage) GetInstancesInRange(identifier []byte, from specqbft.Heigh
 
 // CleanAllInstances removes all StoredInstance's & highest StoredInstance's for msgID.
 func (i *ibftStorage) CleanAllInstances(logger *zap.Logger, msgID []byte) error {
-	prefix := i.prefix
-	prefix = append(prefix, msgID[:]...)
-	prefix = append(prefix, []byte(instanceKey)...)
-	_, err := i.db.DeletePrefix(prefix)
+	_, err := i.db.DeletePrefix(i.historyPrefix(msgID))
 	if err != nil {
 		return errors.Wrap(err, "failed to remove decided")
 	}
@@ -150,6 +183,99 @@ func (i *ibftStorage) CleanAllInstances(logger *zap.Logger, msgID []byte) error
 	return nil
 }
 
+// PruneHistory removes the historical instances of all identifiers below the given height,
+// and returns the number and encoded size of the instances removed.
+func (i *ibftStorage) PruneHistory(below specqbft.Height) (int, int64, error) {
+	identifierLen := len(spectypes.MessageID{})
+
+	var pruned int
+	var size int64
+	var cursor []byte
+	for {
+		// Seek to the next identifier, skipping the keys of the current one.
+		var next []byte
+		err := i.db.Iterate(i.prefix, basedb.IterateOptions{Start: cursor, Limit: 1}, func(obj basedb.Obj) error {
+			next = obj.Key
+			return nil
+		})
+		if err != nil {
+			return pruned, size, errors.Wrap(err, "failed to find next identifier")
+		}
+		if len(next) < identifierLen {
+			return pruned, size, nil
+		}
+		identifier := bytes.Clone(next[:identifierLen])
+
+		n, s, err := i.pruneIdentifierHistory(identifier, below)
+		pruned += n
+		size += s
+		if err != nil {
+			return pruned, size, err
+		}
+
+		cursor = successor(identifier)
+		if cursor == nil {
+			return pruned, size, nil
+		}
+	}
+}
+
+func (i *ibftStorage) pruneIdentifierHistory(identifier []byte, below specqbft.Height) (int, int64, error) {
+	prefix := i.historyPrefix(identifier)
+
+	var pruned int
+	var size int64
+	for {
+		var batch []basedb.Obj
+		opts := basedb.IterateOptions{End: heightKey(below), Limit: pruneBatchSize}
+		err := i.db.Iterate(prefix, opts, func(obj basedb.Obj) error {
+			batch = append(batch, obj)
+			return nil
+		})
+		if err != nil {
+			return pruned, size, errors.Wrap(err, "failed to get instances to prune")
+		}
+		if len(batch) == 0 {
+			return pruned, size, nil
+		}
+
+		err = i.db.Update(func(txn basedb.Txn) error {
+			for _, obj := range batch {
+				if err := txn.Delete(prefix, obj.Key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return pruned, size, errors.Wrap(err, "failed to remove instances")
+		}
+		for _, obj := range batch {
+			pruned++
+			size += int64(len(obj.Value))
+		}
+
+		if len(batch) < pruneBatchSize {
+			return pruned, size, nil
+		}
+	}
+}
+
+// WalkHistory calls handler with the height and encoded size of every historical instance, of all identifiers.
+func (i *ibftStorage) WalkHistory(handler func(height specqbft.Height, size int) error) error {
+	identifierLen := len(spectypes.MessageID{})
+	historyKeyLen := identifierLen + len(instanceKey) + 8
+
+	return i.db.Iterate(i.prefix, basedb.IterateOptions{}, func(obj basedb.Obj) error {
+		// Other keys of the store, and keys of stores with a longer prefix, have other lengths or key names.
+		if len(obj.Key) != historyKeyLen || string(obj.Key[identifierLen:identifierLen+len(instanceKey)]) != instanceKey {
+			return nil
+		}
+		height := specqbft.Height(binary.BigEndian.Uint64(obj.Key[identifierLen+len(instanceKey):]))
+		return handler(height, len(obj.Value))
+	})
+}
+
 func (i *ibftStorage) save(value []byte, id string, pk []byte, keyParams ...[]byte) error {
 	prefix := append(i.prefix, pk...)
 	key := i.key(id, keyParams...)
@@ -175,6 +301,67 @@ func (i *ibftStorage) delete(id string, pk []byte, keyParams ...[]byte) error {
 	return i.db.Delete(prefix, key)
 }
 
+// MigrateLegacyHistory moves the historical instances of the store with the given prefix from their legacy keys,
+// which aren't ordered by height, and returns the number of instances moved.
+func MigrateLegacyHistory(db basedb.Database, prefix string) (int, error) {
+	identifierLen := len(spectypes.MessageID{})
+	legacyKeyLen := identifierLen + len(legacyInstanceKey) + 8
+
+	moved := 0
+	var batch []basedb.Obj
+	flush := func() error {
+		err := db.Update(func(txn basedb.Txn) error {
+			for _, obj := range batch {
+				height := specqbft.Height(binary.LittleEndian.Uint64(obj.Key[identifierLen+len(legacyInstanceKey):]))
+				key := append(bytes.Clone(obj.Key[:identifierLen]), instanceKey...)
+				key = append(key, heightKey(height)...)
+				if err := txn.Set([]byte(prefix), key, obj.Value); err != nil {
+					return err
+				}
+				if err := txn.Delete([]byte(prefix), obj.Key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		moved += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	err := db.GetAll([]byte(prefix), func(_ int, obj basedb.Obj) error {
+		// Other keys of the store, and keys of stores with a longer prefix, have other lengths or key names.
+		if len(obj.Key) != legacyKeyLen || string(obj.Key[identifierLen:identifierLen+len(legacyInstanceKey)]) != legacyInstanceKey {
+			return nil
+		}
+		batch = append(batch, obj)
+		if len(batch) < legacyMigrationBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return moved, err
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return moved, err
+		}
+	}
+	return moved, nil
+}
+
+// historyPrefix returns the prefix of the historical instances of the given identifier.
+func (i *ibftStorage) historyPrefix(identifier []byte) []byte {
+	prefix := make([]byte, 0, len(i.prefix)+len(identifier)+len(instanceKey))
+	prefix = append(prefix, i.prefix...)
+	prefix = append(prefix, identifier...)
+	return append(prefix, instanceKey...)
+}
+
 func (i *ibftStorage) key(id string, params ...[]byte) []byte {
 	ret := []byte(id)
 	for _, p := range params {
@@ -183,8 +370,19 @@ func (i *ibftStorage) key(id string, params ...[]byte) []byte {
 	return ret
 }
 
-func uInt64ToByteSlice(n uint64) []byte {
-	b := make([]byte, 8)
-	binary.LittleEndian.PutUint64(b, n)
-	return b
+func heightKey(height specqbft.Height) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(height))
+}
+
+// successor returns the smallest key which is greater than all keys with the given prefix,
+// or nil if there is none.
+func successor(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			next := bytes.Clone(prefix[:i+1])
+			next[i]++
+			return next
+		}
+	}
+	return nil
 }
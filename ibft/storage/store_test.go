@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"encoding/binary"
+	"math"
 	"testing"
 
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
@@ -21,35 +23,6 @@ func TestCleanInstances(t *testing.T) {
 	storage, err := newTestIbftStorage(logger, "test")
 	require.NoError(t, err)
 
-	generateInstance := func(id spectypes.MessageID, h specqbft.Height) *qbftstorage.StoredInstance {
-		return &qbftstorage.StoredInstance{
-			State: &specqbft.State{
-				ID:                   id[:],
-				Round:                1,
-				Height:               h,
-				LastPreparedRound:    1,
-				LastPreparedValue:    []byte("value"),
-				Decided:              true,
-				DecidedValue:         []byte("value"),
-				ProposeContainer:     specqbft.NewMsgContainer(),
-				PrepareContainer:     specqbft.NewMsgContainer(),
-				CommitContainer:      specqbft.NewMsgContainer(),
-				RoundChangeContainer: specqbft.NewMsgContainer(),
-			},
-			DecidedMessage: &specqbft.SignedMessage{
-				Signature: []byte("sig"),
-				Signers:   []spectypes.OperatorID{1},
-				Message: specqbft.Message{
-					MsgType:    specqbft.CommitMsgType,
-					Height:     h,
-					Round:      1,
-					Identifier: id[:],
-					Root:       [32]byte{},
-				},
-			},
-		}
-	}
-
 	msgsCount := 10
 	for i := 0; i < msgsCount; i++ {
 		require.NoError(t, storage.SaveInstance(generateInstance(msgID, specqbft.Height(i))))
@@ -169,6 +142,170 @@ func TestSaveAndFetchState(t *testing.T) {
 	require.Equal(t, []byte("value"), savedInstance.State.DecidedValue)
 }
 
+func TestGetInstances(t *testing.T) {
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleAttester)
+	storage, err := newTestIbftStorage(logging.TestLogger(t), "test")
+	require.NoError(t, err)
+
+	// Heights beyond a byte are ordered by their value, with a gap.
+	for h := specqbft.Height(0); h < 300; h++ {
+		if h >= 100 && h < 200 {
+			continue
+		}
+		require.NoError(t, storage.SaveInstance(generateInstance(msgID, h)))
+	}
+	otherMsgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("other_pk"), spectypes.BNRoleAttester)
+	require.NoError(t, storage.SaveInstance(generateInstance(otherMsgID, 50)))
+
+	heights := func(instances []*qbftstorage.StoredInstance) []specqbft.Height {
+		var heights []specqbft.Height
+		for _, instance := range instances {
+			heights = append(heights, instance.State.Height)
+		}
+		return heights
+	}
+
+	instances, err := storage.GetInstances(msgID[:], 98, math.MaxUint64, 4)
+	require.NoError(t, err)
+	require.Equal(t, []specqbft.Height{98, 99, 200, 201}, heights(instances))
+
+	instances, err = storage.GetInstancesInRange(msgID[:], 250, 260)
+	require.NoError(t, err)
+	require.Equal(t, []specqbft.Height{250, 251, 252, 253, 254, 255, 256, 257, 258, 259, 260}, heights(instances))
+
+	instances, err = storage.GetInstancesInRange(msgID[:], 0, math.MaxUint64)
+	require.NoError(t, err)
+	require.Len(t, instances, 200)
+
+	instances, err = storage.GetInstancesInRange(msgID[:], 120, 180)
+	require.NoError(t, err)
+	require.Empty(t, instances)
+}
+
+func TestMigrateLegacyHistory(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Store the history with legacy keys, next to the highest instance and the history of a store with a longer prefix.
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleSyncCommittee)
+	for h := specqbft.Height(0); h < 1500; h++ {
+		value, err := generateInstance(msgID, h).Encode()
+		require.NoError(t, err)
+		key := append(append(msgID[:], legacyInstanceKey...), binary.LittleEndian.AppendUint64(nil, uint64(h))...)
+		require.NoError(t, db.Set([]byte("SYNC_COMMITTEE"), key, value))
+		require.NoError(t, db.Set([]byte("SYNC_COMMITTEE_CONTRIBUTION"), key, value))
+	}
+	storage := New(db, "SYNC_COMMITTEE")
+	require.NoError(t, storage.SaveHighestInstance(generateInstance(msgID, 1500)))
+
+	moved, err := MigrateLegacyHistory(db, "SYNC_COMMITTEE")
+	require.NoError(t, err)
+	require.Equal(t, 1500, moved)
+
+	instances, err := storage.GetInstancesInRange(msgID[:], 0, math.MaxUint64)
+	require.NoError(t, err)
+	require.Len(t, instances, 1500)
+	for i, instance := range instances {
+		require.EqualValues(t, i, instance.State.Height)
+	}
+	highest, err := storage.GetHighestInstance(msgID[:])
+	require.NoError(t, err)
+	require.EqualValues(t, 1500, highest.State.Height)
+	count, err := db.CountPrefix([]byte("SYNC_COMMITTEE_CONTRIBUTION"))
+	require.NoError(t, err)
+	require.EqualValues(t, 1500, count)
+
+	// Migrating again doesn't move anything.
+	moved, err = MigrateLegacyHistory(db, "SYNC_COMMITTEE")
+	require.NoError(t, err)
+	require.Zero(t, moved)
+}
+
+func TestPruneHistory(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	// The keys of the contribution store share the prefix of the sync committee store.
+	storage := New(db, spectypes.BNRoleSyncCommittee.String())
+	contributionStorage := New(db, spectypes.BNRoleSyncCommitteeContribution.String())
+
+	msgIDs := []spectypes.MessageID{
+		spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk1"), spectypes.BNRoleSyncCommittee),
+		spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk2"), spectypes.BNRoleSyncCommittee),
+	}
+	for _, msgID := range msgIDs {
+		for h := specqbft.Height(0); h < 300; h++ {
+			require.NoError(t, storage.SaveHighestAndHistoricalInstance(generateInstance(msgID, h)))
+		}
+	}
+	contributionMsgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk1"), spectypes.BNRoleSyncCommitteeContribution)
+	require.NoError(t, contributionStorage.SaveHighestAndHistoricalInstance(generateInstance(contributionMsgID, 10)))
+
+	pruned, size, err := storage.PruneHistory(250)
+	require.NoError(t, err)
+	require.Equal(t, 500, pruned)
+	require.Positive(t, size)
+
+	for _, msgID := range msgIDs {
+		instances, err := storage.GetInstancesInRange(msgID[:], 0, math.MaxUint64)
+		require.NoError(t, err)
+		require.Len(t, instances, 50)
+		require.EqualValues(t, 250, instances[0].State.Height)
+
+		highest, err := storage.GetHighestInstance(msgID[:])
+		require.NoError(t, err)
+		require.EqualValues(t, 299, highest.State.Height)
+	}
+
+	instance, err := contributionStorage.GetInstance(contributionMsgID[:], 10)
+	require.NoError(t, err)
+	require.NotNil(t, instance)
+
+	var walked []specqbft.Height
+	require.NoError(t, storage.WalkHistory(func(height specqbft.Height, size int) error {
+		require.Positive(t, size)
+		walked = append(walked, height)
+		return nil
+	}))
+	require.Len(t, walked, 100)
+	for _, height := range walked {
+		require.GreaterOrEqual(t, height, specqbft.Height(250))
+	}
+}
+
+func generateInstance(id spectypes.MessageID, h specqbft.Height) *qbftstorage.StoredInstance {
+	return &qbftstorage.StoredInstance{
+		State: &specqbft.State{
+			ID:                   id[:],
+			Round:                1,
+			Height:               h,
+			LastPreparedRound:    1,
+			LastPreparedValue:    []byte("value"),
+			Decided:              true,
+			DecidedValue:         []byte("value"),
+			ProposeContainer:     specqbft.NewMsgContainer(),
+			PrepareContainer:     specqbft.NewMsgContainer(),
+			CommitContainer:      specqbft.NewMsgContainer(),
+			RoundChangeContainer: specqbft.NewMsgContainer(),
+		},
+		DecidedMessage: &specqbft.SignedMessage{
+			Signature: []byte("sig"),
+			Signers:   []spectypes.OperatorID{1},
+			Message: specqbft.Message{
+				MsgType:    specqbft.CommitMsgType,
+				Height:     h,
+				Round:      1,
+				Identifier: id[:],
+				Root:       [32]byte{},
+			},
+		},
+	}
+}
+
 func newTestIbftStorage(logger *zap.Logger, prefix string) (qbftstorage.QBFTStore, error) {
 	db, err := kv.NewInMemory(logger.Named(logging.NameBadgerDBLog), basedb.Options{
 		Reporting: true,
@@ -14,14 +14,17 @@ const (
 	NameWSServer         = "WSServer"
 	NameConnHandler      = "ConnHandler"
 
-	NameBadgerDBLog       = "BadgerDBLog"
-	NameBadgerDBReporting = "BadgerDBReporting"
-	NameCreateThreshold   = "CreateThreshold"
-	NameDiscoveryV5Logger = "DiscoveryV5Logger"
-	NameExportKeys        = "ExportKeys"
-	NameP2PStorage        = "P2PStorage"
-	NamePubsubTrace       = "PubsubTrace"
-	NameScoreInspector    = "ScoreInspector"
-	NameEventHandler      = "EventHandler"
-	NameDutyFetcher       = "DutyFetcher"
+	NameBadgerDBLog        = "BadgerDBLog"
+	NameBadgerDBReporting  = "BadgerDBReporting"
+	NamePebbleDBLog        = "PebbleDBLog"
+	NamePebbleDBReporting  = "PebbleDBReporting"
+	NameCreateThreshold    = "CreateThreshold"
+	NameDiscoveryV5Logger  = "DiscoveryV5Logger"
+	NameExportKeys         = "ExportKeys"
+	NameP2PStorage         = "P2PStorage"
+	NamePubsubTrace        = "PubsubTrace"
+	NameScoreInspector     = "ScoreInspector"
+	NameEventHandler       = "EventHandler"
+	NameDutyFetcher        = "DutyFetcher"
+	NameSlashingProtection = "SlashingProtection"
 )
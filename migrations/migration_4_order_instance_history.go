@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging/fields"
+)
+
+// migration_4_order_instance_history re-keys the historical instances by their big-endian height,
+// so that the history can be scanned in ranges of heights.
+var migration_4_order_instance_history = Migration{
+	Name: "migration_4_order_instance_history",
+	Run: func(ctx context.Context, logger *zap.Logger, opt Options, key []byte, completed CompletedFunc) error {
+		roles := []spectypes.BeaconRole{
+			spectypes.BNRoleAttester,
+			spectypes.BNRoleProposer,
+			spectypes.BNRoleAggregator,
+			spectypes.BNRoleSyncCommittee,
+			spectypes.BNRoleSyncCommitteeContribution,
+			spectypes.BNRoleValidatorRegistration,
+			spectypes.BNRoleVoluntaryExit,
+		}
+		for _, role := range roles {
+			moved, err := storage.MigrateLegacyHistory(opt.Db, role.String())
+			if err != nil {
+				return fmt.Errorf("failed to migrate %s instances: %w", role, err)
+			}
+			logger.Debug("migrated historical instances", fields.Role(role), fields.Count(moved))
+		}
+		return completed(opt.Db)
+	},
+}
@@ -14,12 +14,30 @@ import (
 	"github.com/bloxapp/ssv/storage/kv"
 )
 
-func setupOptions(ctx context.Context, t *testing.T) (Options, error) {
+// testEachEngine runs the test with every storage engine.
+func testEachEngine(t *testing.T, test func(t *testing.T, engine string)) {
+	for _, engine := range []string{basedb.EngineBadger, basedb.EnginePebble} {
+		engine := engine
+		t.Run(engine, func(t *testing.T) {
+			test(t, engine)
+		})
+	}
+}
+
+func setupOptions(ctx context.Context, t *testing.T, engine string) (Options, error) {
 	// Create in-memory test DB.
-	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{
+	options := basedb.Options{
 		Reporting: true,
 		Ctx:       ctx,
-	})
+	}
+	var db basedb.Database
+	var err error
+	switch engine {
+	case basedb.EnginePebble:
+		db, err = kv.NewPebbleInMemory(logging.TestLogger(t), options)
+	default:
+		db, err = kv.NewInMemory(logging.TestLogger(t), options)
+	}
 	if err != nil {
 		return Options{}, err
 	}
@@ -30,9 +48,13 @@ func setupOptions(ctx context.Context, t *testing.T) (Options, error) {
 }
 
 func Test_RunNotMigratingTwice(t *testing.T) {
+	testEachEngine(t, test_RunNotMigratingTwice)
+}
+
+func test_RunNotMigratingTwice(t *testing.T, engine string) {
 	ctx := context.Background()
 	logger := logging.TestLogger(t)
-	opt, err := setupOptions(ctx, t)
+	opt, err := setupOptions(ctx, t, engine)
 	require.NoError(t, err)
 
 	var count int
@@ -58,9 +80,13 @@ func Test_RunNotMigratingTwice(t *testing.T) {
 }
 
 func Test_Rollback(t *testing.T) {
+	testEachEngine(t, test_Rollback)
+}
+
+func test_Rollback(t *testing.T, engine string) {
 	ctx := context.Background()
 	logger := logging.TestLogger(t)
-	opt, err := setupOptions(ctx, t)
+	opt, err := setupOptions(ctx, t, engine)
 	require.NoError(t, err)
 
 	// Test that migration fails and rolls back on error.
@@ -85,9 +111,13 @@ func Test_Rollback(t *testing.T) {
 }
 
 func Test_NextMigrationNotExecutedOnFailure(t *testing.T) {
+	testEachEngine(t, test_NextMigrationNotExecutedOnFailure)
+}
+
+func test_NextMigrationNotExecutedOnFailure(t *testing.T, engine string) {
 	ctx := context.Background()
 	logger := logging.TestLogger(t)
-	opt, err := setupOptions(ctx, t)
+	opt, err := setupOptions(ctx, t, engine)
 	require.NoError(t, err)
 
 	fakeError := errors.New("fake error")
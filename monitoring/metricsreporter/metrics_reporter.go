@@ -56,6 +56,10 @@ var (
 		Name: "ssv_execution_client_last_fetched_block",
 		Help: "Last fetched block by execution client",
 	})
+	executionClientInconsistencies = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv_execution_client_inconsistencies",
+		Help: "Count block hash disagreements between execution client endpoints",
+	})
 	validatorStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "ssv:validator:v2:status",
 		Help: "Validator status",
@@ -164,6 +168,7 @@ type MetricsReporter interface {
 	ExecutionClientReady()
 	ExecutionClientSyncing()
 	ExecutionClientFailure()
+	ExecutionClientInconsistent()
 	ExecutionClientLastFetchedBlock(block uint64)
 	OperatorPublicKey(operatorID spectypes.OperatorID, publicKey []byte)
 	ValidatorInactive(publicKey []byte)
@@ -226,6 +231,7 @@ func New(opts ...Option) MetricsReporter {
 		ssvNodeStatus,
 		executionClientStatus,
 		executionClientLastFetchedBlock,
+		executionClientInconsistencies,
 		validatorStatus,
 		eventProcessed,
 		eventProcessingFailed,
@@ -285,6 +291,10 @@ func (m *metricsReporter) ExecutionClientFailure() {
 	executionClientStatus.Set(executionClientFailure)
 }
 
+func (m *metricsReporter) ExecutionClientInconsistent() {
+	executionClientInconsistencies.Inc()
+}
+
 func (m *metricsReporter) ExecutionClientLastFetchedBlock(block uint64) {
 	executionClientLastFetchedBlock.Set(float64(block))
 }
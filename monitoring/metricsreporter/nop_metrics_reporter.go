@@ -19,6 +19,7 @@ func (n *nopMetrics) SSVNodeNotHealthy()
 func (n *nopMetrics) ExecutionClientReady()                                                         {}
 func (n *nopMetrics) ExecutionClientSyncing()                                                       {}
 func (n *nopMetrics) ExecutionClientFailure()                                                       {}
+func (n *nopMetrics) ExecutionClientInconsistent()                                                  {}
 func (n *nopMetrics) ExecutionClientLastFetchedBlock(block uint64)                                  {}
 func (n *nopMetrics) OperatorPublicKey(operatorID spectypes.OperatorID, publicKey []byte)           {}
 func (n *nopMetrics) ValidatorInactive(publicKey []byte)                                            {}
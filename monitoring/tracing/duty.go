@@ -0,0 +1,189 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Duty stages recorded as child spans of the duty span.
+const (
+	StagePreConsensus     = "pre_consensus"
+	StageConsensus        = "consensus"
+	StagePostConsensus    = "post_consensus"
+	StageBeaconSubmission = "beacon_submission"
+)
+
+// dutySpan is the span of a running duty, with the spans of its running stages.
+type dutySpan struct {
+	slot   phase0.Slot
+	ctx    context.Context
+	span   trace.Span
+	stages map[string]trace.Span
+}
+
+type dutyKey struct {
+	pubKey string
+	role   spectypes.BeaconRole
+}
+
+// duties holds the span of the running duty of each validator and role,
+// so that every component along the duty's lifecycle can attach to it.
+var duties = struct {
+	sync.Mutex
+	m map[dutyKey]*dutySpan
+}{m: make(map[dutyKey]*dutySpan)}
+
+func keyOf(pubKey []byte, role spectypes.BeaconRole) dutyKey {
+	return dutyKey{pubKey: hex.EncodeToString(pubKey), role: role}
+}
+
+// StartDuty starts the span of the given duty, unless it's already running, and returns its context.
+// A running span of a previous duty of the same validator and role is ended.
+// Spans which aren't sampled aren't kept, so tracing has no overhead when it's disabled.
+func StartDuty(ctx context.Context, pubKey []byte, duty *spectypes.Duty) context.Context {
+	key := keyOf(pubKey, duty.Type)
+
+	duties.Lock()
+	defer duties.Unlock()
+
+	if d, ok := duties.m[key]; ok {
+		if d.slot == duty.Slot {
+			return d.ctx
+		}
+		d.end(codes.Error, "superseded by a newer duty")
+		delete(duties.m, key)
+	}
+
+	ctx, span := Tracer().Start(ctx, "duty",
+		trace.WithAttributes(
+			attribute.String("ssv.role", duty.Type.String()),
+			attribute.Int64("ssv.slot", int64(duty.Slot)),
+			attribute.String("ssv.validator", key.pubKey),
+		))
+	if !span.IsRecording() {
+		return ctx
+	}
+	duties.m[key] = &dutySpan{
+		slot:   duty.Slot,
+		ctx:    ctx,
+		span:   span,
+		stages: make(map[string]trace.Span),
+	}
+	return ctx
+}
+
+// DutyContext returns the context of the running duty span of the given validator and role, if any.
+func DutyContext(pubKey []byte, role spectypes.BeaconRole) (context.Context, bool) {
+	duties.Lock()
+	defer duties.Unlock()
+
+	d, ok := duties.m[keyOf(pubKey, role)]
+	if !ok {
+		return nil, false
+	}
+	return d.ctx, true
+}
+
+// StartStage starts a child span of the running duty for the given stage.
+func StartStage(pubKey []byte, role spectypes.BeaconRole, stage string, attrs ...attribute.KeyValue) {
+	duties.Lock()
+	defer duties.Unlock()
+
+	d, ok := duties.m[keyOf(pubKey, role)]
+	if !ok {
+		return
+	}
+	if _, running := d.stages[stage]; running {
+		return
+	}
+	_, span := Tracer().Start(d.ctx, stage, trace.WithAttributes(attrs...))
+	d.stages[stage] = span
+}
+
+// EndStage ends the span of the given stage of the running duty.
+func EndStage(pubKey []byte, role spectypes.BeaconRole, stage string, attrs ...attribute.KeyValue) {
+	duties.Lock()
+	defer duties.Unlock()
+
+	d, ok := duties.m[keyOf(pubKey, role)]
+	if !ok {
+		return
+	}
+	if span, running := d.stages[stage]; running {
+		span.SetAttributes(attrs...)
+		span.End()
+		delete(d.stages, stage)
+	}
+}
+
+// DutyEvent adds an event to the span of the running duty.
+func DutyEvent(pubKey []byte, role spectypes.BeaconRole, name string, attrs ...attribute.KeyValue) {
+	duties.Lock()
+	defer duties.Unlock()
+
+	if d, ok := duties.m[keyOf(pubKey, role)]; ok {
+		d.span.AddEvent(name, trace.WithAttributes(attrs...))
+	}
+}
+
+// EndDuty ends the span of the running duty and of its unfinished stages.
+// The duty is marked as failed if err is not nil.
+func EndDuty(pubKey []byte, role spectypes.BeaconRole, outcome string, err error) {
+	key := keyOf(pubKey, role)
+
+	duties.Lock()
+	defer duties.Unlock()
+
+	d, ok := duties.m[key]
+	if !ok {
+		return
+	}
+	delete(duties.m, key)
+
+	d.span.SetAttributes(attribute.String("ssv.outcome", outcome))
+	if err != nil {
+		d.span.RecordError(err)
+		d.end(codes.Error, err.Error())
+		return
+	}
+	d.end(codes.Ok, "")
+}
+
+func (d *dutySpan) end(code codes.Code, description string) {
+	for _, span := range d.stages {
+		span.SetStatus(codes.Error, "unfinished")
+		span.End()
+	}
+	d.span.SetStatus(code, description)
+	d.span.End()
+}
+
+// RecordMessage records a message of a running duty: the time it spent queued since its receipt,
+// and the time it took to handle it. The returned function must be called once it's handled.
+func RecordMessage(pubKey []byte, role spectypes.BeaconRole, msgType string, received time.Time) (handled func(err error)) {
+	ctx, ok := DutyContext(pubKey, role)
+	if !ok || received.IsZero() {
+		return func(error) {}
+	}
+
+	attrs := trace.WithAttributes(attribute.String("ssv.msg_type", msgType))
+	_, queued := Tracer().Start(ctx, "queue", attrs, trace.WithTimestamp(received))
+	queued.End()
+
+	_, span := Tracer().Start(ctx, "handle_message", attrs)
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/utils/commons"
+)
+
+const (
+	instrumentationName = "github.com/bloxapp/ssv"
+	serviceName         = "ssv-node"
+)
+
+// Options configures the export of traces.
+type Options struct {
+	OTLPEndpoint string  `yaml:"OTLPEndpoint" env:"TRACING_OTLP_ENDPOINT" env-description:"OTLP/HTTP collector endpoint (host:port) to export traces to, tracing is disabled if empty"`
+	Insecure     bool    `yaml:"Insecure" env:"TRACING_INSECURE" env-description:"Export traces over plain HTTP instead of HTTPS"`
+	SampleRatio  float64 `yaml:"SampleRatio" env:"TRACING_SAMPLE_RATIO" env-default:"1" env-description:"Ratio of duties to trace, between 0 and 1"`
+}
+
+// Init sets up the global tracer provider to export traces to the configured OTLP collector.
+// Tracing is disabled if no endpoint is configured, in which case spans are no-ops.
+// The returned function flushes the pending spans and shuts down the exporter.
+func Init(ctx context.Context, logger *zap.Logger, opts Options) (shutdown func(context.Context) error, err error) {
+	if opts.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	clientOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.OTLPEndpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create OTLP exporter")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(commons.GetBuildData()),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.Info("exporting traces", zap.String("endpoint", opts.OTLPEndpoint), zap.Float64("sample_ratio", opts.SampleRatio))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the node.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
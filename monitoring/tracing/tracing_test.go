@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bloxapp/ssv/logging"
+)
+
+// collector is an in-process stub of an OTLP/HTTP collector.
+type collector struct {
+	mu    sync.Mutex
+	spans map[string]*tracepb.Span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				c.spans[span.Name] = span
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	resp, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(resp)
+}
+
+func TestDutyTracing(t *testing.T) {
+	c := &collector{spans: make(map[string]*tracepb.Span)}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+	shutdown, err := Init(context.Background(), logging.TestLogger(t), Options{
+		OTLPEndpoint: strings.TrimPrefix(server.URL, "http://"),
+		Insecure:     true,
+		SampleRatio:  1,
+	})
+	require.NoError(t, err)
+
+	pubKey := []byte{1, 2, 3}
+	duty := &spectypes.Duty{Type: spectypes.BNRoleProposer, Slot: 32}
+
+	StartDuty(context.Background(), pubKey, duty)
+	// Starting the same duty again continues its span.
+	StartDuty(context.Background(), pubKey, duty)
+	_, ok := DutyContext(pubKey, spectypes.BNRoleProposer)
+	require.True(t, ok)
+
+	handled := RecordMessage(pubKey, spectypes.BNRoleProposer, "consensus", time.Now().Add(-time.Second))
+	handled(nil)
+	StartStage(pubKey, spectypes.BNRoleProposer, StagePreConsensus)
+	EndStage(pubKey, spectypes.BNRoleProposer, StagePreConsensus)
+	StartStage(pubKey, spectypes.BNRoleProposer, StageConsensus)
+	DutyEvent(pubKey, spectypes.BNRoleProposer, "round timeout")
+	EndDuty(pubKey, spectypes.BNRoleProposer, "failed", errors.New("beacon node is down"))
+
+	_, ok = DutyContext(pubKey, spectypes.BNRoleProposer)
+	require.False(t, ok)
+
+	require.NoError(t, shutdown(context.Background()))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dutySpan := c.spans["duty"]
+	require.NotNil(t, dutySpan)
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, dutySpan.Status.Code)
+	require.Len(t, dutySpan.Events, 2) // round timeout and the recorded error
+	require.Equal(t, "round timeout", dutySpan.Events[0].Name)
+
+	for _, name := range []string{"queue", "handle_message", StagePreConsensus, StageConsensus} {
+		span := c.spans[name]
+		require.NotNil(t, span, name)
+		require.Equal(t, dutySpan.TraceId, span.TraceId, name)
+		require.Equal(t, dutySpan.SpanId, span.ParentSpanId, name)
+	}
+	queued := c.spans["queue"]
+	require.GreaterOrEqual(t, queued.EndTimeUnixNano-queued.StartTimeUnixNano, uint64(time.Second))
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, c.spans[StageConsensus].Status.Code)
+}
+
+func TestDisabledTracing(t *testing.T) {
+	shutdown, err := Init(context.Background(), logging.TestLogger(t), Options{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, shutdown(context.Background())) }()
+
+	pubKey := []byte{1}
+	StartDuty(context.Background(), pubKey, &spectypes.Duty{Type: spectypes.BNRoleAttester, Slot: 1})
+	_, ok := DutyContext(pubKey, spectypes.BNRoleAttester)
+	require.False(t, ok)
+}
@@ -30,6 +30,8 @@ type DiscV5Options struct {
 	NetworkKey *ecdsa.PrivateKey
 	// Bootnodes is a list of bootstrapper nodes
 	Bootnodes []string
+	// KnownNodes are the records of nodes known from previous runs, which seed the discovery along with the bootnodes
+	KnownNodes []string
 	// Subnets is a bool slice represents all the subnets the node is intreseted in
 	Subnets []byte
 	// EnableLogging when true enables logs to be emitted
@@ -92,6 +94,15 @@ func (opts *DiscV5Options) DiscV5Cfg(logger *zap.Logger) (*discover.Config, erro
 		}
 		dv5Cfg.Bootnodes = bootnodes
 	}
+	for _, record := range opts.KnownNodes {
+		// Known nodes may be outdated, so invalid ones are skipped rather than failing the discovery.
+		known, err := ParseENR(nil, false, record)
+		if err != nil {
+			logger.Debug("could not parse known node record", zap.Error(err))
+			continue
+		}
+		dv5Cfg.Bootnodes = append(dv5Cfg.Bootnodes, known...)
+	}
 
 	if opts.EnableLogging {
 		newLogger := log.New()
@@ -3,7 +3,6 @@ package p2pv1
 import (
 	"context"
 	"crypto/ecdsa"
-	"crypto/rsa"
 	"fmt"
 	"strings"
 	"time"
@@ -22,7 +21,10 @@ import (
 	"github.com/bloxapp/ssv/monitoring/metricsreporter"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/commons"
+	"github.com/bloxapp/ssv/network/peers"
+	"github.com/bloxapp/ssv/network/peers/connections"
 	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/operator/keys"
 	"github.com/bloxapp/ssv/operator/storage"
 	uc "github.com/bloxapp/ssv/utils/commons"
 )
@@ -37,6 +39,10 @@ type Config struct {
 	Ctx       context.Context
 	Bootnodes string `yaml:"Bootnodes" env:"BOOTNODES" env-description:"Bootnodes to use to start discovery, seperated with ';'" env-default:""`
 	Discovery string `yaml:"Discovery" env:"P2P_DISCOVERY" env-description:"Discovery system to use" env-default:"discv5"`
+	// StaticPeers are always kept connected, and are exempt from the peers limit and from trimming.
+	StaticPeers string `yaml:"StaticPeers" env:"STATIC_PEERS" env-description:"Multiaddrs of peers to always stay connected to, including their peer ID, seperated with ';'"`
+	// DenyPeers are never connected to.
+	DenyPeers string `yaml:"DenyPeers" env:"DENY_PEERS" env-description:"IDs of peers to never connect to, seperated with ';'"`
 
 	TCPPort     int    `yaml:"TcpPort" env:"TCP_PORT" env-default:"13001" env-description:"TCP port for p2p transport"`
 	UDPPort     int    `yaml:"UdpPort" env:"UDP_PORT" env-default:"12001" env-description:"UDP port for discovery"`
@@ -58,8 +64,8 @@ type Config struct {
 	DiscoveryTrace bool `yaml:"DiscoveryTrace" env:"DISCOVERY_TRACE" env-description:"Flag to turn on/off discovery tracing in logs"`
 	// NetworkPrivateKey is used for network identity, MUST be injected
 	NetworkPrivateKey *ecdsa.PrivateKey
-	// OperatorPrivateKey is used for operator identity, MUST be injected
-	OperatorPrivateKey *rsa.PrivateKey
+	// OperatorSigner is used for operator identity, MUST be injected
+	OperatorSigner keys.OperatorSigner
 	// OperatorPubKeyHash is hash of operator public key, used for identity, optional
 	OperatorPubKeyHash string
 	// OperatorID contains numeric operator ID
@@ -70,6 +76,8 @@ type Config struct {
 	UserAgent string
 	// NodeStorage is used to get operator metadata.
 	NodeStorage storage.Storage
+	// KnownPeers persists the known peers across restarts, optional.
+	KnownPeers *peers.KnownPeersStore
 	// Network defines a network configuration.
 	Network networkconfig.NetworkConfig
 	// MessageValidator validates incoming messages.
@@ -188,6 +196,22 @@ func (c *Config) TransformBootnodes() []string {
 	return append(extraBootnodes, c.Network.Bootnodes...)
 }
 
+// TransformStaticPeers parses the static peers
+func (c *Config) TransformStaticPeers() ([]peer.AddrInfo, error) {
+	if c.StaticPeers == "" {
+		return nil, nil
+	}
+	return connections.ParseStaticPeers(strings.Split(c.StaticPeers, ";"))
+}
+
+// TransformDenyPeers parses the denied peers
+func (c *Config) TransformDenyPeers() ([]peer.ID, error) {
+	if c.DenyPeers == "" {
+		return nil, nil
+	}
+	return connections.ParseDeniedPeers(strings.Split(c.DenyPeers, ";"))
+}
+
 func userAgent(fromCfg string) string {
 	if len(fromCfg) > 0 {
 		return fromCfg
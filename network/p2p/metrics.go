@@ -40,6 +40,10 @@ var (
 		Name: "ssv:network:router:in",
 		Help: "Counts incoming messages",
 	}, []string{"mt"})
+	metricsTimeToReady = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:network:time_to_ready_seconds",
+		Help: "Time from the start of the network until it's connected to peers in all of its subnets",
+	})
 )
 
 func init() {
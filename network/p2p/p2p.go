@@ -2,7 +2,7 @@ package p2pv1
 
 import (
 	"context"
-	"crypto/rsa"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,6 +26,7 @@ import (
 	"github.com/bloxapp/ssv/network/records"
 	"github.com/bloxapp/ssv/network/streams"
 	"github.com/bloxapp/ssv/network/topics"
+	"github.com/bloxapp/ssv/operator/keys"
 	operatorstorage "github.com/bloxapp/ssv/operator/storage"
 	"github.com/bloxapp/ssv/utils/async"
 	"github.com/bloxapp/ssv/utils/tasks"
@@ -45,6 +46,9 @@ const (
 	peersReportingInterval          = 60 * time.Second
 	peerIdentitiesReportingInterval = 5 * time.Minute
 	topicsReportingInterval         = 180 * time.Second
+	staticPeersInterval             = 30 * time.Second
+	knownPeersSaveInterval          = 5 * time.Minute
+	readyCheckInterval              = time.Second
 )
 
 // p2pNetwork implements network.P2PNetwork
@@ -66,6 +70,9 @@ type p2pNetwork struct {
 	msgValidator validation.MessageValidator
 	connHandler  connections.ConnHandler
 	connGater    connmgr.ConnectionGater
+	peerPolicy   *connections.PeerPolicy
+	// peerPolicyMu serializes protecting the static peers with replacing them.
+	peerPolicyMu sync.Mutex
 	metrics      Metrics
 
 	state int32
@@ -77,8 +84,10 @@ type p2pNetwork struct {
 	libConnManager   connmgrcore.ConnManager
 
 	nodeStorage             operatorstorage.Storage
+	knownPeers              map[peer.ID]*peers.KnownPeer
+	discoveredPeers         *hashmap.Map[peer.ID, discoveredPeer]
 	operatorPKHashToPKCache *hashmap.Map[string, []byte] // used for metrics
-	operatorPrivateKey      *rsa.PrivateKey
+	operatorSigner          keys.OperatorSigner
 	operatorID              func() spectypes.OperatorID
 }
 
@@ -98,9 +107,10 @@ func New(logger *zap.Logger, cfg *Config, mr Metrics) network.P2PNetwork {
 		msgValidator:            cfg.MessageValidator,
 		state:                   stateClosed,
 		activeValidators:        hashmap.New[string, validatorStatus](),
+		discoveredPeers:         hashmap.New[peer.ID, discoveredPeer](),
 		nodeStorage:             cfg.NodeStorage,
 		operatorPKHashToPKCache: hashmap.New[string, []byte](),
-		operatorPrivateKey:      cfg.OperatorPrivateKey,
+		operatorSigner:          cfg.OperatorSigner,
 		operatorID:              cfg.OperatorID,
 		metrics:                 mr,
 	}
@@ -140,6 +150,7 @@ func (n *p2pNetwork) Close() error {
 	atomic.SwapInt32(&n.state, stateClosing)
 	defer atomic.StoreInt32(&n.state, stateClosed)
 	n.cancel()
+	n.saveKnownPeers(n.interfaceLogger)
 	if err := n.libConnManager.Close(); err != nil {
 		n.interfaceLogger.Warn("could not close discovery", zap.Error(err))
 	}
@@ -165,10 +176,15 @@ func (n *p2pNetwork) Start(logger *zap.Logger) error {
 	}
 
 	logger.Info("starting")
+	go n.reportTimeToReady(logger, time.Now())
 
 	go n.startDiscovery(logger)
 
+	go n.connectStaticPeers(logger)
+	async.Interval(n.ctx, staticPeersInterval, func() { n.connectStaticPeers(logger) })
+
 	async.Interval(n.ctx, connManagerGCInterval, n.peersBalancing(logger))
+	async.Interval(n.ctx, knownPeersSaveInterval, func() { n.saveKnownPeers(logger) })
 	// don't report metrics in tests
 	if n.cfg.Metrics != nil {
 		async.Interval(n.ctx, peersReportingInterval, n.reportAllPeers(logger))
@@ -196,9 +212,17 @@ func (n *p2pNetwork) peersBalancing(logger *zap.Logger) func() {
 		ctx, cancel := context.WithTimeout(n.ctx, connManagerGCTimeout)
 		defer cancel()
 
+		// Static peers are protected regardless of their score, so they don't take up the best peers.
+		scoredPeers := make([]peer.ID, 0, len(allPeers))
+		for _, pid := range allPeers {
+			if !n.peerPolicy.IsStatic(pid) {
+				scoredPeers = append(scoredPeers, pid)
+			}
+		}
+
 		connMgr := peers.NewConnManager(logger, n.libConnManager, n.idx)
 		mySubnets := records.Subnets(n.subnets).Clone()
-		connMgr.TagBestPeers(logger, n.cfg.MaxPeers-1, mySubnets, allPeers, n.cfg.TopicMaxPeers)
+		connMgr.TagBestPeers(logger, n.cfg.MaxPeers-1, mySubnets, scoredPeers, n.cfg.TopicMaxPeers)
 		connMgr.TrimPeers(ctx, logger, n.host.Network())
 	}
 }
@@ -213,8 +237,10 @@ func (n *p2pNetwork) startDiscovery(logger *zap.Logger) {
 		defer cancel()
 		n.backoffConnector.Connect(ctx, discoveredPeers)
 	}()
+	n.reconnectKnownPeers(logger, discoveredPeers)
 	err := tasks.Retry(func() error {
 		return n.disc.Bootstrap(logger, func(e discovery.PeerEvent) {
+			n.discoveredPeers.Set(e.AddrInfo.ID, discoveredPeer{record: e.Node.String(), seen: time.Now()})
 			if !n.idx.CanConnect(e.AddrInfo.ID) {
 				return
 			}
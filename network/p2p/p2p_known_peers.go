@@ -0,0 +1,208 @@
+package p2pv1
+
+import (
+	"sort"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/network/peers"
+	"github.com/bloxapp/ssv/network/records"
+)
+
+// knownPeersRetention is how long peers which aren't seen anymore are remembered.
+const knownPeersRetention = 72 * time.Hour
+
+// discoveredPeer is the record of a peer found by discovery.
+type discoveredPeer struct {
+	record string
+	seen   time.Time
+}
+
+// loadKnownPeers loads the peers known from previous runs into the peers index and the peerstore,
+// so that their subnets and scores are known before they're connected to.
+func (n *p2pNetwork) loadKnownPeers(logger *zap.Logger) {
+	n.knownPeers = make(map[peer.ID]*peers.KnownPeer)
+	if n.cfg.KnownPeers == nil {
+		return
+	}
+	known, err := n.cfg.KnownPeers.KnownPeers()
+	if err != nil {
+		logger.Warn("could not load known peers", zap.Error(err))
+		return
+	}
+
+	for _, kp := range known {
+		if kp.ID == n.host.ID() {
+			continue
+		}
+		n.knownPeers[kp.ID] = kp
+
+		if subnets, err := (records.Subnets{}).FromString(kp.Subnets); err == nil && len(subnets) > 0 {
+			n.idx.UpdatePeerSubnets(kp.ID, subnets)
+		}
+		for _, score := range kp.Scores {
+			score := score
+			_ = n.idx.Score(kp.ID, &score)
+		}
+		var addrs []ma.Multiaddr
+		for _, addr := range kp.Addrs {
+			if maddr, err := ma.NewMultiaddr(addr); err == nil {
+				addrs = append(addrs, maddr)
+			}
+		}
+		n.host.Peerstore().AddAddrs(kp.ID, addrs, peerstore.AddressTTL)
+	}
+	logger.Debug("loaded known peers", fields.Count(len(n.knownPeers)))
+}
+
+// knownNodeRecords returns the node records of the known peers, to seed the discovery with.
+func (n *p2pNetwork) knownNodeRecords() []string {
+	var nodeRecords []string
+	for _, kp := range n.knownPeers {
+		if kp.ENR != "" {
+			nodeRecords = append(nodeRecords, kp.ENR)
+		}
+	}
+	return nodeRecords
+}
+
+// reconnectKnownPeers queues the known peers which share subnets with this node for connection,
+// starting with the most recently seen ones.
+func (n *p2pNetwork) reconnectKnownPeers(logger *zap.Logger, connect chan<- peer.AddrInfo) {
+	known := make([]*peers.KnownPeer, 0, len(n.knownPeers))
+	for _, kp := range n.knownPeers {
+		known = append(known, kp)
+	}
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].LastSeen.After(known[j].LastSeen)
+	})
+
+	mySubnets := records.Subnets(n.subnets)
+	queued := 0
+	for _, kp := range known {
+		if n.peerPolicy.IsDenied(kp.ID) || !n.idx.CanConnect(kp.ID) {
+			continue
+		}
+		if mySubnets.String() != records.ZeroSubnets && len(records.SharedSubnets(mySubnets, n.idx.GetPeerSubnets(kp.ID), 1)) == 0 {
+			continue
+		}
+		addrs := n.host.Peerstore().Addrs(kp.ID)
+		if len(addrs) == 0 {
+			continue
+		}
+		select {
+		case connect <- peer.AddrInfo{ID: kp.ID, Addrs: addrs}:
+			queued++
+		default:
+			logger.Debug("connector queue is full, skipping remaining known peers")
+			return
+		}
+	}
+	logger.Debug("reconnecting to known peers", fields.Count(queued))
+}
+
+// saveKnownPeers saves the connected and recently discovered peers, to reconnect to them after a restart.
+func (n *p2pNetwork) saveKnownPeers(logger *zap.Logger) {
+	if n.cfg.KnownPeers == nil || n.host == nil || n.idx == nil {
+		return
+	}
+	now := time.Now()
+	seenSince := now.Add(-knownPeersRetention)
+
+	known := make(map[peer.ID]*peers.KnownPeer)
+	update := func(id peer.ID, seen time.Time) *peers.KnownPeer {
+		kp, ok := known[id]
+		if !ok {
+			kp = &peers.KnownPeer{ID: id}
+			if previous, ok := n.knownPeers[id]; ok {
+				kp.ENR = previous.ENR
+			}
+			known[id] = kp
+		}
+		if seen.After(kp.LastSeen) {
+			kp.LastSeen = seen
+		}
+		return kp
+	}
+
+	var forgotten []peer.ID
+	n.discoveredPeers.Range(func(id peer.ID, dp discoveredPeer) bool {
+		if dp.seen.Before(seenSince) {
+			forgotten = append(forgotten, id)
+			return true
+		}
+		update(id, dp.seen).ENR = dp.record
+		return true
+	})
+	for _, id := range forgotten {
+		n.discoveredPeers.Del(id)
+	}
+	for _, id := range n.host.Network().Peers() {
+		update(id, now)
+	}
+
+	list := make([]*peers.KnownPeer, 0, len(known))
+	for id, kp := range known {
+		for _, addr := range n.host.Peerstore().Addrs(id) {
+			kp.Addrs = append(kp.Addrs, addr.String())
+		}
+		if subnets := n.idx.GetPeerSubnets(id); len(subnets) > 0 {
+			kp.Subnets = subnets.String()
+		}
+		kp.Scores, _ = n.idx.GetScore(id, validationScoreName)
+		list = append(list, kp)
+	}
+	if err := n.cfg.KnownPeers.SaveKnownPeers(list, seenSince); err != nil {
+		logger.Warn("could not save known peers", zap.Error(err))
+		return
+	}
+	logger.Debug("saved known peers", fields.Count(len(list)))
+}
+
+// reportTimeToReady reports the time it took since the given start until the network was ready
+// and connected to peers in all of its subnets.
+func (n *p2pNetwork) reportTimeToReady(logger *zap.Logger, start time.Time) {
+	ticker := time.NewTicker(readyCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !n.isReady() || !n.subnetsConnected() {
+			continue
+		}
+		metricsTimeToReady.Set(time.Since(start).Seconds())
+		logger.Info("network is ready", fields.Duration(start))
+		return
+	}
+}
+
+// subnetsConnected returns whether there is a connected peer in each of the subnets of this node,
+// or any connected peer if it has no subnets.
+func (n *p2pNetwork) subnetsConnected() bool {
+	stats := n.idx.GetSubnetsStats()
+	if stats == nil {
+		return false
+	}
+	anySubnet := false
+	for subnet, active := range n.subnets {
+		if active == 0 {
+			continue
+		}
+		anySubnet = true
+		if subnet >= len(stats.Connected) || stats.Connected[subnet] == 0 {
+			return false
+		}
+	}
+	if !anySubnet {
+		return len(n.host.Network().Peers()) > 0
+	}
+	return true
+}
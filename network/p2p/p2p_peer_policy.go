@@ -0,0 +1,102 @@
+package p2pv1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/network/peers"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+)
+
+// StaticPeers returns the peers which are always kept connected.
+func (n *p2pNetwork) StaticPeers() []peer.AddrInfo {
+	return n.peerPolicy.StaticPeers()
+}
+
+// DeniedPeers returns the peers which are never connected to.
+func (n *p2pNetwork) DeniedPeers() []peer.ID {
+	return n.peerPolicy.DeniedPeers()
+}
+
+// UpdatePeerPolicy replaces the static and denied peers and persists them, overriding the configured ones,
+// disconnecting from the denied peers and connecting to the static ones.
+func (n *p2pNetwork) UpdatePeerPolicy(static []peer.AddrInfo, denied []peer.ID) error {
+	logger := n.interfaceLogger
+
+	stored := &operatorstorage.PeerPolicy{
+		StaticPeers: []string{},
+		DenyPeers:   []string{},
+	}
+	for _, info := range static {
+		info := info
+		addrs, err := peer.AddrInfoToP2pAddrs(&info)
+		if err != nil {
+			return fmt.Errorf("invalid static peer %s: %w", info.ID, err)
+		}
+		for _, addr := range addrs {
+			stored.StaticPeers = append(stored.StaticPeers, addr.String())
+		}
+	}
+	for _, id := range denied {
+		stored.DenyPeers = append(stored.DenyPeers, id.String())
+	}
+
+	n.peerPolicyMu.Lock()
+	if err := n.nodeStorage.SavePeerPolicy(nil, stored); err != nil {
+		n.peerPolicyMu.Unlock()
+		return fmt.Errorf("save peer policy: %w", err)
+	}
+	for _, info := range n.peerPolicy.StaticPeers() {
+		n.libConnManager.Unprotect(info.ID, peers.StaticTag)
+	}
+	n.peerPolicy.Update(static, denied)
+	n.protectStaticPeers()
+	n.peerPolicyMu.Unlock()
+
+	for _, id := range denied {
+		if n.host.Network().Connectedness(id) != network.Connected {
+			continue
+		}
+		if err := n.host.Network().ClosePeer(id); err != nil {
+			logger.Debug("could not disconnect from denied peer", fields.PeerID(id), zap.Error(err))
+		}
+	}
+	logger.Info("updated peer policy", zap.Int("static_peers", len(static)), zap.Int("denied_peers", len(denied)))
+
+	go n.connectStaticPeers(logger)
+	return nil
+}
+
+// connectStaticPeers protects the static peers from trimming, and connects to those which aren't connected.
+func (n *p2pNetwork) connectStaticPeers(logger *zap.Logger) {
+	n.peerPolicyMu.Lock()
+	static := n.protectStaticPeers()
+	n.peerPolicyMu.Unlock()
+
+	for _, info := range static {
+		if n.host.Network().Connectedness(info.ID) == network.Connected {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(n.ctx, connectTimeout)
+		err := n.host.Connect(ctx, info)
+		cancel()
+		if err != nil {
+			logger.Debug("could not connect to static peer", fields.PeerID(info.ID), zap.Error(err))
+		}
+	}
+}
+
+// protectStaticPeers protects the static peers from trimming and returns them.
+// peerPolicyMu must be held, so that replaced static peers aren't protected again.
+func (n *p2pNetwork) protectStaticPeers() []peer.AddrInfo {
+	static := n.peerPolicy.StaticPeers()
+	for _, info := range static {
+		n.libConnManager.Protect(info.ID, peers.StaticTag)
+	}
+	return static
+}
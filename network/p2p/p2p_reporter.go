@@ -26,7 +26,7 @@ func (n *p2pNetwork) ReportValidation(logger *zap.Logger, msg *spectypes.SSVMess
 	}
 	peers := n.msgResolver.GetPeers(data)
 	for _, pi := range peers {
-		err := n.idx.Score(pi, &ssvpeers.NodeScore{Name: "validation", Value: msgValidationScore(res)})
+		err := n.idx.Score(pi, &ssvpeers.NodeScore{Name: validationScoreName, Value: msgValidationScore(res)})
 		if err != nil {
 			logger.Warn("could not score peer", fields.PeerID(pi), zap.Error(err))
 			continue
@@ -35,7 +35,8 @@ func (n *p2pNetwork) ReportValidation(logger *zap.Logger, msg *spectypes.SSVMess
 }
 
 const (
-	validationScoreLow = 5.0
+	validationScoreName = "validation"
+	validationScoreLow  = 5.0
 )
 
 func msgValidationScore(res protocolp2p.MsgValidationResult) float64 {
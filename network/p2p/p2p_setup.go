@@ -104,6 +104,30 @@ func (n *p2pNetwork) initCfg() error {
 	if n.cfg.TopicMaxPeers <= 0 {
 		n.cfg.TopicMaxPeers = minPeersBuffer / 2
 	}
+	staticPeers, err := n.cfg.TransformStaticPeers()
+	if err != nil {
+		return fmt.Errorf("parse static peers: %w", err)
+	}
+	denyPeers, err := n.cfg.TransformDenyPeers()
+	if err != nil {
+		return fmt.Errorf("parse deny peers: %w", err)
+	}
+	if n.nodeStorage != nil {
+		// The peer policy set through the SSV API persists across restarts.
+		stored, found, err := n.nodeStorage.GetPeerPolicy(nil)
+		if err != nil {
+			return fmt.Errorf("get stored peer policy: %w", err)
+		}
+		if found {
+			if staticPeers, err = connections.ParseStaticPeers(stored.StaticPeers); err != nil {
+				return fmt.Errorf("parse stored static peers: %w", err)
+			}
+			if denyPeers, err = connections.ParseDeniedPeers(stored.DenyPeers); err != nil {
+				return fmt.Errorf("parse stored deny peers: %w", err)
+			}
+		}
+	}
+	n.peerPolicy = connections.NewPeerPolicy(staticPeers, denyPeers)
 
 	return nil
 }
@@ -121,7 +145,7 @@ func (n *p2pNetwork) SetupHost(logger *zap.Logger) error {
 	if err != nil {
 		return errors.Wrap(err, "could not create resource manager")
 	}
-	n.connGater = connections.NewConnectionGater(logger, n.connectionsAtLimit)
+	n.connGater = connections.NewConnectionGater(logger, n.connectionsAtLimit, n.peerPolicy)
 	opts = append(opts, libp2p.ResourceManager(rmgr), libp2p.ConnectionGater(n.connGater))
 	host, err := libp2p.New(opts...)
 	if err != nil {
@@ -148,6 +172,7 @@ func (n *p2pNetwork) SetupServices(logger *zap.Logger) error {
 	if err := n.setupPeerServices(logger); err != nil {
 		return errors.Wrap(err, "could not setup peer services")
 	}
+	n.loadKnownPeers(logger)
 	if err := n.setupDiscovery(logger); err != nil {
 		return errors.Wrap(err, "could not setup discovery service")
 	}
@@ -228,7 +253,7 @@ func (n *p2pNetwork) setupPeerServices(logger *zap.Logger) error {
 	n.host.SetStreamHandler(peers.NodeInfoProtocol, handshaker.Handler(logger))
 	logger.Debug("handshaker is ready")
 
-	n.connHandler = connections.NewConnHandler(n.ctx, handshaker, subnetsProvider, n.idx, n.idx, n.idx, n.metrics)
+	n.connHandler = connections.NewConnHandler(n.ctx, handshaker, subnetsProvider, n.idx, n.idx, n.idx, n.peerPolicy, n.metrics)
 	n.host.Network().Notify(n.connHandler.Handle(logger))
 	logger.Debug("connection handler is ready")
 
@@ -249,6 +274,7 @@ func (n *p2pNetwork) setupDiscovery(logger *zap.Logger) error {
 			TCPPort:       n.cfg.TCPPort,
 			NetworkKey:    n.cfg.NetworkPrivateKey,
 			Bootnodes:     n.cfg.TransformBootnodes(),
+			KnownNodes:    n.knownNodeRecords(),
 			EnableLogging: n.cfg.DiscoveryTrace,
 		}
 		if len(n.subnets) > 0 {
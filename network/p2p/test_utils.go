@@ -22,6 +22,7 @@ import (
 	"github.com/bloxapp/ssv/network/peers/connections/mock"
 	"github.com/bloxapp/ssv/network/testing"
 	"github.com/bloxapp/ssv/networkconfig"
+	operatorkeys "github.com/bloxapp/ssv/operator/keys"
 	"github.com/bloxapp/ssv/utils/format"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
 )
@@ -137,7 +138,7 @@ func (ln *LocalNet) NewTestP2pNetwork(ctx context.Context, nodeIndex int, keys t
 	cfg.Ctx = ctx
 	cfg.Subnets = "00000000000000000000020000000000" //PAY ATTENTION for future test scenarios which use more than one eth-validator we need to make this field dynamically changing
 	cfg.NodeStorage = mock.NodeStorage{
-		MockGetPrivateKey:               keys.OperatorKey,
+		MockGetPrivateKey:               operatorkeys.PrivateKeyFromRSA(keys.OperatorKey),
 		RegisteredOperatorPublicKeyPEMs: []string{},
 	}
 	cfg.Metrics = nil
@@ -238,7 +239,7 @@ func NewNetConfig(keys testing.NodeKeys, operatorPubKeyHash string, bn *discover
 		PubSubTrace:        false,
 		PubSubScoring:      true,
 		NetworkPrivateKey:  keys.NetKey,
-		OperatorPrivateKey: keys.OperatorKey,
+		OperatorSigner:     operatorkeys.PrivateKeyFromRSA(keys.OperatorKey),
 		OperatorPubKeyHash: operatorPubKeyHash,
 		UserAgent:          ua,
 		Discovery:          discT,
@@ -17,6 +17,8 @@ import (
 
 const (
 	protectedTag = "ssv/subnets"
+	// StaticTag protects static peers from being trimmed.
+	StaticTag = "ssv/static"
 )
 
 type PeerScore float64
@@ -27,7 +29,7 @@ type PeerScore float64
 type ConnManager interface {
 	// TagBestPeers tags the best n peers from the given list, based on subnets distribution scores.
 	TagBestPeers(logger *zap.Logger, n int, mySubnets records.Subnets, allPeers []peer.ID, topicMaxPeers int)
-	// TrimPeers will trim unprotected peers.
+	// TrimPeers will trim unprotected peers, other than static peers.
 	TrimPeers(ctx context.Context, logger *zap.Logger, net libp2pnetwork.Network)
 }
 
@@ -72,7 +74,7 @@ func (c connManager) TrimPeers(ctx context.Context, logger *zap.Logger, net libp
 	// TODO: use libp2p's conn manager once ready
 	// c.connManager.TrimOpenConns(ctx)
 	for _, pid := range allPeers {
-		if !c.connManager.IsProtected(pid, protectedTag) {
+		if !c.connManager.IsProtected(pid, protectedTag) && !c.connManager.IsProtected(pid, StaticTag) {
 			err := net.ClosePeer(pid)
 			logger.Debug("closing peer", zap.String("pid", pid.String()), zap.Error(err))
 			// if err != nil {
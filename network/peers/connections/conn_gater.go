@@ -27,17 +27,19 @@ const (
 // connGater implements ConnectionGater interface:
 // https://github.com/libp2p/go-libp2p/core/blob/master/connmgr/gater.go
 type connGater struct {
-	logger    *zap.Logger // struct logger to implement connmgr.ConnectionGater
-	atLimit   func() bool
-	ipLimiter *leakybucket.Collector
+	logger     *zap.Logger // struct logger to implement connmgr.ConnectionGater
+	atLimit    func() bool
+	ipLimiter  *leakybucket.Collector
+	peerPolicy *PeerPolicy
 }
 
 // NewConnectionGater creates a new instance of ConnectionGater
-func NewConnectionGater(logger *zap.Logger, atLimit func() bool) connmgr.ConnectionGater {
+func NewConnectionGater(logger *zap.Logger, atLimit func() bool, peerPolicy *PeerPolicy) connmgr.ConnectionGater {
 	return &connGater{
-		logger:    logger,
-		atLimit:   atLimit,
-		ipLimiter: leakybucket.NewCollector(ipLimitRate, ipLimitBurst, ipLimitPeriod, true),
+		logger:     logger,
+		atLimit:    atLimit,
+		ipLimiter:  leakybucket.NewCollector(ipLimitRate, ipLimitBurst, ipLimitPeriod, true),
+		peerPolicy: peerPolicy,
 	}
 }
 
@@ -45,7 +47,7 @@ func NewConnectionGater(logger *zap.Logger, atLimit func() bool) connmgr.Connect
 // to the addresses of that peer being available/resolved. Blocking connections
 // at this stage is typical for blacklisting scenarios
 func (n *connGater) InterceptPeerDial(id peer.ID) bool {
-	return true
+	return !n.peerPolicy.IsDenied(id)
 }
 
 // InterceptAddrDial is called on an imminent outbound dial to a peer on a
@@ -74,6 +76,10 @@ func (n *connGater) InterceptAccept(multiaddrs libp2pnetwork.ConnMultiaddrs) boo
 // InterceptSecured is called for both inbound and outbound connections,
 // after a security handshake has taken place and we've authenticated the peer.
 func (n *connGater) InterceptSecured(direction libp2pnetwork.Direction, id peer.ID, multiaddrs libp2pnetwork.ConnMultiaddrs) bool {
+	if n.peerPolicy.IsDenied(id) {
+		n.logger.Debug("connection rejected from denied peer", zap.String("peer_id", id.String()))
+		return false
+	}
 	return true
 }
 
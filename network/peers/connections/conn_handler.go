@@ -31,6 +31,7 @@ type connHandler struct {
 	subnetsIndex    peers.SubnetsIndex
 	connIdx         peers.ConnectionIndex
 	peerInfos       peers.PeerInfoIndex
+	peerPolicy      *PeerPolicy
 	metrics         Metrics
 }
 
@@ -42,6 +43,7 @@ func NewConnHandler(
 	subnetsIndex peers.SubnetsIndex,
 	connIdx peers.ConnectionIndex,
 	peerInfos peers.PeerInfoIndex,
+	peerPolicy *PeerPolicy,
 	mr Metrics,
 ) ConnHandler {
 	return &connHandler{
@@ -51,6 +53,7 @@ func NewConnHandler(
 		subnetsIndex:    subnetsIndex,
 		connIdx:         connIdx,
 		peerInfos:       peerInfos,
+		peerPolicy:      peerPolicy,
 		metrics:         mr,
 	}
 }
@@ -135,7 +138,7 @@ func (ch *connHandler) Handle(logger *zap.Logger) *libp2pnetwork.NotifyBundle {
 				}
 			}
 
-			if !ch.sharesEnoughSubnets(logger, conn) {
+			if !ch.peerPolicy.IsStatic(pid) && !ch.sharesEnoughSubnets(logger, conn) {
 				return errors.New("peer doesn't share enough subnets")
 			}
 			return nil
@@ -170,7 +173,7 @@ func (ch *connHandler) Handle(logger *zap.Logger) *libp2pnetwork.NotifyBundle {
 				logger := connLogger(conn)
 				err := acceptConnection(logger, net, conn)
 				if err == nil {
-					if ch.connIdx.AtLimit(conn.Stat().Direction) {
+					if ch.connIdx.AtLimit(conn.Stat().Direction) && !ch.peerPolicy.IsStatic(conn.RemotePeer()) {
 						err = errors.New("reached peers limit")
 					}
 				}
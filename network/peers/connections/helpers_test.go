@@ -14,6 +14,7 @@ import (
 	"github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/network/peers/connections/mock"
 	"github.com/bloxapp/ssv/network/records"
+	"github.com/bloxapp/ssv/operator/keys"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
 )
 
@@ -99,7 +100,7 @@ func getTestingData(t *testing.T) TestData {
 		MockPeerstore: ps,
 	}
 	nst := mock.NodeStorage{
-		MockGetPrivateKey: senderPrivateKey,
+		MockGetPrivateKey: keys.PrivateKeyFromRSA(senderPrivateKey),
 		RegisteredOperatorPublicKeyPEMs: []string{
 			senderPublicKey,
 		},
@@ -2,7 +2,6 @@ package mock
 
 import (
 	"bytes"
-	"crypto/rsa"
 	"math/big"
 
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
@@ -10,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 
+	"github.com/bloxapp/ssv/operator/keys"
 	"github.com/bloxapp/ssv/operator/storage"
 	registrystorage "github.com/bloxapp/ssv/registry/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
@@ -18,7 +18,7 @@ import (
 var _ storage.Storage = NodeStorage{}
 
 type NodeStorage struct {
-	MockGetPrivateKey               *rsa.PrivateKey
+	MockGetPrivateKey               keys.OperatorPrivateKey
 	RegisteredOperatorPublicKeyPEMs []string
 }
 
@@ -52,6 +52,56 @@ func (m NodeStorage) GetLastProcessedBlock(txn basedb.Reader) (*big.Int, bool, e
 	panic("implement me")
 }
 
+func (m NodeStorage) SaveProcessedBlock(rw basedb.ReadWriter, block *storage.ProcessedBlock) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) GetProcessedBlocks(r basedb.Reader) ([]*storage.ProcessedBlock, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) PruneProcessedBlock(rw basedb.ReadWriter, number uint64) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) DeleteProcessedBlock(rw basedb.ReadWriter, number uint64) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) SavePendingTask(rw basedb.ReadWriter, task *storage.PendingTask) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) GetPendingTasks(r basedb.Reader) ([]*storage.PendingTask, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) DeletePendingTask(rw basedb.ReadWriter, block uint64, index uint32) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) SaveEncryptedShares(rw basedb.ReadWriter, validatorPK []byte, encryptedKeys [][]byte) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) GetEncryptedShares(r basedb.Reader, validatorPK []byte) ([][]byte, bool, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) DeleteEncryptedShares(rw basedb.ReadWriter, validatorPK []byte) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m NodeStorage) DropRegistryData() error {
 	//TODO implement me
 	panic("implement me")
@@ -107,6 +157,11 @@ func (m NodeStorage) GetRecipientDataMany(txn basedb.Reader, owners []common.Add
 	panic("implement me")
 }
 
+func (m NodeStorage) ListRecipients(txn basedb.Reader) ([]*registrystorage.RecipientData, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m NodeStorage) SaveRecipientData(txn basedb.ReadWriter, recipientData *registrystorage.RecipientData) (*registrystorage.RecipientData, error) {
 	//TODO implement me
 	panic("implement me")
@@ -142,7 +197,7 @@ func (m NodeStorage) DropShares() error {
 	panic("implement me")
 }
 
-func (m NodeStorage) GetPrivateKey() (*rsa.PrivateKey, bool, error) {
+func (m NodeStorage) GetPrivateKey() (keys.OperatorPrivateKey, bool, error) {
 	if m.MockGetPrivateKey != nil {
 		return m.MockGetPrivateKey, true, nil
 	} else {
@@ -155,6 +210,16 @@ func (m NodeStorage) SetupPrivateKey(operatorKeyBase64 string) ([]byte, error) {
 	panic("implement me")
 }
 
+func (m NodeStorage) SetupOperatorKey(operatorKey keys.OperatorPrivateKey) ([]byte, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) RotatePrivateKey(rw basedb.ReadWriter, operatorKey keys.OperatorPrivateKey) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m NodeStorage) GetConfig(rw basedb.ReadWriter) (*storage.ConfigLock, bool, error) {
 	panic("implement me")
 }
@@ -166,3 +231,21 @@ func (m NodeStorage) SaveConfig(rw basedb.ReadWriter, config *storage.ConfigLock
 func (m NodeStorage) DeleteConfig(rw basedb.ReadWriter) error {
 	panic("implement me")
 }
+
+func (m NodeStorage) SavePeerPolicy(rw basedb.ReadWriter, policy *storage.PeerPolicy) error {
+	return nil
+}
+
+func (m NodeStorage) GetPeerPolicy(r basedb.Reader) (*storage.PeerPolicy, bool, error) {
+	return nil, false, nil
+}
+
+func (m NodeStorage) ExportRegistry(r basedb.Reader) (*storage.RegistryState, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) ImportRegistry(rw basedb.ReadWriter, state *storage.RegistryState) error {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,111 @@
+package connections
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+)
+
+// PeerPolicy holds the static peers, which are always kept connected and exempt from the peers limit,
+// and the denied peers, which are never connected to. It can be updated at runtime.
+type PeerPolicy struct {
+	mu     sync.RWMutex
+	static map[peer.ID]peer.AddrInfo
+	denied map[peer.ID]struct{}
+}
+
+// NewPeerPolicy creates a new PeerPolicy with the given static and denied peers.
+func NewPeerPolicy(static []peer.AddrInfo, denied []peer.ID) *PeerPolicy {
+	p := &PeerPolicy{}
+	p.Update(static, denied)
+	return p
+}
+
+// Update replaces the static and denied peers.
+func (p *PeerPolicy) Update(static []peer.AddrInfo, denied []peer.ID) {
+	staticMap := make(map[peer.ID]peer.AddrInfo, len(static))
+	for _, info := range static {
+		staticMap[info.ID] = info
+	}
+	deniedMap := make(map[peer.ID]struct{}, len(denied))
+	for _, id := range denied {
+		deniedMap[id] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.static = staticMap
+	p.denied = deniedMap
+}
+
+// IsStatic returns whether the given peer is a static peer.
+func (p *PeerPolicy) IsStatic(id peer.ID) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.static[id]
+	return ok
+}
+
+// IsDenied returns whether the given peer is denied.
+func (p *PeerPolicy) IsDenied(id peer.ID) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.denied[id]
+	return ok
+}
+
+// StaticPeers returns the static peers.
+func (p *PeerPolicy) StaticPeers() []peer.AddrInfo {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	static := make([]peer.AddrInfo, 0, len(p.static))
+	for _, info := range p.static {
+		static = append(static, info)
+	}
+	return static
+}
+
+// DeniedPeers returns the denied peers.
+func (p *PeerPolicy) DeniedPeers() []peer.ID {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	denied := make([]peer.ID, 0, len(p.denied))
+	for id := range p.denied {
+		denied = append(denied, id)
+	}
+	return denied
+}
+
+// ParseStaticPeers parses the multiaddrs of static peers, which must include their peer ID,
+// e.g. /ip4/1.2.3.4/tcp/13001/p2p/16Uiu2HAm...
+func ParseStaticPeers(addrs []string) ([]peer.AddrInfo, error) {
+	var static []peer.AddrInfo
+	for _, addr := range addrs {
+		if addr == "" {
+			continue
+		}
+		info, err := peer.AddrInfoFromString(addr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid static peer %q", addr)
+		}
+		static = append(static, *info)
+	}
+	return static, nil
+}
+
+// ParseDeniedPeers parses the IDs of denied peers.
+func ParseDeniedPeers(ids []string) ([]peer.ID, error) {
+	var denied []peer.ID
+	for _, s := range ids {
+		if s == "" {
+			continue
+		}
+		id, err := peer.Decode(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid denied peer %q", s)
+		}
+		denied = append(denied, id)
+	}
+	return denied, nil
+}
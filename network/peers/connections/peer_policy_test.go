@@ -0,0 +1,55 @@
+package connections
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPeerPolicy(t *testing.T) {
+	staticID, deniedID, otherID := newPeerID(t), newPeerID(t), newPeerID(t)
+
+	static, err := ParseStaticPeers([]string{"/ip4/192.168.1.2/tcp/13001/p2p/" + staticID.String()})
+	require.NoError(t, err)
+	require.Len(t, static, 1)
+	denied, err := ParseDeniedPeers([]string{deniedID.String(), ""})
+	require.NoError(t, err)
+	require.Equal(t, []peer.ID{deniedID}, denied)
+
+	_, err = ParseStaticPeers([]string{"/ip4/192.168.1.2/tcp/13001"})
+	require.ErrorContains(t, err, "invalid static peer")
+	_, err = ParseDeniedPeers([]string{"not-a-peer"})
+	require.ErrorContains(t, err, "invalid denied peer")
+
+	policy := NewPeerPolicy(static, denied)
+	require.True(t, policy.IsStatic(staticID))
+	require.False(t, policy.IsStatic(otherID))
+	require.True(t, policy.IsDenied(deniedID))
+	require.False(t, policy.IsDenied(otherID))
+
+	gater := NewConnectionGater(zap.NewNop(), func() bool { return true }, policy)
+	require.False(t, gater.InterceptPeerDial(deniedID))
+	require.True(t, gater.InterceptPeerDial(otherID))
+	require.False(t, gater.InterceptSecured(libp2pnetwork.DirInbound, deniedID, nil))
+	require.True(t, gater.InterceptSecured(libp2pnetwork.DirInbound, otherID, nil))
+
+	// The policy can be replaced at runtime.
+	policy.Update(nil, []peer.ID{otherID})
+	require.False(t, policy.IsStatic(staticID))
+	require.Empty(t, policy.StaticPeers())
+	require.Equal(t, []peer.ID{otherID}, policy.DeniedPeers())
+	require.True(t, gater.InterceptPeerDial(deniedID))
+	require.False(t, gater.InterceptPeerDial(otherID))
+}
+
+func newPeerID(t *testing.T) peer.ID {
+	_, pub, err := crypto.GenerateSecp256k1Key(nil)
+	require.NoError(t, err)
+	id, err := peer.IDFromPublicKey(pub)
+	require.NoError(t, err)
+	return id
+}
@@ -1,7 +1,6 @@
 package peers
 
 import (
-	"crypto/rsa"
 	"io"
 
 	"github.com/libp2p/go-libp2p/core/network"
@@ -12,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv/network/records"
+	"github.com/bloxapp/ssv/operator/keys"
 )
 
 const (
@@ -57,7 +57,7 @@ type ScoreIndex interface {
 // NodeInfoIndex is an interface for managing records.NodeInfo of network peers
 type NodeInfoIndex interface {
 	// SelfSealed returns a sealed, encoded of self node info
-	SelfSealed(sender, recipient peer.ID, permissioned bool, operatorPrivateKey *rsa.PrivateKey) ([]byte, error)
+	SelfSealed(sender, recipient peer.ID, permissioned bool, operatorSigner keys.OperatorSigner) ([]byte, error)
 
 	// Self returns the current node info
 	Self() *records.NodeInfo
@@ -0,0 +1,81 @@
+package peers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+var knownPeersPrefix = []byte("p2p-known-peers/")
+
+// KnownPeer is the persisted record of a peer, used to reconnect to it and to seed discovery after a restart.
+type KnownPeer struct {
+	ID peer.ID `json:"id"`
+	// ENR is the node record of the peer, if it was discovered.
+	ENR      string      `json:"enr,omitempty"`
+	Addrs    []string    `json:"addrs,omitempty"`
+	Subnets  string      `json:"subnets,omitempty"`
+	Scores   []NodeScore `json:"scores,omitempty"`
+	LastSeen time.Time   `json:"last_seen"`
+}
+
+// KnownPeersStore persists the known peers of the node across restarts.
+type KnownPeersStore struct {
+	db basedb.Database
+}
+
+// NewKnownPeersStore creates a new KnownPeersStore.
+func NewKnownPeersStore(db basedb.Database) *KnownPeersStore {
+	return &KnownPeersStore{db: db}
+}
+
+// KnownPeers returns all the known peers.
+func (s *KnownPeersStore) KnownPeers() ([]*KnownPeer, error) {
+	var known []*KnownPeer
+	err := s.db.GetAll(knownPeersPrefix, func(_ int, obj basedb.Obj) error {
+		kp := &KnownPeer{}
+		if err := json.Unmarshal(obj.Value, kp); err != nil {
+			return errors.Wrap(err, "could not decode known peer")
+		}
+		known = append(known, kp)
+		return nil
+	})
+	return known, err
+}
+
+// SaveKnownPeers saves the given peers, and removes the peers which weren't seen since the given time.
+func (s *KnownPeersStore) SaveKnownPeers(known []*KnownPeer, seenSince time.Time) error {
+	return s.db.Update(func(txn basedb.Txn) error {
+		var stale [][]byte
+		err := txn.GetAll(knownPeersPrefix, func(_ int, obj basedb.Obj) error {
+			kp := &KnownPeer{}
+			if err := json.Unmarshal(obj.Value, kp); err != nil || kp.LastSeen.Before(seenSince) {
+				stale = append(stale, obj.Key)
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not get known peers")
+		}
+		for _, key := range stale {
+			if err := txn.Delete(knownPeersPrefix, key); err != nil {
+				return err
+			}
+		}
+
+		for _, kp := range known {
+			b, err := json.Marshal(kp)
+			if err != nil {
+				return errors.Wrap(err, "could not encode known peer")
+			}
+			if err := txn.Set(knownPeersPrefix, []byte(kp.ID), b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
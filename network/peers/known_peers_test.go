@@ -0,0 +1,75 @@
+package peers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/network/commons"
+	nettesting "github.com/bloxapp/ssv/network/testing"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+func TestKnownPeersStore(t *testing.T) {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewKnownPeersStore(db)
+
+	nks, err := nettesting.CreateKeys(3)
+	require.NoError(t, err)
+	var pids []peer.ID
+	for _, nk := range nks {
+		sk, err := commons.ECDSAPrivToInterface(nk.NetKey)
+		require.NoError(t, err)
+		pid, err := peer.IDFromPrivateKey(sk)
+		require.NoError(t, err)
+		pids = append(pids, pid)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	first := []*KnownPeer{
+		{
+			ID:       pids[0],
+			ENR:      "enr:-abc",
+			Addrs:    []string{"/ip4/127.0.0.1/tcp/13001"},
+			Subnets:  "0xffffffffffffffffffffffffffffffff",
+			Scores:   []NodeScore{{Name: "validation", Value: 1.5}},
+			LastSeen: now.Add(-time.Hour),
+		},
+		{ID: pids[1], LastSeen: now.Add(-time.Hour)},
+	}
+	require.NoError(t, store.SaveKnownPeers(first, now.Add(-2*time.Hour)))
+
+	known, err := store.KnownPeers()
+	require.NoError(t, err)
+	require.Len(t, known, 2)
+	for _, kp := range known {
+		if kp.ID == pids[0] {
+			require.Equal(t, first[0].ENR, kp.ENR)
+			require.Equal(t, first[0].Addrs, kp.Addrs)
+			require.Equal(t, first[0].Subnets, kp.Subnets)
+			require.Equal(t, first[0].Scores, kp.Scores)
+			require.True(t, first[0].LastSeen.Equal(kp.LastSeen))
+		}
+	}
+
+	// Peers which weren't seen since the given time are removed, others are kept or updated.
+	second := []*KnownPeer{
+		{ID: pids[1], LastSeen: now},
+		{ID: pids[2], LastSeen: now},
+	}
+	require.NoError(t, store.SaveKnownPeers(second, now.Add(-30*time.Minute)))
+
+	known, err = store.KnownPeers()
+	require.NoError(t, err)
+	var ids []peer.ID
+	for _, kp := range known {
+		ids = append(ids, kp.ID)
+	}
+	require.ElementsMatch(t, []peer.ID{pids[1], pids[2]}, ids)
+}
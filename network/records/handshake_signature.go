@@ -16,7 +16,8 @@ type HandshakeData struct {
 	SenderPublicKey []byte
 }
 
-func (h *HandshakeData) Hash() [32]byte {
+// Encode returns the data whose hash is signed by the sender.
+func (h *HandshakeData) Encode() []byte {
 	sb := strings.Builder{}
 
 	sb.WriteString(h.SenderPeerID.String())
@@ -24,5 +25,9 @@ func (h *HandshakeData) Hash() [32]byte {
 	sb.WriteString(strconv.FormatInt(h.Timestamp.Unix(), 10))
 	sb.Write(h.SenderPublicKey)
 
-	return sha256.Sum256([]byte(sb.String()))
+	return []byte(sb.String())
+}
+
+func (h *HandshakeData) Hash() [32]byte {
+	return sha256.Sum256(h.Encode())
 }
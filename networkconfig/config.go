@@ -1,6 +1,7 @@
 package networkconfig
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -21,6 +22,23 @@ var SupportedConfigs = map[string]NetworkConfig{
 	LocalTestnet.Name: LocalTestnet,
 }
 
+// genesisValidatorsRoots maps beacon networks to their genesis validators root.
+var genesisValidatorsRoots = map[spectypes.BeaconNetwork]spec.Root{
+	spectypes.MainNetwork:    mustParseRoot("4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"),
+	spectypes.PraterNetwork:  mustParseRoot("043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb"),
+	spectypes.HoleskyNetwork: mustParseRoot("9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1"),
+}
+
+func mustParseRoot(s string) spec.Root {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(spec.Root{}) {
+		panic(fmt.Sprintf("invalid root: %s", s))
+	}
+	var root spec.Root
+	copy(root[:], b)
+	return root
+}
+
 func GetNetworkConfigByName(name string) (NetworkConfig, error) {
 	if network, ok := SupportedConfigs[name]; ok {
 		return network, nil
@@ -69,3 +87,13 @@ func (n NetworkConfig) SlotsPerEpoch() uint64 {
 func (n NetworkConfig) GetGenesisTime() time.Time {
 	return time.Unix(int64(n.Beacon.MinGenesisTime()), 0)
 }
+
+// GenesisValidatorsRoot returns the genesis validators root of the beacon network.
+// Returns false if the root is unknown, which is the case for local testnets.
+func (n NetworkConfig) GenesisValidatorsRoot() (spec.Root, bool) {
+	if n.Beacon.GetNetwork().LocalTestNet {
+		return spec.Root{}, false
+	}
+	root, ok := genesisValidatorsRoots[n.Beacon.GetBeaconNetwork()]
+	return root, ok
+}
@@ -18,6 +18,12 @@ type Node interface {
 	Healthy(ctx context.Context) error
 }
 
+// MultiEndpointNode is a Node backed by several endpoints, which reports the health of each of them.
+type MultiEndpointNode interface {
+	Node
+	EndpointsHealthy(ctx context.Context) map[string]error
+}
+
 type Prober struct {
 	logger           *zap.Logger
 	interval         time.Duration
@@ -147,6 +153,24 @@ func (p *Prober) CheckBeaconNodeHealth(ctx context.Context) error {
 	return p.nodes["consensus client"].Healthy(ctx)
 }
 
+// CheckBeaconNodeEndpointsHealth returns the health of each consensus client endpoint,
+// or nil if the consensus client doesn't report its endpoints.
+func (p *Prober) CheckBeaconNodeEndpointsHealth(ctx context.Context) map[string]error {
+	return p.checkEndpointsHealth(ctx, "consensus client")
+}
+
+func (p *Prober) checkEndpointsHealth(ctx context.Context, name string) map[string]error {
+	p.nodesMu.Lock()
+	defer p.nodesMu.Unlock()
+	node, ok := p.nodes[name].(MultiEndpointNode)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.interval)
+	defer cancel()
+	return node.EndpointsHealthy(ctx)
+}
+
 func (p *Prober) CheckExecutionNodeHealth(ctx context.Context) error {
 	p.nodesMu.Lock()
 	defer p.nodesMu.Unlock()
@@ -155,6 +179,12 @@ func (p *Prober) CheckExecutionNodeHealth(ctx context.Context) error {
 	return p.nodes["execution client"].Healthy(ctx)
 }
 
+// CheckExecutionNodeEndpointsHealth returns the health of each execution client endpoint,
+// or nil if the execution client doesn't report its endpoints.
+func (p *Prober) CheckExecutionNodeEndpointsHealth(ctx context.Context) map[string]error {
+	return p.checkEndpointsHealth(ctx, "execution client")
+}
+
 func (p *Prober) CheckEventSyncerHealth(ctx context.Context) error {
 	p.nodesMu.Lock()
 	defer p.nodesMu.Unlock()
@@ -70,6 +70,39 @@ func TestProber_UnhealthyHandler(t *testing.T) {
 	require.False(t, healthy)
 }
 
+func TestProber_EndpointsHealth(t *testing.T) {
+	ctx := context.Background()
+
+	notHealthy := fmt.Errorf("not healthy")
+	multiNode := &multiEndpointNode{
+		endpoints: map[string]error{
+			"http://bn1": nil,
+			"http://bn2": notHealthy,
+		},
+	}
+	prober := NewProber(zap.L(), nil, map[string]Node{
+		"consensus client": multiNode,
+		"execution client": &node{},
+	})
+
+	endpoints := prober.CheckBeaconNodeEndpointsHealth(ctx)
+	require.Len(t, endpoints, 2)
+	require.NoError(t, endpoints["http://bn1"])
+	require.ErrorIs(t, endpoints["http://bn2"], notHealthy)
+
+	prober = NewProber(zap.L(), nil, map[string]Node{"consensus client": &node{}})
+	require.Nil(t, prober.CheckBeaconNodeEndpointsHealth(ctx))
+}
+
+type multiEndpointNode struct {
+	node
+	endpoints map[string]error
+}
+
+func (mn *multiEndpointNode) EndpointsHealthy(context.Context) map[string]error {
+	return mn.endpoints
+}
+
 type node struct {
 	healthy atomic.Pointer[error]
 }
@@ -22,9 +22,11 @@ import (
 	"github.com/bloxapp/ssv/beacon/goclient"
 	"github.com/bloxapp/ssv/logging"
 	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/monitoring/tracing"
 	"github.com/bloxapp/ssv/networkconfig"
 	"github.com/bloxapp/ssv/operator/duties/dutystore"
 	"github.com/bloxapp/ssv/operator/slotticker"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	"github.com/bloxapp/ssv/protocol/v2/types"
 )
 
@@ -87,6 +89,7 @@ type SchedulerOptions struct {
 	ValidatorExitCh     <-chan ExitDescriptor
 	SlotTickerProvider  slotticker.Provider
 	BuilderProposals    bool
+	ProposerConfig      beaconprotocol.ProposerConfig
 	DutyStore           *dutystore.Store
 }
 
@@ -142,8 +145,8 @@ func NewScheduler(opts *SchedulerOptions) *Scheduler {
 		reorg:    make(chan ReorgEvent),
 		waitCond: sync.NewCond(&sync.Mutex{}),
 	}
-	if s.builderProposals {
-		s.handlers = append(s.handlers, NewValidatorRegistrationHandler())
+	if s.builderProposals || opts.ProposerConfig != nil {
+		s.handlers = append(s.handlers, NewValidatorRegistrationHandler(s.builderProposals, opts.ProposerConfig))
 	}
 	return s
 }
@@ -369,8 +372,11 @@ func (s *Scheduler) ExecuteDuties(logger *zap.Logger, duties []*spectypes.Duty)
 		}
 		slotDelayHistogram.Observe(float64(slotDelay.Milliseconds()))
 		go func() {
+			ctx := tracing.StartDuty(context.Background(), duty.PubKey[:], duty)
 			if duty.Type == spectypes.BNRoleAttester || duty.Type == spectypes.BNRoleSyncCommittee {
+				_, span := tracing.Tracer().Start(ctx, "wait_one_third_or_valid_block")
 				s.waitOneThirdOrValidBlock(duty.Slot)
+				span.End()
 			}
 			s.executeDuty(logger, duty)
 		}()
@@ -327,7 +327,7 @@ func TestScheduler_Regression_IndicesChangeStuck(t *testing.T) {
 	s := NewScheduler(opts)
 
 	// add multiple mock duty handlers
-	s.handlers = []dutyHandler{NewValidatorRegistrationHandler()}
+	s.handlers = []dutyHandler{NewValidatorRegistrationHandler(true, nil)}
 	mockBeaconNode.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 	mockTicker.EXPECT().Next().Return(nil).AnyTimes()
 	err := s.Start(ctx, logger)
@@ -6,16 +6,26 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"go.uber.org/zap"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 )
 
 const validatorRegistrationEpochInterval = uint64(10)
 
 type ValidatorRegistrationHandler struct {
 	baseHandler
+
+	builderProposals bool
+	proposerConfig   beaconprotocol.ProposerConfig
 }
 
-func NewValidatorRegistrationHandler() *ValidatorRegistrationHandler {
-	return &ValidatorRegistrationHandler{}
+// NewValidatorRegistrationHandler creates a handler which registers the validators which propose blocks from external builders,
+// either by default or as overridden by the proposer config.
+func NewValidatorRegistrationHandler(builderProposals bool, proposerConfig beaconprotocol.ProposerConfig) *ValidatorRegistrationHandler {
+	return &ValidatorRegistrationHandler{
+		builderProposals: builderProposals,
+		proposerConfig:   proposerConfig,
+	}
 }
 
 func (h *ValidatorRegistrationHandler) Name() string {
@@ -39,6 +49,9 @@ func (h *ValidatorRegistrationHandler) HandleDuties(ctx context.Context) {
 				if !share.HasBeaconMetadata() || !share.BeaconMetadata.IsAttesting() {
 					continue
 				}
+				if !h.builderEnabled(share.ValidatorPubKey) {
+					continue
+				}
 
 				// if not passed first registration, should be registered within one epoch time in a corresponding slot
 				// if passed first registration, should be registered within validatorRegistrationEpochInterval epochs time in a corresponding slot
@@ -72,3 +85,12 @@ func (h *ValidatorRegistrationHandler) HandleDuties(ctx context.Context) {
 		}
 	}
 }
+
+func (h *ValidatorRegistrationHandler) builderEnabled(pubKey []byte) bool {
+	if h.proposerConfig != nil {
+		if enabled, ok := h.proposerConfig.BuilderEnabled(pubKey); ok {
+			return enabled
+		}
+	}
+	return h.builderProposals
+}
@@ -2,13 +2,17 @@ package fee_recipient
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/logging/fields"
 	"github.com/bloxapp/ssv/networkconfig"
 	"github.com/bloxapp/ssv/operator/slotticker"
 	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
@@ -18,9 +22,27 @@ import (
 
 //go:generate mockgen -package=mocks -destination=./mocks/controller.go -source=./controller.go
 
+const (
+	// preparationBatchSize is the maximum number of proposal preparations submitted in a single request.
+	preparationBatchSize = 500
+	// refreshEpochs is how often all the proposal preparations are resubmitted, even if unchanged.
+	// Beacon nodes keep a proposal preparation only through the epoch it's submitted in and the next 2 epochs.
+	refreshEpochs = 2
+	// headGapSlots is the number of slots without head events after which the beacon node
+	// is assumed to have been restarted or replaced, losing the submitted proposal preparations.
+	headGapSlots = 3
+)
+
 // RecipientController submit proposal preparation to beacon node for all committee validators
 type RecipientController interface {
 	Start(logger *zap.Logger)
+	// FeeRecipientUpdated submits the proposal preparations of the validators of the given owner.
+	FeeRecipientUpdated(owner common.Address)
+	// ValidatorsAdded submits the proposal preparations of the given validators, once their indices are known.
+	ValidatorsAdded(pubKeys ...[]byte)
+	// ProposerConfigReloaded submits the proposal preparations of all the validators,
+	// since their fee recipients may have been overridden.
+	ProposerConfigReloaded()
 }
 
 // ControllerOptions holds the needed dependencies
@@ -32,6 +54,7 @@ type ControllerOptions struct {
 	RecipientStorage   storage.Recipients
 	SlotTickerProvider slotticker.Provider
 	OperatorData       *storage.OperatorData
+	ProposerConfig     beaconprotocol.ProposerConfig
 }
 
 // recipientController implementation of RecipientController
@@ -43,6 +66,14 @@ type recipientController struct {
 	recipientStorage   storage.Recipients
 	slotTickerProvider slotticker.Provider
 	operatorData       *storage.OperatorData
+	proposerConfig     beaconprotocol.ProposerConfig
+
+	// mu guards the pending submissions, which are submitted together on the next slot.
+	mu             sync.Mutex
+	submitAll      bool
+	pendingOwners  map[common.Address]struct{}
+	pendingPubKeys map[string]struct{}
+	lastHead       time.Time
 }
 
 func NewController(opts *ControllerOptions) *recipientController {
@@ -54,44 +85,102 @@ func NewController(opts *ControllerOptions) *recipientController {
 		recipientStorage:   opts.RecipientStorage,
 		slotTickerProvider: opts.SlotTickerProvider,
 		operatorData:       opts.OperatorData,
+		proposerConfig:     opts.ProposerConfig,
+		pendingOwners:      make(map[common.Address]struct{}),
+		pendingPubKeys:     make(map[string]struct{}),
 	}
 }
 
+// Start submits the proposal preparations of all the validators on the next slot,
+// and then submits them again only when they change, every refreshEpochs, or when the beacon node seems to have restarted.
 func (rc *recipientController) Start(logger *zap.Logger) {
+	rc.requestSubmitAll()
+
+	if err := rc.beaconClient.Events(rc.ctx, []string{"head"}, rc.handleHeadEvent(logger)); err != nil {
+		logger.Warn("could not subscribe to head events, proposal preparations will only be refreshed periodically", zap.Error(err))
+	}
+
 	rc.listenToTicker(logger)
 }
 
-// listenToTicker loop over the given slot channel
-// TODO: re-think this logic, we can use validator map instead of iterating over all shares
-// in addition, submitting "same data" every slot is not efficient and can overload beacon node
-// instead we can subscribe to beacon node events and submit only when there is
-// a new fee recipient event (or new validator) was handled or when there is a syncing issue with beacon node
+func (rc *recipientController) FeeRecipientUpdated(owner common.Address) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.pendingOwners[owner] = struct{}{}
+}
+
+func (rc *recipientController) ValidatorsAdded(pubKeys ...[]byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, pubKey := range pubKeys {
+		rc.pendingPubKeys[string(pubKey)] = struct{}{}
+	}
+}
+
+func (rc *recipientController) ProposerConfigReloaded() {
+	rc.requestSubmitAll()
+}
+
+func (rc *recipientController) requestSubmitAll() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.submitAll = true
+}
+
+// handleHeadEvent requests submitting all the proposal preparations when head events resume after a gap,
+// which happens when the beacon node restarts or when failing over to another beacon node.
+func (rc *recipientController) handleHeadEvent(logger *zap.Logger) func(event *eth2apiv1.Event) {
+	headGap := rc.network.SlotDurationSec() * headGapSlots
+	return func(event *eth2apiv1.Event) {
+		if event.Data == nil {
+			return
+		}
+
+		now := time.Now()
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		if !rc.lastHead.IsZero() && now.Sub(rc.lastHead) > headGap {
+			logger.Info("head events resumed after a gap, resubmitting proposal preparations",
+				zap.Duration("gap", now.Sub(rc.lastHead)))
+			rc.submitAll = true
+		}
+		rc.lastHead = now
+	}
+}
+
+// listenToTicker submits the pending proposal preparations on every slot,
+// and requests submitting all of them halfway through every refreshEpochs epochs.
 func (rc *recipientController) listenToTicker(logger *zap.Logger) {
-	firstTimeSubmitted := false
 	ticker := rc.slotTickerProvider()
+	refreshSlots := rc.network.SlotsPerEpoch() * refreshEpochs
 	for {
-		<-ticker.Next()
-		slot := ticker.Slot()
-		// submit if first time or if first slot in epoch
-		if firstTimeSubmitted && uint64(slot)%rc.network.SlotsPerEpoch() != (rc.network.SlotsPerEpoch()/2) {
-			continue
-		}
-		firstTimeSubmitted = true
+		select {
+		case <-rc.ctx.Done():
+			return
+		case <-ticker.Next():
+			slot := ticker.Slot()
+			if uint64(slot)%refreshSlots == rc.network.SlotsPerEpoch()/2 {
+				rc.requestSubmitAll()
+			}
 
-		err := rc.prepareAndSubmit(logger, slot)
-		if err != nil {
-			logger.Warn("could not submit proposal preparations", zap.Error(err))
+			if err := rc.prepareAndSubmit(logger, slot); err != nil {
+				logger.Warn("could not submit proposal preparations", zap.Error(err))
+			}
 		}
 	}
 }
 
+// prepareAndSubmit submits the pending proposal preparations in batches.
+// The shares of failed batches remain pending, and are retried on the next slot.
 func (rc *recipientController) prepareAndSubmit(logger *zap.Logger, slot phase0.Slot) error {
-	shares := rc.shareStorage.List(nil, storage.ByOperatorID(rc.operatorData.ID), storage.ByActiveValidator())
+	shares := rc.pendingShares()
+	if len(shares) == 0 {
+		return nil
+	}
 
-	const batchSize = 500
 	var submitted int
-	for start := 0; start < len(shares); start += batchSize {
-		end := start + batchSize
+	for start := 0; start < len(shares); start += preparationBatchSize {
+		end := start + preparationBatchSize
 		if end > len(shares) {
 			end = len(shares)
 		}
@@ -103,18 +192,70 @@ func (rc *recipientController) prepareAndSubmit(logger *zap.Logger, slot phase0.
 				zap.Int("start_index", start),
 				zap.Error(err),
 			)
+			rc.addPendingShares(batch)
 			continue
 		}
 		submitted += count
 	}
 
 	logger.Debug("✅  successfully submitted proposal preparations",
+		fields.Slot(slot),
 		zap.Int("submitted", submitted),
 		zap.Int("total", len(shares)),
 	)
 	return nil
 }
 
+// pendingShares returns the active shares of the operator with pending proposal preparations, and clears them.
+// Added validators whose indices aren't known yet remain pending.
+func (rc *recipientController) pendingShares() []*types.SSVShare {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	var shares []*types.SSVShare
+	included := make(map[string]struct{})
+	if rc.submitAll || len(rc.pendingOwners) > 0 {
+		submitAll := rc.submitAll
+		for _, share := range rc.shareStorage.List(nil, storage.ByOperatorID(rc.operatorData.ID), storage.ByActiveValidator()) {
+			if _, ok := rc.pendingOwners[share.OwnerAddress]; submitAll || ok {
+				shares = append(shares, share)
+				included[string(share.ValidatorPubKey)] = struct{}{}
+			}
+		}
+		rc.submitAll = false
+		rc.pendingOwners = make(map[common.Address]struct{})
+	}
+
+	for pubKey := range rc.pendingPubKeys {
+		if _, ok := included[pubKey]; ok {
+			delete(rc.pendingPubKeys, pubKey)
+			continue
+		}
+		share := rc.shareStorage.Get(nil, []byte(pubKey))
+		if share == nil || !share.BelongsToOperator(rc.operatorData.ID) {
+			delete(rc.pendingPubKeys, pubKey)
+			continue
+		}
+		if !share.HasBeaconMetadata() {
+			continue
+		}
+		shares = append(shares, share)
+		delete(rc.pendingPubKeys, pubKey)
+	}
+
+	return shares
+}
+
+// addPendingShares marks the proposal preparations of the given shares as pending again.
+func (rc *recipientController) addPendingShares(shares []*types.SSVShare) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for _, share := range shares {
+		rc.pendingPubKeys[string(share.ValidatorPubKey)] = struct{}{}
+	}
+}
+
 func (rc *recipientController) submit(logger *zap.Logger, shares []*types.SSVShare) (int, error) {
 	m, err := rc.toProposalPreparation(shares)
 	if err != nil {
@@ -151,6 +292,11 @@ func (rc *recipientController) toProposalPreparation(shares []*types.SSVShare) (
 		if !found {
 			copy(feeRecipient[:], share.OwnerAddress.Bytes())
 		}
+		if rc.proposerConfig != nil {
+			if override, ok := rc.proposerConfig.FeeRecipient(share.ValidatorPubKey); ok {
+				feeRecipient = override
+			}
+		}
 		m[share.BeaconMetadata.Index] = feeRecipient
 	}
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	spectypes "github.com/bloxapp/ssv-spec/types"
@@ -41,20 +42,29 @@ func TestSubmitProposal(t *testing.T) {
 	network := networkconfig.TestNetwork
 	populateStorage(t, logger, shareStorage, operatorData)
 
-	frCtrl := NewController(&ControllerOptions{
-		Ctx:              context.TODO(),
-		Network:          network,
-		ShareStorage:     shareStorage,
-		RecipientStorage: recipientStorage,
-		OperatorData:     operatorData,
-	})
+	newController := func(t *testing.T, client beacon.BeaconNode, ticker slotticker.SlotTicker) *recipientController {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		return NewController(&ControllerOptions{
+			Ctx:              ctx,
+			BeaconClient:     client,
+			Network:          network,
+			ShareStorage:     shareStorage,
+			RecipientStorage: recipientStorage,
+			OperatorData:     operatorData,
+			SlotTickerProvider: func() slotticker.SlotTicker {
+				return ticker
+			},
+		})
+	}
 
-	t.Run("submit first time or halfway through epoch", func(t *testing.T) {
+	t.Run("submit first time or refresh halfway through epoch", func(t *testing.T) {
 		numberOfRequests := 4
 		var wg sync.WaitGroup
 		wg.Add(numberOfRequests) // Set up the wait group before starting goroutines
 
 		client := beacon.NewMockBeaconNode(ctrl)
+		client.EXPECT().Events(gomock.Any(), []string{"head"}, gomock.Any()).Return(nil)
 		client.EXPECT().SubmitProposalPreparation(gomock.Any()).DoAndReturn(func(feeRecipients map[phase0.ValidatorIndex]bellatrix.ExecutionAddress) error {
 			wg.Done()
 			return nil
@@ -68,11 +78,7 @@ func TestSubmitProposal(t *testing.T) {
 			return <-mockSlotChan
 		}).AnyTimes()
 
-		frCtrl.beaconClient = client
-		frCtrl.slotTickerProvider = func() slotticker.SlotTicker {
-			return ticker
-		}
-
+		frCtrl := newController(t, client, ticker)
 		go frCtrl.Start(logger)
 
 		slots := []phase0.Slot{
@@ -81,6 +87,7 @@ func TestSubmitProposal(t *testing.T) {
 			20,                                       // should not call submit
 			phase0.Slot(network.SlotsPerEpoch()) / 2, // halfway through epoch
 			63,                                       // should not call submit
+			phase0.Slot(network.SlotsPerEpoch()) + phase0.Slot(network.SlotsPerEpoch())/2, // not a refresh epoch
 		}
 
 		for _, s := range slots {
@@ -90,14 +97,92 @@ func TestSubmitProposal(t *testing.T) {
 		}
 
 		wg.Wait()
+	})
+
+	t.Run("submit on events", func(t *testing.T) {
+		submissions := make(chan map[phase0.ValidatorIndex]bellatrix.ExecutionAddress, 10)
+		client := beacon.NewMockBeaconNode(ctrl)
+		client.EXPECT().Events(gomock.Any(), []string{"head"}, gomock.Any()).Return(nil)
+		client.EXPECT().SubmitProposalPreparation(gomock.Any()).DoAndReturn(func(feeRecipients map[phase0.ValidatorIndex]bellatrix.ExecutionAddress) error {
+			submissions <- feeRecipients
+			return nil
+		}).AnyTimes()
+
+		ticker := mocks.NewMockSlotTicker(ctrl)
+		mockTimeChan := make(chan time.Time)
+		mockSlotChan := make(chan phase0.Slot)
+		ticker.EXPECT().Next().Return(mockTimeChan).AnyTimes()
+		ticker.EXPECT().Slot().DoAndReturn(func() phase0.Slot {
+			return <-mockSlotChan
+		}).AnyTimes()
+
+		frCtrl := newController(t, client, ticker)
+		go frCtrl.Start(logger)
+
+		tick := func(slot phase0.Slot) {
+			mockTimeChan <- time.Now()
+			mockSlotChan <- slot
+		}
+		expectSubmitted := func(count int) {
+			var submitted int
+			for submitted < count {
+				select {
+				case m := <-submissions:
+					submitted += len(m)
+				case <-time.After(5 * time.Second):
+					t.Fatalf("submitted %d proposal preparations, expected %d", submitted, count)
+				}
+			}
+			require.Equal(t, count, submitted)
+		}
+		expectNoSubmission := func() {
+			time.Sleep(time.Millisecond * 500)
+			require.Empty(t, submissions)
+		}
 
-		close(mockTimeChan) // Close the channel after test
-		close(mockSlotChan)
+		tick(1)
+		expectSubmitted(1000)
+
+		// Only the validators of the owner are submitted.
+		frCtrl.FeeRecipientUpdated(shareOwner(5))
+		tick(2)
+		expectSubmitted(1)
+
+		// Added validators are submitted once they have metadata.
+		share := createShare(3000, operatorData.ID)
+		share.BeaconMetadata = nil
+		require.NoError(t, shareStorage.Save(nil, share))
+		frCtrl.ValidatorsAdded(share.ValidatorPubKey, createShare(2000, 1).ValidatorPubKey)
+		tick(3)
+		expectNoSubmission()
+
+		require.NoError(t, shareStorage.Save(nil, createShare(3000, operatorData.ID)))
+		tick(4)
+		expectSubmitted(1)
+		tick(5)
+		expectNoSubmission()
+
+		// All validators are submitted when head events resume after a gap.
+		handleHeadEvent := frCtrl.handleHeadEvent(logger)
+		headEvent := &eth2apiv1.Event{Topic: "head", Data: &eth2apiv1.HeadEvent{Slot: 6}}
+		handleHeadEvent(headEvent)
+		tick(6)
+		expectNoSubmission()
+
+		frCtrl.mu.Lock()
+		frCtrl.lastHead = time.Now().Add(-network.SlotDurationSec() * (headGapSlots + 1))
+		frCtrl.mu.Unlock()
+		handleHeadEvent(headEvent)
+		tick(7)
+		expectSubmitted(1001)
+
+		require.NoError(t, shareStorage.Delete(nil, share.ValidatorPubKey))
 	})
 
 	t.Run("error handling", func(t *testing.T) {
 		var wg sync.WaitGroup
 		client := beacon.NewMockBeaconNode(ctrl)
+		client.EXPECT().Events(gomock.Any(), []string{"head"}, gomock.Any()).Return(errors.New("failed to subscribe"))
 		client.EXPECT().SubmitProposalPreparation(gomock.Any()).DoAndReturn(func(feeRecipients map[phase0.ValidatorIndex]bellatrix.ExecutionAddress) error {
 			wg.Done()
 			return errors.New("failed to submit")
@@ -108,16 +193,70 @@ func TestSubmitProposal(t *testing.T) {
 		ticker.EXPECT().Next().Return(mockTimeChan).AnyTimes()
 		ticker.EXPECT().Slot().Return(phase0.Slot(100)).AnyTimes()
 
-		frCtrl.beaconClient = client
-		frCtrl.slotTickerProvider = func() slotticker.SlotTicker {
-			return ticker
-		}
-
+		frCtrl := newController(t, client, ticker)
+		wg.Add(2)
 		go frCtrl.Start(logger)
 		mockTimeChan <- time.Now()
-		wg.Add(2)
 		wg.Wait()
-		close(mockTimeChan)
+	})
+
+	t.Run("retry failed submissions", func(t *testing.T) {
+		submissions := make(chan map[phase0.ValidatorIndex]bellatrix.ExecutionAddress, 10)
+		var calls int
+		client := beacon.NewMockBeaconNode(ctrl)
+		client.EXPECT().Events(gomock.Any(), []string{"head"}, gomock.Any()).Return(nil)
+		client.EXPECT().SubmitProposalPreparation(gomock.Any()).DoAndReturn(func(feeRecipients map[phase0.ValidatorIndex]bellatrix.ExecutionAddress) error {
+			calls++
+			if calls == 1 {
+				submissions <- nil
+				return errors.New("failed to submit")
+			}
+			submissions <- feeRecipients
+			return nil
+		}).AnyTimes()
+
+		ticker := mocks.NewMockSlotTicker(ctrl)
+		mockTimeChan := make(chan time.Time)
+		mockSlotChan := make(chan phase0.Slot)
+		ticker.EXPECT().Next().Return(mockTimeChan).AnyTimes()
+		ticker.EXPECT().Slot().DoAndReturn(func() phase0.Slot {
+			return <-mockSlotChan
+		}).AnyTimes()
+
+		frCtrl := newController(t, client, ticker)
+		go frCtrl.Start(logger)
+
+		tick := func(slot phase0.Slot) {
+			mockTimeChan <- time.Now()
+			mockSlotChan <- slot
+		}
+		receive := func() map[phase0.ValidatorIndex]bellatrix.ExecutionAddress {
+			select {
+			case m := <-submissions:
+				return m
+			case <-time.After(5 * time.Second):
+				t.Fatal("proposal preparations weren't submitted")
+				return nil
+			}
+		}
+
+		// The first batch fails, and the second one succeeds.
+		tick(1)
+		require.Nil(t, receive())
+		succeeded := receive()
+		require.Len(t, succeeded, preparationBatchSize)
+
+		// Only the validators of the failed batch are submitted on the next slot.
+		tick(2)
+		retried := receive()
+		require.Len(t, retried, 1000-preparationBatchSize)
+		for index := range retried {
+			require.NotContains(t, succeeded, index)
+		}
+
+		tick(3)
+		time.Sleep(time.Millisecond * 500)
+		require.Empty(t, submissions)
 	})
 }
 
@@ -134,23 +273,6 @@ func createStorage(t *testing.T) (basedb.Database, registrystorage.Shares, regis
 }
 
 func populateStorage(t *testing.T, logger *zap.Logger, storage registrystorage.Shares, operatorData *registrystorage.OperatorData) {
-	createShare := func(index int, operatorID spectypes.OperatorID) *types.SSVShare {
-		ownerAddr := fmt.Sprintf("%d", index)
-		ownerAddrByte := [20]byte{}
-		copy(ownerAddrByte[:], ownerAddr)
-
-		return &types.SSVShare{
-			Share: spectypes.Share{ValidatorPubKey: []byte(fmt.Sprintf("pk%d", index)), OperatorID: operatorID},
-			Metadata: types.Metadata{
-				BeaconMetadata: &beacon.ValidatorMetadata{
-					Index: phase0.ValidatorIndex(index),
-				},
-				OwnerAddress: common.BytesToAddress(ownerAddrByte[:]),
-				Liquidated:   false,
-			},
-		}
-	}
-
 	for i := 0; i < 1000; i++ {
 		require.NoError(t, storage.Save(nil, createShare(i, operatorData.ID)))
 	}
@@ -161,3 +283,23 @@ func populateStorage(t *testing.T, logger *zap.Logger, storage registrystorage.S
 	all := storage.List(nil, registrystorage.ByOperatorID(operatorData.ID), registrystorage.ByNotLiquidated())
 	require.Equal(t, 1000, len(all))
 }
+
+func createShare(index int, operatorID spectypes.OperatorID) *types.SSVShare {
+	return &types.SSVShare{
+		Share: spectypes.Share{ValidatorPubKey: []byte(fmt.Sprintf("pk%d", index)), OperatorID: operatorID},
+		Metadata: types.Metadata{
+			BeaconMetadata: &beacon.ValidatorMetadata{
+				Index: phase0.ValidatorIndex(index),
+			},
+			OwnerAddress: shareOwner(index),
+			Liquidated:   false,
+		},
+	}
+}
+
+func shareOwner(index int) common.Address {
+	ownerAddr := fmt.Sprintf("%d", index)
+	ownerAddrByte := [20]byte{}
+	copy(ownerAddrByte[:], ownerAddr)
+	return common.BytesToAddress(ownerAddrByte[:])
+}
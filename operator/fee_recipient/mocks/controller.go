@@ -7,6 +7,7 @@ package mocks
 import (
 	reflect "reflect"
 
+	common "github.com/ethereum/go-ethereum/common"
 	gomock "github.com/golang/mock/gomock"
 	zap "go.uber.org/zap"
 )
@@ -34,6 +35,30 @@ func (m *MockRecipientController) EXPECT() *MockRecipientControllerMockRecorder
 	return m.recorder
 }
 
+// FeeRecipientUpdated mocks base method.
+func (m *MockRecipientController) FeeRecipientUpdated(owner common.Address) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FeeRecipientUpdated", owner)
+}
+
+// FeeRecipientUpdated indicates an expected call of FeeRecipientUpdated.
+func (mr *MockRecipientControllerMockRecorder) FeeRecipientUpdated(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeeRecipientUpdated", reflect.TypeOf((*MockRecipientController)(nil).FeeRecipientUpdated), owner)
+}
+
+// ProposerConfigReloaded mocks base method.
+func (m *MockRecipientController) ProposerConfigReloaded() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ProposerConfigReloaded")
+}
+
+// ProposerConfigReloaded indicates an expected call of ProposerConfigReloaded.
+func (mr *MockRecipientControllerMockRecorder) ProposerConfigReloaded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProposerConfigReloaded", reflect.TypeOf((*MockRecipientController)(nil).ProposerConfigReloaded))
+}
+
 // Start mocks base method.
 func (m *MockRecipientController) Start(logger *zap.Logger) {
 	m.ctrl.T.Helper()
@@ -45,3 +70,19 @@ func (mr *MockRecipientControllerMockRecorder) Start(logger interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRecipientController)(nil).Start), logger)
 }
+
+// ValidatorsAdded mocks base method.
+func (m *MockRecipientController) ValidatorsAdded(pubKeys ...[]byte) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range pubKeys {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "ValidatorsAdded", varargs...)
+}
+
+// ValidatorsAdded indicates an expected call of ValidatorsAdded.
+func (mr *MockRecipientControllerMockRecorder) ValidatorsAdded(pubKeys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatorsAdded", reflect.TypeOf((*MockRecipientController)(nil).ValidatorsAdded), pubKeys...)
+}
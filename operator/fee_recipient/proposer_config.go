@@ -0,0 +1,241 @@
+package fee_recipient
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bloxapp/ssv/logging/fields"
+)
+
+// DefaultProposerConfigWatchInterval is the default interval at which the proposer config file is checked for changes.
+const DefaultProposerConfigWatchInterval = 10 * time.Second
+
+// ProposerSettings are the proposer settings of a validator. Unset settings aren't overridden.
+type ProposerSettings struct {
+	FeeRecipient   string `yaml:"fee_recipient" json:"fee_recipient,omitempty"`
+	GasLimit       uint64 `yaml:"gas_limit" json:"gas_limit,omitempty"`
+	BuilderEnabled *bool  `yaml:"builder_enabled" json:"builder_enabled,omitempty"`
+	// BuilderMinBid is the minimum value in gwei of the block of an external builder to propose it,
+	// rather than the locally built block.
+	BuilderMinBid uint64 `yaml:"builder_min_bid" json:"builder_min_bid,omitempty"`
+}
+
+// ProposerConfigFile is the format of the proposer config file, in YAML or JSON:
+//
+//	default_config:
+//	  gas_limit: 30000000
+//	proposer_config:
+//	  0xa1b2...:
+//	    fee_recipient: 0x1234...
+//	    builder_enabled: true
+//	    builder_min_bid: 50000000
+//
+// The default config applies to all the validators of the operator,
+// and the proposer config of a validator overrides it.
+type ProposerConfigFile struct {
+	Default   ProposerSettings            `yaml:"default_config" json:"default_config"`
+	Proposers map[string]ProposerSettings `yaml:"proposer_config" json:"proposer_config"`
+}
+
+type proposerSettings struct {
+	feeRecipient   *bellatrix.ExecutionAddress
+	gasLimit       uint64
+	builderEnabled *bool
+	builderMinBid  uint64
+}
+
+// ProposerConfig is the local proposer config of the validators, loaded from a file.
+// Its methods are safe to call on a nil ProposerConfig, which overrides nothing.
+type ProposerConfig struct {
+	path string
+
+	mu        sync.RWMutex
+	defaults  proposerSettings
+	proposers map[phase0.BLSPubKey]proposerSettings
+}
+
+// LoadProposerConfig loads the proposer config from the file at the given path.
+func LoadProposerConfig(path string) (*ProposerConfig, error) {
+	c := &ProposerConfig{path: path}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reloads the proposer config from its file. The current config is kept if it fails.
+func (c *ProposerConfig) Reload() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return errors.Wrap(err, "could not read proposer config")
+	}
+	var file ProposerConfigFile
+	// YAML is a superset of JSON, so this decodes both.
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return errors.Wrap(err, "could not decode proposer config")
+	}
+
+	defaults, err := file.Default.parse()
+	if err != nil {
+		return errors.Wrap(err, "invalid default config")
+	}
+	proposers := make(map[phase0.BLSPubKey]proposerSettings, len(file.Proposers))
+	for pubKeyHex, settings := range file.Proposers {
+		pubKey, err := parsePubKey(pubKeyHex)
+		if err != nil {
+			return err
+		}
+		parsed, err := settings.parse()
+		if err != nil {
+			return errors.Wrapf(err, "invalid proposer config of %s", pubKeyHex)
+		}
+		proposers[pubKey] = parsed
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaults = defaults
+	c.proposers = proposers
+	return nil
+}
+
+// Watch reloads the proposer config whenever its file changes, checking it every interval until the context is done,
+// and calls onReload after each reload. A file which fails to load, e.g. while it's being written, is retried at the next interval.
+func (c *ProposerConfig) Watch(ctx context.Context, logger *zap.Logger, interval time.Duration, onReload func()) {
+	var modTime time.Time
+	var size int64
+	if info, err := os.Stat(c.path); err == nil {
+		modTime, size = info.ModTime(), info.Size()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(c.path)
+		if err != nil {
+			logger.Warn("could not stat proposer config file", zap.Error(err))
+			continue
+		}
+		if info.ModTime().Equal(modTime) && info.Size() == size {
+			continue
+		}
+		if err := c.Reload(); err != nil {
+			logger.Warn("could not reload proposer config", zap.Error(err))
+			continue
+		}
+		modTime, size = info.ModTime(), info.Size()
+		logger.Info("reloaded proposer config", fields.Count(c.count()))
+		if onReload != nil {
+			onReload()
+		}
+	}
+}
+
+// FeeRecipient returns the fee recipient of the validator, if it's overridden.
+func (c *ProposerConfig) FeeRecipient(pubKey []byte) (bellatrix.ExecutionAddress, bool) {
+	settings := c.settings(pubKey)
+	if settings.feeRecipient == nil {
+		return bellatrix.ExecutionAddress{}, false
+	}
+	return *settings.feeRecipient, true
+}
+
+// GasLimit returns the gas limit of the validator, if it's overridden.
+func (c *ProposerConfig) GasLimit(pubKey []byte) (uint64, bool) {
+	settings := c.settings(pubKey)
+	return settings.gasLimit, settings.gasLimit != 0
+}
+
+// BuilderEnabled returns whether the validator proposes blocks from external builders, if it's overridden.
+func (c *ProposerConfig) BuilderEnabled(pubKey []byte) (bool, bool) {
+	settings := c.settings(pubKey)
+	if settings.builderEnabled == nil {
+		return false, false
+	}
+	return *settings.builderEnabled, true
+}
+
+// BuilderMinBid returns the minimum value in gwei of the block of an external builder for the validator to propose it,
+// if it's set.
+func (c *ProposerConfig) BuilderMinBid(pubKey []byte) (uint64, bool) {
+	settings := c.settings(pubKey)
+	return settings.builderMinBid, settings.builderMinBid != 0
+}
+
+// settings returns the settings of the validator merged over the default settings.
+func (c *ProposerConfig) settings(pubKey []byte) proposerSettings {
+	if c == nil {
+		return proposerSettings{}
+	}
+	var pk phase0.BLSPubKey
+	copy(pk[:], pubKey)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	settings := c.defaults
+	if proposer, ok := c.proposers[pk]; ok {
+		if proposer.feeRecipient != nil {
+			settings.feeRecipient = proposer.feeRecipient
+		}
+		if proposer.gasLimit != 0 {
+			settings.gasLimit = proposer.gasLimit
+		}
+		if proposer.builderEnabled != nil {
+			settings.builderEnabled = proposer.builderEnabled
+		}
+		if proposer.builderMinBid != 0 {
+			settings.builderMinBid = proposer.builderMinBid
+		}
+	}
+	return settings
+}
+
+func (c *ProposerConfig) count() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.proposers)
+}
+
+func (s ProposerSettings) parse() (proposerSettings, error) {
+	parsed := proposerSettings{
+		gasLimit:       s.GasLimit,
+		builderEnabled: s.BuilderEnabled,
+		builderMinBid:  s.BuilderMinBid,
+	}
+	if s.FeeRecipient != "" {
+		if !common.IsHexAddress(s.FeeRecipient) {
+			return proposerSettings{}, errors.Errorf("invalid fee recipient %q", s.FeeRecipient)
+		}
+		var feeRecipient bellatrix.ExecutionAddress
+		copy(feeRecipient[:], common.HexToAddress(s.FeeRecipient).Bytes())
+		parsed.feeRecipient = &feeRecipient
+	}
+	return parsed, nil
+}
+
+func parsePubKey(s string) (phase0.BLSPubKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(b) != len(phase0.BLSPubKey{}) {
+		return phase0.BLSPubKey{}, errors.Errorf("invalid validator public key %q", s)
+	}
+	var pubKey phase0.BLSPubKey
+	copy(pubKey[:], b)
+	return pubKey, nil
+}
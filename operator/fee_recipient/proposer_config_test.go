@@ -0,0 +1,244 @@
+package fee_recipient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+const (
+	testPubKey1 = "0xa1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+	testPubKey2 = "b1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+	testPubKey3 = "0xc1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+)
+
+func TestProposerConfig(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		path := writeProposerConfig(t, "proposers.yaml", `
+default_config:
+  gas_limit: 30000000
+proposer_config:
+  `+testPubKey1+`:
+    fee_recipient: 0x1111111111111111111111111111111111111111
+    builder_enabled: true
+  `+testPubKey2+`:
+    gas_limit: 36000000
+    builder_enabled: false
+    builder_min_bid: 50000000
+`)
+		c, err := LoadProposerConfig(path)
+		require.NoError(t, err)
+
+		feeRecipient, ok := c.FeeRecipient(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.Equal(t, executionAddress("0x1111111111111111111111111111111111111111"), feeRecipient)
+		gasLimit, ok := c.GasLimit(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.EqualValues(t, 30000000, gasLimit)
+		enabled, ok := c.BuilderEnabled(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.True(t, enabled)
+
+		_, ok = c.FeeRecipient(pubKey(t, testPubKey2))
+		require.False(t, ok)
+		gasLimit, ok = c.GasLimit(pubKey(t, testPubKey2))
+		require.True(t, ok)
+		require.EqualValues(t, 36000000, gasLimit)
+		enabled, ok = c.BuilderEnabled(pubKey(t, testPubKey2))
+		require.True(t, ok)
+		require.False(t, enabled)
+		minBid, ok := c.BuilderMinBid(pubKey(t, testPubKey2))
+		require.True(t, ok)
+		require.EqualValues(t, 50000000, minBid)
+		_, ok = c.BuilderMinBid(pubKey(t, testPubKey1))
+		require.False(t, ok)
+
+		// Validators which aren't in the config get the default config.
+		_, ok = c.FeeRecipient(pubKey(t, testPubKey3))
+		require.False(t, ok)
+		gasLimit, ok = c.GasLimit(pubKey(t, testPubKey3))
+		require.True(t, ok)
+		require.EqualValues(t, 30000000, gasLimit)
+		_, ok = c.BuilderEnabled(pubKey(t, testPubKey3))
+		require.False(t, ok)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := writeProposerConfig(t, "proposers.json", `{
+  "default_config": {"fee_recipient": "0x2222222222222222222222222222222222222222", "builder_enabled": true},
+  "proposer_config": {"`+testPubKey1+`": {"fee_recipient": "0x1111111111111111111111111111111111111111"}}
+}`)
+		c, err := LoadProposerConfig(path)
+		require.NoError(t, err)
+
+		feeRecipient, ok := c.FeeRecipient(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.Equal(t, executionAddress("0x1111111111111111111111111111111111111111"), feeRecipient)
+		feeRecipient, ok = c.FeeRecipient(pubKey(t, testPubKey3))
+		require.True(t, ok)
+		require.Equal(t, executionAddress("0x2222222222222222222222222222222222222222"), feeRecipient)
+		enabled, ok := c.BuilderEnabled(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.True(t, enabled)
+		_, ok = c.GasLimit(pubKey(t, testPubKey1))
+		require.False(t, ok)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		path := writeProposerConfig(t, "proposers.yaml", `
+proposer_config:
+  `+testPubKey1+`:
+    gas_limit: 30000000
+`)
+		c, err := LoadProposerConfig(path)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(path, []byte(`
+proposer_config:
+  `+testPubKey1+`:
+    gas_limit: 36000000
+`), 0600))
+		require.NoError(t, c.Reload())
+		gasLimit, ok := c.GasLimit(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.EqualValues(t, 36000000, gasLimit)
+
+		// An invalid config keeps the current one.
+		require.NoError(t, os.WriteFile(path, []byte(`
+proposer_config:
+  `+testPubKey1+`:
+    fee_recipient: 0x1234
+`), 0600))
+		require.ErrorContains(t, c.Reload(), "invalid fee recipient")
+		gasLimit, ok = c.GasLimit(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.EqualValues(t, 36000000, gasLimit)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		path := writeProposerConfig(t, "proposers.yaml", `
+proposer_config:
+  `+testPubKey1+`:
+    gas_limit: 30000000
+`)
+		c, err := LoadProposerConfig(path)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		reloaded := make(chan struct{}, 1)
+		go c.Watch(ctx, zap.NewNop(), 10*time.Millisecond, func() { reloaded <- struct{}{} })
+
+		// The file is rewritten until the change is detected, since Watch may only start watching after the first write.
+		content := `
+proposer_config:
+  ` + testPubKey1 + `:
+    gas_limit: 36000000
+`
+		require.Eventually(t, func() bool {
+			content += "\n"
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+			select {
+			case <-reloaded:
+				return true
+			case <-time.After(50 * time.Millisecond):
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+		gasLimit, ok := c.GasLimit(pubKey(t, testPubKey1))
+		require.True(t, ok)
+		require.EqualValues(t, 36000000, gasLimit)
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		path := writeProposerConfig(t, "proposers.yaml", `
+proposer_config:
+  0x1234:
+    gas_limit: 30000000
+`)
+		_, err := LoadProposerConfig(path)
+		require.ErrorContains(t, err, "invalid validator public key")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var c *ProposerConfig
+		_, ok := c.FeeRecipient(pubKey(t, testPubKey1))
+		require.False(t, ok)
+		_, ok = c.GasLimit(pubKey(t, testPubKey1))
+		require.False(t, ok)
+		_, ok = c.BuilderEnabled(pubKey(t, testPubKey1))
+		require.False(t, ok)
+	})
+}
+
+func TestProposalPreparationOverride(t *testing.T) {
+	db, shareStorage, recipientStorage := createStorage(t)
+	defer db.Close()
+
+	path := writeProposerConfig(t, "proposers.yaml", `
+proposer_config:
+  `+testPubKey1+`:
+    fee_recipient: 0x1111111111111111111111111111111111111111
+`)
+	proposerConfig, err := LoadProposerConfig(path)
+	require.NoError(t, err)
+
+	frCtrl := NewController(&ControllerOptions{
+		ShareStorage:     shareStorage,
+		RecipientStorage: recipientStorage,
+		ProposerConfig:   proposerConfig,
+	})
+
+	owner := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	shares := []*types.SSVShare{
+		{
+			Share:    spectypes.Share{ValidatorPubKey: pubKey(t, testPubKey1)},
+			Metadata: types.Metadata{BeaconMetadata: &beacon.ValidatorMetadata{Index: 1}, OwnerAddress: owner},
+		},
+		{
+			Share:    spectypes.Share{ValidatorPubKey: pubKey(t, testPubKey2)},
+			Metadata: types.Metadata{BeaconMetadata: &beacon.ValidatorMetadata{Index: 2}, OwnerAddress: owner},
+		},
+	}
+
+	m, err := frCtrl.toProposalPreparation(shares)
+	require.NoError(t, err)
+	require.Equal(t, map[phase0.ValidatorIndex]bellatrix.ExecutionAddress{
+		1: executionAddress("0x1111111111111111111111111111111111111111"),
+		2: executionAddress(owner.Hex()),
+	}, m)
+
+	// Reloading the proposer config resubmits all the proposal preparations.
+	frCtrl.ProposerConfigReloaded()
+	require.True(t, frCtrl.submitAll)
+}
+
+func writeProposerConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func pubKey(t *testing.T, s string) []byte {
+	pk, err := parsePubKey(s)
+	require.NoError(t, err)
+	return pk[:]
+}
+
+func executionAddress(s string) bellatrix.ExecutionAddress {
+	var addr bellatrix.ExecutionAddress
+	copy(addr[:], common.HexToAddress(s).Bytes())
+	return addr
+}
@@ -0,0 +1,87 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+// OperatorSigner signs with the operator private key.
+type OperatorSigner interface {
+	// Public returns the operator public key.
+	Public() *rsa.PublicKey
+	// Sign signs the SHA-256 hash of data with RSASSA-PKCS1-v1_5.
+	Sign(data []byte) ([]byte, error)
+}
+
+// OperatorPrivateKey is the operator private key, which may be held in memory or by an HSM.
+type OperatorPrivateKey interface {
+	OperatorSigner
+	// Decrypt decrypts data encrypted with RSAES-PKCS1-v1_5 to the operator public key, such as share private keys.
+	Decrypt(data []byte) ([]byte, error)
+	// StorageHash returns the hash the node storage identifies the key by.
+	StorageHash() (string, error)
+	// EncryptionKey returns the key the key manager storage and the database backups are encrypted with.
+	EncryptionKey() (string, error)
+}
+
+// Base64PublicKey returns the operator public key as base64 encoded PEM, as registered in the SSV contract.
+func Base64PublicKey(signer OperatorSigner) (string, error) {
+	return rsaencryption.EncodePublicKey(signer.Public())
+}
+
+type privateKey struct {
+	sk  *rsa.PrivateKey
+	pem []byte
+}
+
+// PrivateKeyFromBase64 returns the operator private key of the given base64 encoded PEM, like OperatorPrivateKey in the config.
+func PrivateKeyFromBase64(operatorKeyBase64 string) (OperatorPrivateKey, error) {
+	pemBytes, err := base64.StdEncoding.DecodeString(operatorKeyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64: %w", err)
+	}
+	return PrivateKeyFromPEM(pemBytes)
+}
+
+// PrivateKeyFromPEM returns the operator private key of the given PEM.
+func PrivateKeyFromPEM(pemBytes []byte) (OperatorPrivateKey, error) {
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(pemBytes))
+	if err != nil {
+		return nil, err
+	}
+	return &privateKey{sk: sk, pem: pemBytes}, nil
+}
+
+// PrivateKeyFromRSA returns the operator private key of the given RSA private key.
+func PrivateKeyFromRSA(sk *rsa.PrivateKey) OperatorPrivateKey {
+	return &privateKey{sk: sk, pem: rsaencryption.PrivateKeyToByte(sk)}
+}
+
+func (k *privateKey) Public() *rsa.PublicKey {
+	return &k.sk.PublicKey
+}
+
+func (k *privateKey) Sign(data []byte) ([]byte, error) {
+	hash := sha256.Sum256(data)
+	return rsa.SignPKCS1v15(nil, k.sk, crypto.SHA256, hash[:])
+}
+
+func (k *privateKey) Decrypt(data []byte) ([]byte, error) {
+	return rsaencryption.DecodeKey(k.sk, data)
+}
+
+// StorageHash returns the hash of the PEM, as the node storage always identified the key by.
+func (k *privateKey) StorageHash() (string, error) {
+	return rsaencryption.HashRsaKey(k.pem)
+}
+
+// EncryptionKey returns the hash of the PKCS #1 encoding of the key, as the key manager storage was always encrypted with.
+func (k *privateKey) EncryptionKey() (string, error) {
+	return rsaencryption.HashRsaKey(x509.MarshalPKCS1PrivateKey(k.sk))
+}
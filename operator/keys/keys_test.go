@@ -0,0 +1,69 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	testingspace "github.com/bloxapp/ssv/utils/rsaencryption/testingspace"
+)
+
+func TestPrivateKey(t *testing.T) {
+	sk, err := rsaencryption.ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+	operatorKey, err := PrivateKeyFromBase64(base64.StdEncoding.EncodeToString([]byte(testingspace.SkPem)))
+	require.NoError(t, err)
+	require.Equal(t, &sk.PublicKey, operatorKey.Public())
+
+	t.Run("sign", func(t *testing.T) {
+		data := []byte("data")
+		signature, err := operatorKey.Sign(data)
+		require.NoError(t, err)
+		hash := sha256.Sum256(data)
+		require.NoError(t, rsa.VerifyPKCS1v15(&sk.PublicKey, crypto.SHA256, hash[:], signature))
+	})
+
+	t.Run("decrypt", func(t *testing.T) {
+		encrypted, err := base64.StdEncoding.DecodeString(testingspace.EncryptedKeyBase64)
+		require.NoError(t, err)
+		decrypted, err := operatorKey.Decrypt(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "626d6a13ae5b1458c310700941764f3841f279f9c8de5f4ba94abd01dc082517", string(decrypted))
+	})
+
+	t.Run("hashes", func(t *testing.T) {
+		// The hashes must remain those existing storages were set up with.
+		storageHash, err := operatorKey.StorageHash()
+		require.NoError(t, err)
+		expected, err := rsaencryption.HashRsaKey([]byte(testingspace.SkPem))
+		require.NoError(t, err)
+		require.Equal(t, expected, storageHash)
+
+		encryptionKey, err := operatorKey.EncryptionKey()
+		require.NoError(t, err)
+		expected, err = rsaencryption.HashRsaKey(x509.MarshalPKCS1PrivateKey(sk))
+		require.NoError(t, err)
+		require.Equal(t, expected, encryptionKey)
+	})
+
+	t.Run("public key", func(t *testing.T) {
+		publicKey, err := Base64PublicKey(operatorKey)
+		require.NoError(t, err)
+		expected, err := rsaencryption.ExtractPublicKey(sk)
+		require.NoError(t, err)
+		require.Equal(t, expected, publicKey)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := PrivateKeyFromBase64("xxx")
+		require.Error(t, err)
+		_, err = PrivateKeyFromPEM([]byte("xxx"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,395 @@
+package keys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+// Messages whose signatures derive the hashes of a PKCS#11 key, since the key itself can't be read.
+// RSASSA-PKCS1-v1_5 signatures are deterministic, so the hashes are stable for a given key.
+const (
+	storageHashMessage   = "ssv/operator-key/storage-hash"
+	encryptionKeyMessage = "ssv/operator-key/encryption-key"
+)
+
+// PKCS11Config locates the operator private key on a PKCS#11 token, such as an HSM.
+// The key must be an RSA private key allowed to sign and decrypt.
+type PKCS11Config struct {
+	Module     string `yaml:"Module" env:"PKCS11_MODULE" env-description:"Path to the PKCS#11 module of the token holding the operator private key, which is used instead of the key store if set"`
+	TokenLabel string `yaml:"TokenLabel" env:"PKCS11_TOKEN_LABEL" env-description:"Label of the PKCS#11 token holding the operator private key"`
+	PINFile    string `yaml:"PINFile" env:"PKCS11_PIN_FILE" env-description:"Path to the file of the user PIN of the PKCS#11 token"`
+	KeyLabel   string `yaml:"KeyLabel" env:"PKCS11_KEY_LABEL" env-description:"Label of the operator private key on the PKCS#11 token"`
+}
+
+// pkcs11Sessions is the number of sessions on the token, which bounds the number of concurrent operations with the key,
+// since PKCS#11 doesn't allow concurrent operations on a session.
+const pkcs11Sessions = 4
+
+// PKCS11Key is an operator private key held by a PKCS#11 token, which never leaves it.
+// Its operations run on a pool of sessions, which are opened again if the token resets,
+// e.g. when an HSM restarts or fails over, invalidating them.
+type PKCS11Key struct {
+	ctx    *pkcs11.Ctx
+	cfg    PKCS11Config
+	pin    string
+	public *rsa.PublicKey
+
+	// sessions bounds the number of sessions in use.
+	sessions chan struct{}
+
+	// mu guards the idle sessions and the handles of the token, which change when the sessions are opened again.
+	mu         sync.Mutex
+	slot       uint
+	object     pkcs11.ObjectHandle
+	idle       []pkcs11.SessionHandle
+	generation uint64
+	closed     bool
+
+	storageHash   string
+	encryptionKey string
+}
+
+// pkcs11Session is a session in use, with the handle of the key as of when it was taken.
+type pkcs11Session struct {
+	handle     pkcs11.SessionHandle
+	object     pkcs11.ObjectHandle
+	generation uint64
+}
+
+// OpenPKCS11 logs into the token of the given config and finds the operator private key on it.
+// The returned key must be closed once no longer used.
+func OpenPKCS11(cfg PKCS11Config) (*PKCS11Key, error) {
+	pin, err := os.ReadFile(cfg.PINFile)
+	if err != nil {
+		return nil, fmt.Errorf("read PIN file: %w", err)
+	}
+
+	ctx := pkcs11.New(cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("could not load PKCS#11 module %s", cfg.Module)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("initialize PKCS#11 module: %w", err)
+	}
+	k := &PKCS11Key{
+		ctx:      ctx,
+		cfg:      cfg,
+		pin:      strings.TrimSpace(string(pin)),
+		sessions: make(chan struct{}, pkcs11Sessions),
+	}
+	if err := k.open(); err != nil {
+		k.Close()
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *PKCS11Key) open() error {
+	k.mu.Lock()
+	err := k.login()
+	k.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	public, err := k.publicKey()
+	if err != nil {
+		return err
+	}
+	k.public = public
+
+	if k.storageHash, err = k.derivedHash(storageHashMessage); err != nil {
+		return err
+	}
+	if k.encryptionKey, err = k.derivedHash(encryptionKeyMessage); err != nil {
+		return err
+	}
+	return nil
+}
+
+// login opens a session on the token, logs into it and finds the key on it, making it the only idle session.
+// The sessions of the application share their login, so the sessions opened later are logged in as well.
+// It must be called with mu held.
+func (k *PKCS11Key) login() error {
+	slot, err := findSlot(k.ctx, k.cfg.TokenLabel)
+	if err != nil {
+		return err
+	}
+	session, err := k.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("open session: %w", err)
+	}
+	if err := k.ctx.Login(session, pkcs11.CKU_USER, k.pin); err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		_ = k.ctx.CloseSession(session)
+		return fmt.Errorf("login: %w", err)
+	}
+	object, err := findPrivateKey(k.ctx, session, k.cfg.KeyLabel)
+	if err != nil {
+		_ = k.ctx.CloseSession(session)
+		return err
+	}
+	k.slot = slot
+	k.object = object
+	k.idle = []pkcs11.SessionHandle{session}
+	k.generation++
+	return nil
+}
+
+// relogin closes all the sessions on the token and logs into it again, unless another operation already did
+// since the given session was taken.
+func (k *PKCS11Key) relogin(session pkcs11Session) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.closed {
+		return errors.New("PKCS#11 key is closed")
+	}
+	if session.generation != k.generation {
+		return nil
+	}
+	// The sessions in use by other operations fail as well, and aren't returned to the pool.
+	_ = k.ctx.CloseAllSessions(k.slot)
+	k.idle = nil
+	return k.login()
+}
+
+// withSession runs the given operation on a session of the pool. If the session turns out to be invalid,
+// the sessions are opened again, and the operation is retried once.
+func (k *PKCS11Key) withSession(op func(session pkcs11.SessionHandle, object pkcs11.ObjectHandle) error) error {
+	k.sessions <- struct{}{}
+	defer func() { <-k.sessions }()
+
+	session, err := k.acquire()
+	if err != nil {
+		return err
+	}
+	err = op(session.handle, session.object)
+	k.release(session, err)
+	if !sessionLost(err) {
+		return err
+	}
+
+	if err := k.relogin(session); err != nil {
+		return fmt.Errorf("open PKCS#11 session again: %w", err)
+	}
+	session, err = k.acquire()
+	if err != nil {
+		return err
+	}
+	err = op(session.handle, session.object)
+	k.release(session, err)
+	return err
+}
+
+// acquire takes an idle session, or opens a new one.
+func (k *PKCS11Key) acquire() (pkcs11Session, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.closed {
+		return pkcs11Session{}, errors.New("PKCS#11 key is closed")
+	}
+	session := pkcs11Session{object: k.object, generation: k.generation}
+	if n := len(k.idle); n > 0 {
+		session.handle = k.idle[n-1]
+		k.idle = k.idle[:n-1]
+		return session, nil
+	}
+	handle, err := k.ctx.OpenSession(k.slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return pkcs11Session{}, fmt.Errorf("open session: %w", err)
+	}
+	session.handle = handle
+	return session, nil
+}
+
+// release returns the session to the pool, unless it's invalid or the sessions were opened again since it was taken.
+func (k *PKCS11Key) release(session pkcs11Session, err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.closed || session.generation != k.generation || sessionLost(err) {
+		_ = k.ctx.CloseSession(session.handle)
+		return
+	}
+	k.idle = append(k.idle, session.handle)
+}
+
+// sessionLost returns true if the error means that the session, the login or the key handle are no longer valid,
+// which happens when the token resets.
+func sessionLost(err error) bool {
+	var pkcs11Err pkcs11.Error
+	if !errors.As(err, &pkcs11Err) {
+		return false
+	}
+	switch pkcs11Err {
+	case pkcs11.CKR_SESSION_HANDLE_INVALID,
+		pkcs11.CKR_SESSION_CLOSED,
+		pkcs11.CKR_USER_NOT_LOGGED_IN,
+		pkcs11.CKR_KEY_HANDLE_INVALID,
+		pkcs11.CKR_OBJECT_HANDLE_INVALID,
+		pkcs11.CKR_DEVICE_REMOVED,
+		pkcs11.CKR_TOKEN_NOT_PRESENT:
+		return true
+	default:
+		return false
+	}
+}
+
+func (k *PKCS11Key) publicKey() (*rsa.PublicKey, error) {
+	var attributes []*pkcs11.Attribute
+	err := k.withSession(func(session pkcs11.SessionHandle, object pkcs11.ObjectHandle) error {
+		var err error
+		attributes, err = k.ctx.GetAttributeValue(session, object, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get public key: %w", err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(attributes[0].Value),
+		E: int(new(big.Int).SetBytes(attributes[1].Value).Int64()),
+	}, nil
+}
+
+func findSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("get slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("get token info: %w", err)
+		}
+		if info.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("token %q not found", tokenLabel)
+}
+
+func findPrivateKey(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, keyLabel string) (pkcs11.ObjectHandle, error) {
+	if err := ctx.FindObjectsInit(session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyLabel),
+	}); err != nil {
+		return 0, fmt.Errorf("find private key: %w", err)
+	}
+	objects, _, err := ctx.FindObjects(session, 2)
+	if finalErr := ctx.FindObjectsFinal(session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("find private key: %w", err)
+	}
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("RSA private key %q not found", keyLabel)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("several RSA private keys %q found", keyLabel)
+	}
+}
+
+func (k *PKCS11Key) derivedHash(message string) (string, error) {
+	signature, err := k.Sign([]byte(message))
+	if err != nil {
+		return "", err
+	}
+	return rsaencryption.HashRsaKey(signature)
+}
+
+func (k *PKCS11Key) Public() *rsa.PublicKey {
+	return k.public
+}
+
+func (k *PKCS11Key) Sign(data []byte) ([]byte, error) {
+	var signature []byte
+	err := k.withSession(func(session pkcs11.SessionHandle, object pkcs11.ObjectHandle) error {
+		mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_SHA256_RSA_PKCS, nil)}
+		if err := k.ctx.SignInit(session, mechanism, object); err != nil {
+			return fmt.Errorf("init sign: %w", err)
+		}
+		var err error
+		if signature, err = k.ctx.Sign(session, data); err != nil {
+			return fmt.Errorf("sign: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+func (k *PKCS11Key) Decrypt(data []byte) ([]byte, error) {
+	var decrypted []byte
+	err := k.withSession(func(session pkcs11.SessionHandle, object pkcs11.ObjectHandle) error {
+		mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil)}
+		if err := k.ctx.DecryptInit(session, mechanism, object); err != nil {
+			return fmt.Errorf("init decrypt: %w", err)
+		}
+		var err error
+		if decrypted, err = k.ctx.Decrypt(session, data); err != nil {
+			return fmt.Errorf("could not decrypt key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// StorageHash returns the hash of the signature of a fixed message.
+func (k *PKCS11Key) StorageHash() (string, error) {
+	return k.storageHash, nil
+}
+
+// EncryptionKey returns the hash of the signature of a fixed message, other than the one of StorageHash.
+// It differs from the one of the same key held in memory, so the storage of a node moving its key to a token
+// must be re-encrypted with rotate-operator-key.
+func (k *PKCS11Key) EncryptionKey() (string, error) {
+	return k.encryptionKey, nil
+}
+
+// Close logs out of the token and unloads the module, once the operations in progress are done.
+func (k *PKCS11Key) Close() error {
+	k.mu.Lock()
+	closed := k.closed
+	k.mu.Unlock()
+	if closed {
+		return nil
+	}
+	for i := 0; i < cap(k.sessions); i++ {
+		k.sessions <- struct{}{}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.closed = true
+
+	var errs []error
+	if len(k.idle) > 0 {
+		// Logging out fails if the login failed, which doesn't matter when closing.
+		_ = k.ctx.Logout(k.idle[0])
+		errs = append(errs, k.ctx.CloseAllSessions(k.slot))
+		k.idle = nil
+	}
+	errs = append(errs, k.ctx.Finalize())
+	k.ctx.Destroy()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,213 @@
+package keys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	testingspace "github.com/bloxapp/ssv/utils/rsaencryption/testingspace"
+)
+
+// softHSMModules are the usual paths of the SoftHSM module, which can also be given by SOFTHSM2_MODULE.
+var softHSMModules = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/local/lib/softhsm/libsofthsm2.so",
+	"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+}
+
+func TestPKCS11Key(t *testing.T) {
+	sk, err := rsaencryption.ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+	cfg := setupSoftHSM(t, sk)
+
+	operatorKey, err := OpenPKCS11(cfg)
+	require.NoError(t, err)
+	fileKey := PrivateKeyFromRSA(sk)
+	require.Equal(t, fileKey.Public(), operatorKey.Public())
+
+	t.Run("sign", func(t *testing.T) {
+		// RSASSA-PKCS1-v1_5 signatures are deterministic, so they must equal the ones of the key in memory.
+		signature, err := operatorKey.Sign([]byte("data"))
+		require.NoError(t, err)
+		expected, err := fileKey.Sign([]byte("data"))
+		require.NoError(t, err)
+		require.Equal(t, expected, signature)
+	})
+
+	t.Run("decrypt", func(t *testing.T) {
+		encrypted, err := rsaencryption.EncodeKey(operatorKey.Public(), []byte("share key"))
+		require.NoError(t, err)
+		decrypted, err := operatorKey.Decrypt(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "share key", string(decrypted))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		expected, err := fileKey.Sign([]byte("data"))
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		for i := 0; i < 4*pkcs11Sessions; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				signature, err := operatorKey.Sign([]byte("data"))
+				require.NoError(t, err)
+				require.Equal(t, expected, signature)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("session recovery", func(t *testing.T) {
+		// Closing the sessions behind the key's back invalidates them, as a token reset does.
+		require.NoError(t, operatorKey.ctx.CloseAllSessions(operatorKey.slot))
+		signature, err := operatorKey.Sign([]byte("data"))
+		require.NoError(t, err)
+		expected, err := fileKey.Sign([]byte("data"))
+		require.NoError(t, err)
+		require.Equal(t, expected, signature)
+	})
+
+	storageHash, err := operatorKey.StorageHash()
+	require.NoError(t, err)
+	encryptionKey, err := operatorKey.EncryptionKey()
+	require.NoError(t, err)
+	require.NotEqual(t, storageHash, encryptionKey)
+	require.NoError(t, operatorKey.Close())
+
+	t.Run("hashes are stable", func(t *testing.T) {
+		operatorKey, err := OpenPKCS11(cfg)
+		require.NoError(t, err)
+		defer operatorKey.Close()
+
+		reopenedStorageHash, err := operatorKey.StorageHash()
+		require.NoError(t, err)
+		require.Equal(t, storageHash, reopenedStorageHash)
+		reopenedEncryptionKey, err := operatorKey.EncryptionKey()
+		require.NoError(t, err)
+		require.Equal(t, encryptionKey, reopenedEncryptionKey)
+	})
+
+	t.Run("wrong PIN", func(t *testing.T) {
+		wrongPIN := filepath.Join(t.TempDir(), "pin")
+		require.NoError(t, os.WriteFile(wrongPIN, []byte("0000"), 0600))
+		wrongCfg := cfg
+		wrongCfg.PINFile = wrongPIN
+		_, err := OpenPKCS11(wrongCfg)
+		require.ErrorContains(t, err, "login")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		unknownCfg := cfg
+		unknownCfg.KeyLabel = "unknown"
+		_, err := OpenPKCS11(unknownCfg)
+		require.ErrorContains(t, err, "not found")
+	})
+}
+
+func TestSessionLost(t *testing.T) {
+	require.True(t, sessionLost(fmt.Errorf("sign: %w", pkcs11.Error(pkcs11.CKR_SESSION_HANDLE_INVALID))))
+	require.True(t, sessionLost(pkcs11.Error(pkcs11.CKR_DEVICE_REMOVED)))
+	require.False(t, sessionLost(pkcs11.Error(pkcs11.CKR_PIN_INCORRECT)))
+	require.False(t, sessionLost(errors.New("other")))
+	require.False(t, sessionLost(nil))
+}
+
+func TestOpenPKCS11Errors(t *testing.T) {
+	pinFile := filepath.Join(t.TempDir(), "pin")
+	require.NoError(t, os.WriteFile(pinFile, []byte("1234"), 0600))
+
+	_, err := OpenPKCS11(PKCS11Config{Module: "/nonexistent/libpkcs11.so", PINFile: filepath.Join(t.TempDir(), "pin")})
+	require.ErrorContains(t, err, "read PIN file")
+
+	_, err = OpenPKCS11(PKCS11Config{Module: "/nonexistent/libpkcs11.so", PINFile: pinFile})
+	require.ErrorContains(t, err, "could not load PKCS#11 module")
+}
+
+// setupSoftHSM initializes a SoftHSM token holding the given key, skipping the test if SoftHSM isn't installed,
+// unless SOFTHSM2_MODULE is given.
+func setupSoftHSM(t *testing.T, sk *rsa.PrivateKey) PKCS11Config {
+	module := os.Getenv("SOFTHSM2_MODULE")
+	if module == "" {
+		for _, path := range softHSMModules {
+			if _, err := os.Stat(path); err == nil {
+				module = path
+				break
+			}
+		}
+	}
+	if module == "" {
+		t.Skip("SoftHSM is not installed")
+	}
+
+	dir := t.TempDir()
+	tokenDir := filepath.Join(dir, "tokens")
+	require.NoError(t, os.Mkdir(tokenDir, 0700))
+	conf := filepath.Join(dir, "softhsm2.conf")
+	require.NoError(t, os.WriteFile(conf, []byte("directories.tokendir = "+tokenDir+"\nobjectstore.backend = file\n"), 0600))
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	cfg := PKCS11Config{
+		Module:     module,
+		TokenLabel: "ssv",
+		PINFile:    filepath.Join(dir, "pin"),
+		KeyLabel:   "operator",
+	}
+	const pin, soPIN = "1234", "5678"
+	require.NoError(t, os.WriteFile(cfg.PINFile, []byte(pin+"\n"), 0600))
+
+	ctx := pkcs11.New(module)
+	require.NotNil(t, ctx)
+	require.NoError(t, ctx.Initialize())
+	defer ctx.Destroy()
+	defer ctx.Finalize()
+
+	slots, err := ctx.GetSlotList(true)
+	require.NoError(t, err)
+	require.NotEmpty(t, slots)
+	require.NoError(t, ctx.InitToken(slots[0], soPIN, cfg.TokenLabel))
+	// SoftHSM moves an initialized token to another slot.
+	slot, err := findSlot(ctx, cfg.TokenLabel)
+	require.NoError(t, err)
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	require.NoError(t, err)
+	defer ctx.CloseSession(session)
+	require.NoError(t, ctx.Login(session, pkcs11.CKU_SO, soPIN))
+	require.NoError(t, ctx.InitPIN(session, pin))
+	require.NoError(t, ctx.Logout(session))
+	require.NoError(t, ctx.Login(session, pkcs11.CKU_USER, pin))
+	defer ctx.Logout(session)
+
+	_, err = ctx.CreateObject(session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.KeyLabel),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS, sk.N.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, big.NewInt(int64(sk.E)).Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE_EXPONENT, sk.D.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIME_1, sk.Primes[0].Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIME_2, sk.Primes[1].Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_EXPONENT_1, sk.Precomputed.Dp.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_EXPONENT_2, sk.Precomputed.Dq.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_COEFFICIENT, sk.Precomputed.Qinv.Bytes()),
+	})
+	require.NoError(t, err)
+	return cfg
+}
@@ -41,7 +41,9 @@ type Options struct {
 	DB                  basedb.Database
 	ValidatorController validator.Controller
 	ValidatorOptions    validator.ControllerOptions `yaml:"ValidatorOptions"`
+	ShadowMode          bool                        `yaml:"ShadowMode" env:"SHADOW_MODE" env-default:"false" env-description:"Run duties without broadcasting signed messages or submitting them to the beacon chain, only logging them"`
 	DutyStore           *dutystore.Store
+	FeeRecipientCtrl    fee_recipient.RecipientController
 	WS                  api.WebSocketServer
 	WsAPIPort           int
 	Metrics             nodeMetrics
@@ -104,18 +106,11 @@ func New(logger *zap.Logger, opts Options, slotTickerProvider slotticker.Provide
 			ValidatorExitCh:     opts.ValidatorController.ValidatorExitChan(),
 			ExecuteDuty:         opts.ValidatorController.ExecuteDuty,
 			BuilderProposals:    opts.ValidatorOptions.BuilderProposals,
+			ProposerConfig:      opts.ValidatorOptions.ProposerConfig,
 			DutyStore:           opts.DutyStore,
 			SlotTickerProvider:  slotTickerProvider,
 		}),
-		feeRecipientCtrl: fee_recipient.NewController(&fee_recipient.ControllerOptions{
-			Ctx:                opts.Context,
-			BeaconClient:       opts.BeaconNode,
-			Network:            opts.Network,
-			ShareStorage:       opts.ValidatorOptions.RegistryStorage.Shares(),
-			RecipientStorage:   opts.ValidatorOptions.RegistryStorage,
-			OperatorData:       opts.ValidatorOptions.OperatorData,
-			SlotTickerProvider: slotTickerProvider,
-		}),
+		feeRecipientCtrl: opts.FeeRecipientCtrl,
 
 		ws:        opts.WS,
 		wsAPIPort: opts.WsAPIPort,
@@ -0,0 +1,158 @@
+// Package shadow wraps the node's network and beacon node in shadow mode,
+// in which the node runs its duties without broadcasting its own signed messages
+// and without submitting anything it signed to the beacon chain.
+//
+// Shadow mode lets a node run next to the live node of the same operator,
+// e.g. on a migration target host, without risking double signing.
+package shadow
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/api"
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	ssz "github.com/ferranbt/fastssz"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/beacon/goclient"
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/network"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+)
+
+// Network is a P2PNetwork which doesn't broadcast messages, but only logs them.
+// Messages of other operators are received as usual.
+type Network struct {
+	network.P2PNetwork
+	logger *zap.Logger
+}
+
+// NewNetwork wraps the given network in shadow mode.
+func NewNetwork(logger *zap.Logger, net network.P2PNetwork) *Network {
+	return &Network{
+		P2PNetwork: net,
+		logger:     logger.Named("ShadowNetwork"),
+	}
+}
+
+// Broadcast logs the message instead of broadcasting it.
+func (n *Network) Broadcast(msg *spectypes.SSVMessage) error {
+	n.logger.Info("shadow mode: would have broadcast message",
+		fields.MessageID(msg.MsgID),
+		fields.MessageType(msg.MsgType),
+		fields.Role(msg.MsgID.GetRoleType()))
+	return nil
+}
+
+// BeaconNode is a BeaconNode which doesn't submit signed objects, but only logs them.
+// Everything else, including fetching duties and the data to sign, is served by the underlying beacon node.
+//
+// SubmitAggregateSelectionProof is passed through since it only fetches the aggregate to sign.
+// Subnet subscriptions and proposal preparations are passed through as well,
+// since they carry no signatures and only keep the underlying beacon node ready to take over.
+// Block proposals with their values are served by the underlying beacon node, if it provides them.
+type BeaconNode struct {
+	beaconprotocol.BeaconNode
+	logger *zap.Logger
+}
+
+// NewBeaconNode wraps the given beacon node in shadow mode.
+func NewBeaconNode(logger *zap.Logger, beaconNode beaconprotocol.BeaconNode) *BeaconNode {
+	return &BeaconNode{
+		BeaconNode: beaconNode,
+		logger:     logger.Named("ShadowBeaconNode"),
+	}
+}
+
+// GetBeaconBlockWithValue passes through to the underlying beacon node.
+func (b *BeaconNode) GetBeaconBlockWithValue(slot phase0.Slot, graffiti, randao []byte) (ssz.Marshaler, spec.DataVersion, *big.Int, error) {
+	provider, ok := b.BeaconNode.(goclient.BlockValueProvider)
+	if !ok {
+		return nil, goclient.DataVersionNil, nil, errors.New("beacon node doesn't provide block values")
+	}
+	return provider.GetBeaconBlockWithValue(slot, graffiti, randao)
+}
+
+// GetBlindedBeaconBlockWithValue passes through to the underlying beacon node.
+func (b *BeaconNode) GetBlindedBeaconBlockWithValue(slot phase0.Slot, graffiti, randao []byte) (ssz.Marshaler, spec.DataVersion, *big.Int, error) {
+	provider, ok := b.BeaconNode.(goclient.BlockValueProvider)
+	if !ok {
+		return nil, goclient.DataVersionNil, nil, errors.New("beacon node doesn't provide block values")
+	}
+	return provider.GetBlindedBeaconBlockWithValue(slot, graffiti, randao)
+}
+
+func (b *BeaconNode) SubmitAttestation(attestation *phase0.Attestation) error {
+	b.logger.Info("shadow mode: would have submitted attestation",
+		fields.Slot(attestation.Data.Slot),
+		zap.Uint64("committee_index", uint64(attestation.Data.Index)),
+		fields.Root(attestation.Data.BeaconBlockRoot))
+	return nil
+}
+
+func (b *BeaconNode) SubmitBeaconBlock(block *spec.VersionedBeaconBlock, sig phase0.BLSSignature) error {
+	slot, _ := block.Slot()
+	b.logger.Info("shadow mode: would have submitted block",
+		fields.Slot(slot),
+		zap.String("version", block.Version.String()))
+	return nil
+}
+
+func (b *BeaconNode) SubmitBlindedBeaconBlock(block *api.VersionedBlindedBeaconBlock, sig phase0.BLSSignature) error {
+	slot, _ := block.Slot()
+	b.logger.Info("shadow mode: would have submitted blinded block",
+		fields.Slot(slot),
+		zap.String("version", block.Version.String()))
+	return nil
+}
+
+func (b *BeaconNode) SubmitSignedAggregateSelectionProof(msg *phase0.SignedAggregateAndProof) error {
+	b.logger.Info("shadow mode: would have submitted aggregate and proof",
+		fields.Slot(msg.Message.Aggregate.Data.Slot),
+		zap.Uint64("validator_index", uint64(msg.Message.AggregatorIndex)))
+	return nil
+}
+
+func (b *BeaconNode) SubmitSyncMessage(msg *altair.SyncCommitteeMessage) error {
+	b.logger.Info("shadow mode: would have submitted sync committee message",
+		fields.Slot(msg.Slot),
+		zap.Uint64("validator_index", uint64(msg.ValidatorIndex)),
+		fields.Root(msg.BeaconBlockRoot))
+	return nil
+}
+
+func (b *BeaconNode) SubmitSignedContributionAndProof(contribution *altair.SignedContributionAndProof) error {
+	b.logger.Info("shadow mode: would have submitted sync committee contribution",
+		fields.Slot(contribution.Message.Contribution.Slot),
+		zap.Uint64("validator_index", uint64(contribution.Message.AggregatorIndex)),
+		zap.Uint64("subcommittee_index", contribution.Message.Contribution.SubcommitteeIndex))
+	return nil
+}
+
+func (b *BeaconNode) SubmitValidatorRegistration(pubkey []byte, feeRecipient bellatrix.ExecutionAddress, sig phase0.BLSSignature) error {
+	b.logger.Info("shadow mode: would have submitted validator registration",
+		fields.PubKey(pubkey),
+		zap.String("fee_recipient", feeRecipient.String()))
+	return nil
+}
+
+func (b *BeaconNode) SubmitSignedValidatorRegistration(registration *eth2apiv1.ValidatorRegistration, sig phase0.BLSSignature) error {
+	b.logger.Info("shadow mode: would have submitted validator registration",
+		fields.PubKey(registration.Pubkey[:]),
+		zap.String("fee_recipient", registration.FeeRecipient.String()),
+		zap.Uint64("gas_limit", registration.GasLimit))
+	return nil
+}
+
+func (b *BeaconNode) SubmitVoluntaryExit(voluntaryExit *phase0.SignedVoluntaryExit, sig phase0.BLSSignature) error {
+	b.logger.Info("shadow mode: would have submitted voluntary exit",
+		zap.Uint64("validator_index", uint64(voluntaryExit.Message.ValidatorIndex)),
+		fields.Epoch(voluntaryExit.Message.Epoch))
+	return nil
+}
@@ -0,0 +1,64 @@
+package shadow
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+)
+
+// countingNetwork counts the messages broadcast through it.
+type countingNetwork struct {
+	network.P2PNetwork
+	broadcasts int
+}
+
+func (n *countingNetwork) Broadcast(msg *spectypes.SSVMessage) error {
+	n.broadcasts++
+	return nil
+}
+
+func TestNetwork(t *testing.T) {
+	underlying := &countingNetwork{}
+	net := NewNetwork(logging.TestLogger(t), underlying)
+
+	msgID := spectypes.NewMsgID(spectypes.GenesisMainnet, []byte{1, 2, 3}, spectypes.BNRoleAttester)
+	require.NoError(t, net.Broadcast(&spectypes.SSVMessage{MsgType: spectypes.SSVConsensusMsgType, MsgID: msgID}))
+	require.Zero(t, underlying.broadcasts)
+}
+
+func TestBeaconNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	underlying := beacon.NewMockBeaconNode(ctrl)
+	bn := NewBeaconNode(logging.TestLogger(t), underlying)
+
+	// Signed objects are not submitted, which the mock would fail on.
+	require.NoError(t, bn.SubmitAttestation(&phase0.Attestation{Data: &phase0.AttestationData{Slot: 1}}))
+	require.NoError(t, bn.SubmitSyncMessage(&altair.SyncCommitteeMessage{Slot: 1}))
+	require.NoError(t, bn.SubmitSignedAggregateSelectionProof(&phase0.SignedAggregateAndProof{
+		Message: &phase0.AggregateAndProof{Aggregate: &phase0.Attestation{Data: &phase0.AttestationData{Slot: 1}}},
+	}))
+	require.NoError(t, bn.SubmitSignedContributionAndProof(&altair.SignedContributionAndProof{
+		Message: &altair.ContributionAndProof{Contribution: &altair.SyncCommitteeContribution{Slot: 1}},
+	}))
+	require.NoError(t, bn.SubmitValidatorRegistration([]byte{1}, bellatrix.ExecutionAddress{}, phase0.BLSSignature{}))
+	require.NoError(t, bn.SubmitVoluntaryExit(&phase0.SignedVoluntaryExit{Message: &phase0.VoluntaryExit{Epoch: 1}}, phase0.BLSSignature{}))
+
+	// Fetching the data to sign is passed through.
+	underlying.EXPECT().GetAttestationData(phase0.Slot(1), phase0.CommitteeIndex(2)).Return(nil, spec.DataVersionPhase0, nil)
+	_, _, err := bn.GetAttestationData(1, 2)
+	require.NoError(t, err)
+
+	// Block values are only provided if the underlying beacon node provides them.
+	_, _, _, err = bn.GetBlindedBeaconBlockWithValue(1, nil, nil)
+	require.ErrorContains(t, err, "doesn't provide block values")
+}
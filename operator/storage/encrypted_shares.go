@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+var encryptedSharesPrefix = []byte("encrypted-shares/")
+
+// SaveEncryptedShares saves the share private keys of a validator, as encrypted by the ValidatorAdded event
+// for each operator of its committee, so that other nodes can be bootstrapped from a registry snapshot.
+func (s *storage) SaveEncryptedShares(rw basedb.ReadWriter, validatorPK []byte, encryptedKeys [][]byte) error {
+	b, err := json.Marshal(encryptedKeys)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return s.db.Using(rw).Set(storagePrefix, encryptedSharesKey(validatorPK), b)
+}
+
+// GetEncryptedShares returns the encrypted share private keys of a validator, in the order of its committee.
+func (s *storage) GetEncryptedShares(r basedb.Reader, validatorPK []byte) ([][]byte, bool, error) {
+	obj, found, err := s.db.UsingReader(r).Get(storagePrefix, encryptedSharesKey(validatorPK))
+	if err != nil || !found {
+		return nil, found, err
+	}
+	var encryptedKeys [][]byte
+	if err := json.Unmarshal(obj.Value, &encryptedKeys); err != nil {
+		return nil, false, fmt.Errorf("unmarshal: %w", err)
+	}
+	return encryptedKeys, true, nil
+}
+
+func (s *storage) DeleteEncryptedShares(rw basedb.ReadWriter, validatorPK []byte) error {
+	return s.db.Using(rw).Delete(storagePrefix, encryptedSharesKey(validatorPK))
+}
+
+func (s *storage) dropEncryptedShares() error {
+	return s.db.DropPrefix(append(storagePrefix, encryptedSharesPrefix...))
+}
+
+func encryptedSharesKey(validatorPK []byte) []byte {
+	return append(append([]byte{}, encryptedSharesPrefix...), validatorPK...)
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/bloxapp/ssv/operator/keys"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// RotatePrivateKey replaces the stored hash of the operator private key with the hash of the given one,
+// after which the node accepts only the given key.
+// The share keys of the registry are encrypted with the public key of their operator on-chain,
+// so a key of another operator can't decrypt them, and the registry must be synced again with it instead.
+func (s *storage) RotatePrivateKey(rw basedb.ReadWriter, operatorKey keys.OperatorPrivateKey) error {
+	hashedKey, err := operatorKey.StorageHash()
+	if err != nil {
+		return fmt.Errorf("hash new private key: %w", err)
+	}
+	if err := s.db.Using(rw).Set(storagePrefix, []byte(HashedPrivateKey), []byte(hashedKey)); err != nil {
+		return fmt.Errorf("save private key hash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+var peerPolicyKey = []byte("peer-policy")
+
+// PeerPolicy is the peer policy set at runtime, which overrides the configured static and denied peers.
+type PeerPolicy struct {
+	// StaticPeers are the multiaddrs of the static peers, including their peer ID.
+	StaticPeers []string `json:"static_peers"`
+	// DenyPeers are the IDs of the denied peers.
+	DenyPeers []string `json:"deny_peers"`
+}
+
+func (s *storage) SavePeerPolicy(rw basedb.ReadWriter, policy *PeerPolicy) error {
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return s.db.Using(rw).Set(storagePrefix, peerPolicyKey, b)
+}
+
+func (s *storage) GetPeerPolicy(r basedb.Reader) (*PeerPolicy, bool, error) {
+	obj, found, err := s.db.UsingReader(r).Get(storagePrefix, peerPolicyKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("db: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	policy := &PeerPolicy{}
+	if err := json.Unmarshal(obj.Value, policy); err != nil {
+		return nil, false, fmt.Errorf("unmarshal: %w", err)
+	}
+	return policy, true, nil
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+var processedBlocksPrefix = []byte("processed-block/")
+
+// ProcessedBlock is a recently processed block, retained to detect and roll back reorgs.
+type ProcessedBlock struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+	// Undo holds the previous values of the keys changed by processing the block.
+	Undo []UndoEntry `json:"undo,omitempty"`
+	// Pruned is set once the block fell out of the reorg window and its journal was dropped,
+	// in which case only its hash is retained to tell whether a reorg is deeper than the window.
+	Pruned bool `json:"pruned,omitempty"`
+}
+
+// UndoEntry is the value of a key before it was changed by processing a block.
+type UndoEntry struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value,omitempty"`
+	// Existed is false if the key didn't exist, in which case undoing deletes it.
+	Existed bool `json:"existed"`
+}
+
+// Apply restores the value of the key.
+func (e UndoEntry) Apply(rw basedb.ReadWriter) error {
+	if !e.Existed {
+		return rw.Delete(nil, e.Key)
+	}
+	return rw.Set(nil, e.Key, e.Value)
+}
+
+func (s *storage) SaveProcessedBlock(rw basedb.ReadWriter, block *ProcessedBlock) error {
+	b, err := json.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return s.db.Using(rw).Set(storagePrefix, processedBlockKey(block.Number), b)
+}
+
+// GetProcessedBlocks returns the retained processed blocks, ordered by their number.
+func (s *storage) GetProcessedBlocks(r basedb.Reader) ([]*ProcessedBlock, error) {
+	var blocks []*ProcessedBlock
+	err := s.db.UsingReader(r).GetAll(append(storagePrefix, processedBlocksPrefix...), func(i int, obj basedb.Obj) error {
+		block := &ProcessedBlock{}
+		if err := json.Unmarshal(obj.Value, block); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+		blocks = append(blocks, block)
+		return nil
+	})
+	return blocks, err
+}
+
+// PruneProcessedBlock drops the journal of the given processed block, if it wasn't dropped yet.
+func (s *storage) PruneProcessedBlock(rw basedb.ReadWriter, number uint64) error {
+	obj, found, err := s.db.Using(rw).Get(storagePrefix, processedBlockKey(number))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	block := &ProcessedBlock{}
+	if err := json.Unmarshal(obj.Value, block); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	if block.Pruned {
+		return nil
+	}
+	block.Undo = nil
+	block.Pruned = true
+	return s.SaveProcessedBlock(rw, block)
+}
+
+func (s *storage) DeleteProcessedBlock(rw basedb.ReadWriter, number uint64) error {
+	return s.db.Using(rw).Delete(storagePrefix, processedBlockKey(number))
+}
+
+func (s *storage) dropProcessedBlocks() error {
+	return s.db.DropPrefix(append(storagePrefix, processedBlocksPrefix...))
+}
+
+// processedBlockKey returns the key of a processed block, which sorts by the block number.
+func processedBlockKey(number uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, processedBlocksPrefix...), number)
+}
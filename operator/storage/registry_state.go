@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"fmt"
+	"math/big"
+
+	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// RegistryState is the registry as of the last processed block,
+// which is exported to and imported from registry snapshots.
+type RegistryState struct {
+	Block      uint64                           `json:"block"`
+	Operators  []registrystorage.OperatorData   `json:"operators"`
+	Shares     []*RegistryShare                 `json:"shares"`
+	Recipients []*registrystorage.RecipientData `json:"recipients"`
+}
+
+// RegistryShare is a validator share along with its share private keys,
+// as encrypted by the ValidatorAdded event for each operator of its committee.
+type RegistryShare struct {
+	// Share is the encoded SSVShare.
+	Share         []byte   `json:"share"`
+	EncryptedKeys [][]byte `json:"encrypted_keys,omitempty"`
+}
+
+// ExportRegistry reads the registry state with the given reader,
+// which should be a read transaction for the state to be consistent.
+func (s *storage) ExportRegistry(r basedb.Reader) (*RegistryState, error) {
+	block, found, err := s.GetLastProcessedBlock(r)
+	if err != nil {
+		return nil, fmt.Errorf("get last processed block: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("no processed blocks")
+	}
+	state := &RegistryState{Block: block.Uint64()}
+
+	state.Operators, err = s.ListOperators(r, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list operators: %w", err)
+	}
+	state.Recipients, err = s.ListRecipients(r)
+	if err != nil {
+		return nil, fmt.Errorf("list recipients: %w", err)
+	}
+
+	shares, err := s.Shares().ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read shares: %w", err)
+	}
+	for _, share := range shares {
+		encoded, err := share.Encode()
+		if err != nil {
+			return nil, err
+		}
+		encryptedKeys, _, err := s.GetEncryptedShares(r, share.ValidatorPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("get encrypted shares: %w", err)
+		}
+		state.Shares = append(state.Shares, &RegistryShare{
+			Share:         encoded,
+			EncryptedKeys: encryptedKeys,
+		})
+	}
+	return state, nil
+}
+
+// ImportRegistry saves the given registry state, including its last processed block.
+// It should be imported into an empty registry.
+func (s *storage) ImportRegistry(rw basedb.ReadWriter, state *RegistryState) error {
+	for i := range state.Operators {
+		if _, err := s.SaveOperatorData(rw, &state.Operators[i]); err != nil {
+			return fmt.Errorf("save operator: %w", err)
+		}
+	}
+	for _, recipient := range state.Recipients {
+		if _, err := s.SaveRecipientData(rw, recipient); err != nil {
+			return fmt.Errorf("save recipient: %w", err)
+		}
+	}
+	for _, rs := range state.Shares {
+		share := &ssvtypes.SSVShare{}
+		if err := share.Decode(rs.Share); err != nil {
+			return err
+		}
+		if err := s.Shares().Save(rw, share); err != nil {
+			return fmt.Errorf("save share: %w", err)
+		}
+		if len(rs.EncryptedKeys) == 0 {
+			continue
+		}
+		if err := s.SaveEncryptedShares(rw, share.ValidatorPubKey, rs.EncryptedKeys); err != nil {
+			return fmt.Errorf("save encrypted shares: %w", err)
+		}
+	}
+	if err := s.SaveLastProcessedBlock(rw, new(big.Int).SetUint64(state.Block)); err != nil {
+		return fmt.Errorf("save last processed block: %w", err)
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -13,11 +12,11 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/operator/keys"
 	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	registry "github.com/bloxapp/ssv/protocol/v2/blockchain/eth1"
 	registrystorage "github.com/bloxapp/ssv/registry/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
-	"github.com/bloxapp/ssv/utils/rsaencryption"
 )
 
 var HashedPrivateKey = "hashed-private-key"
@@ -38,25 +37,46 @@ type Storage interface {
 	SaveLastProcessedBlock(rw basedb.ReadWriter, offset *big.Int) error
 	GetLastProcessedBlock(r basedb.Reader) (*big.Int, bool, error)
 
+	SaveProcessedBlock(rw basedb.ReadWriter, block *ProcessedBlock) error
+	GetProcessedBlocks(r basedb.Reader) ([]*ProcessedBlock, error)
+	PruneProcessedBlock(rw basedb.ReadWriter, number uint64) error
+	DeleteProcessedBlock(rw basedb.ReadWriter, number uint64) error
+
+	SavePendingTask(rw basedb.ReadWriter, task *PendingTask) error
+	GetPendingTasks(r basedb.Reader) ([]*PendingTask, error)
+	DeletePendingTask(rw basedb.ReadWriter, block uint64, index uint32) error
+
+	SaveEncryptedShares(rw basedb.ReadWriter, validatorPK []byte, encryptedKeys [][]byte) error
+	GetEncryptedShares(r basedb.Reader, validatorPK []byte) ([][]byte, bool, error)
+	DeleteEncryptedShares(rw basedb.ReadWriter, validatorPK []byte) error
+
+	ExportRegistry(r basedb.Reader) (*RegistryState, error)
+	ImportRegistry(rw basedb.ReadWriter, state *RegistryState) error
+
 	GetConfig(rw basedb.ReadWriter) (*ConfigLock, bool, error)
 	SaveConfig(rw basedb.ReadWriter, config *ConfigLock) error
 	DeleteConfig(rw basedb.ReadWriter) error
 
+	SavePeerPolicy(rw basedb.ReadWriter, policy *PeerPolicy) error
+	GetPeerPolicy(r basedb.Reader) (*PeerPolicy, bool, error)
+
 	registry.RegistryStore
 
 	registrystorage.Operators
 	registrystorage.Recipients
 	Shares() registrystorage.Shares
 
-	GetPrivateKey() (*rsa.PrivateKey, bool, error)
+	GetPrivateKey() (keys.OperatorPrivateKey, bool, error)
 	SetupPrivateKey(operatorKeyBase64 string) ([]byte, error)
+	SetupOperatorKey(operatorKey keys.OperatorPrivateKey) ([]byte, error)
+	RotatePrivateKey(rw basedb.ReadWriter, operatorKey keys.OperatorPrivateKey) error
 }
 
 type storage struct {
 	logger *zap.Logger
 	db     basedb.Database
 
-	operatorPrivateKey []byte
+	operatorPrivateKey keys.OperatorPrivateKey
 	operatorStore      registrystorage.Operators
 	recipientStore     registrystorage.Recipients
 	shareStore         registrystorage.Shares
@@ -126,6 +146,10 @@ func (s *storage) GetRecipientDataMany(r basedb.Reader, owners []common.Address)
 	return s.recipientStore.GetRecipientDataMany(r, owners)
 }
 
+func (s *storage) ListRecipients(r basedb.Reader) ([]*registrystorage.RecipientData, error) {
+	return s.recipientStore.ListRecipients(r)
+}
+
 func (s *storage) SaveRecipientData(rw basedb.ReadWriter, recipientData *registrystorage.RecipientData) (*registrystorage.RecipientData, error) {
 	return s.recipientStore.SaveRecipientData(rw, recipientData)
 }
@@ -151,6 +175,18 @@ func (s *storage) DropRegistryData() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to drop last processed block")
 	}
+	err = s.dropProcessedBlocks()
+	if err != nil {
+		return errors.Wrap(err, "failed to drop processed blocks")
+	}
+	err = s.dropPendingTasks()
+	if err != nil {
+		return errors.Wrap(err, "failed to drop pending tasks")
+	}
+	err = s.dropEncryptedShares()
+	if err != nil {
+		return errors.Wrap(err, "failed to drop encrypted shares")
+	}
 	err = s.DropShares()
 	if err != nil {
 		return errors.Wrap(err, "failed to drop operators")
@@ -214,40 +250,37 @@ func (s *storage) GetHashedPrivateKey() ([]byte, bool, error) {
 	return obj.Value, found, nil
 }
 
-// GetPrivateKey return rsa private key
-func (s *storage) GetPrivateKey() (*rsa.PrivateKey, bool, error) {
-	privateKey := s.operatorPrivateKey
-	if privateKey == nil {
+// GetPrivateKey return the operator private key
+func (s *storage) GetPrivateKey() (keys.OperatorPrivateKey, bool, error) {
+	if s.operatorPrivateKey == nil {
 		return nil, false, nil
 	}
-	sk, err := rsaencryption.ConvertPemToPrivateKey(string(privateKey))
-	if err != nil {
-		return nil, false, err
-	}
-	return sk, true, nil
+	return s.operatorPrivateKey, true, nil
 }
 
-// SetupPrivateKey setup operator private key at the init of the node and set OperatorPublicKey config
+// SetupPrivateKey setup operator private key of the given base64 encoded PEM at the init of the node
 func (s *storage) SetupPrivateKey(operatorKeyBase64 string) ([]byte, error) {
 	operatorKeyByte, err := base64.StdEncoding.DecodeString(operatorKeyBase64)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to decode base64")
 	}
-	var operatorKey = string(operatorKeyByte)
+	var operatorKey keys.OperatorPrivateKey
+	if len(operatorKeyByte) != 0 {
+		operatorKey, err = keys.PrivateKeyFromPEM(operatorKeyByte)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get operator private key")
+		}
+	}
+	return s.SetupOperatorKey(operatorKey)
+}
 
+// SetupOperatorKey setup operator private key at the init of the node and return the operator public key
+func (s *storage) SetupOperatorKey(operatorKey keys.OperatorPrivateKey) ([]byte, error) {
 	if err := s.validateKey(operatorKey); err != nil {
 		return nil, err
 	}
 
-	sk, found, err := s.GetPrivateKey()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get operator private key")
-	}
-	if !found {
-		return nil, errors.New("failed to find operator private key")
-	}
-
-	operatorPublicKey, err := rsaencryption.ExtractPublicKey(sk)
+	operatorPublicKey, err := keys.Base64PublicKey(operatorKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to extract operator public key")
 	}
@@ -258,46 +291,38 @@ func (s *storage) SetupPrivateKey(operatorKeyBase64 string) ([]byte, error) {
 }
 
 // validateKey validate provided and exist key. save if needed.
-func (s *storage) validateKey(operatorKey string) error {
-	// check if passed new key. if so, save new key (force to always save key when provided)
+func (s *storage) validateKey(operatorKey keys.OperatorPrivateKey) error {
 	storedPrivateKey, privateKeyExist, err := s.GetHashedPrivateKey()
 	if err != nil {
 		return errors.New("Can't Get Operator private key from storage")
 	}
-	hashedKey, err := rsaencryption.HashRsaKey([]byte(operatorKey))
+	if operatorKey == nil {
+		if privateKeyExist {
+			return errors.New("Operator private key is not matching the one encrypted the storage")
+		}
+		return errors.New("key not exist or provided")
+	}
+	hashedKey, err := operatorKey.StorageHash()
 	if err != nil {
 		return errors.New("Cannot hash Operator private key")
 	}
 	if privateKeyExist && hashedKey != string(storedPrivateKey) {
 		return errors.New("Operator private key is not matching the one encrypted the storage")
 	}
-	if operatorKey != "" {
-		return s.savePrivateKey(operatorKey)
-	}
-	// new key not provided, check if key exist
-	_, found, err := s.GetPrivateKey()
-	if err != nil {
-		return err
-	}
-	// if no, check  if you need to generate. if no, return error
-	if !found {
-		return errors.New("key not exist or provided")
-	}
-
-	// key exist in storage.
-	return nil
+	// force to always save key when provided
+	return s.savePrivateKey(operatorKey)
 }
 
 // SavePrivateKey save operator private key
-func (s *storage) savePrivateKey(operatorKey string) error {
-	hashedKey, err := rsaencryption.HashRsaKey([]byte(operatorKey))
+func (s *storage) savePrivateKey(operatorKey keys.OperatorPrivateKey) error {
+	hashedKey, err := operatorKey.StorageHash()
 	if err != nil {
 		return err
 	}
 	if err := s.db.Set(storagePrefix, []byte(HashedPrivateKey), []byte(hashedKey)); err != nil {
 		return err
 	}
-	s.operatorPrivateKey = []byte(operatorKey)
+	s.operatorPrivateKey = operatorKey
 	return nil
 }
 
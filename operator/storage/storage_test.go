@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"encoding/base64"
 	"testing"
 
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
@@ -12,11 +11,11 @@ import (
 
 	"github.com/bloxapp/ssv/logging"
 	"github.com/bloxapp/ssv/networkconfig"
+	"github.com/bloxapp/ssv/operator/keys"
 	"github.com/bloxapp/ssv/protocol/v2/types"
 	registrystorage "github.com/bloxapp/ssv/registry/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/storage/kv"
-	"github.com/bloxapp/ssv/utils/rsaencryption"
 )
 
 var (
@@ -35,13 +34,13 @@ func TestSaveAndGetPrivateKey(t *testing.T) {
 		db: db,
 	}
 
-	KeyByte, err := base64.StdEncoding.DecodeString(skPem) // passing keys format should be in base64
+	operatorKey, err := keys.PrivateKeyFromBase64(skPem) // passing keys format should be in base64
 	require.NoError(t, err)
-	require.NoError(t, operatorStorage.savePrivateKey(string(KeyByte)))
+	require.NoError(t, operatorStorage.savePrivateKey(operatorKey))
 	sk, found, err := operatorStorage.GetPrivateKey()
 	require.True(t, true, found)
 	require.NoError(t, err)
-	operatorPublicKey, err := rsaencryption.ExtractPublicKey(sk)
+	operatorPublicKey, err := keys.Base64PublicKey(sk)
 	require.NoError(t, err)
 	require.Equal(t, pkPem, operatorPublicKey)
 }
@@ -99,17 +98,13 @@ func TestSetupPrivateKey(t *testing.T) {
 			}
 
 			if test.existKey != "" { // mock exist key
-				existKeyByte, err := base64.StdEncoding.DecodeString(test.existKey) // passing keys format should be in base64
+				existKey, err := keys.PrivateKeyFromBase64(test.existKey) // passing keys format should be in base64
 				require.NoError(t, err)
-				require.NoError(t, operatorStorage.savePrivateKey(string(existKeyByte)))
+				require.NoError(t, operatorStorage.savePrivateKey(existKey))
 				sk, found, err := operatorStorage.GetPrivateKey()
 				require.NoError(t, err)
 				require.True(t, found)
-				require.NotNil(t, sk)
-
-				existKeyByte, err = base64.StdEncoding.DecodeString(test.existKey)
-				require.NoError(t, err)
-				require.Equal(t, string(existKeyByte), string(rsaencryption.PrivateKeyToByte(sk)))
+				require.Equal(t, existKey, sk)
 			}
 
 			pk, err := operatorStorage.SetupPrivateKey(test.passedKey)
@@ -129,15 +124,15 @@ func TestSetupPrivateKey(t *testing.T) {
 				return
 			}
 			if test.existKey != "" && test.passedKey == "" { // exist and not passed in env
-				existKeyByte, err := base64.StdEncoding.DecodeString(test.existKey)
+				existKey, err := keys.PrivateKeyFromBase64(test.existKey)
 				require.NoError(t, err)
-				require.Equal(t, string(existKeyByte), string(rsaencryption.PrivateKeyToByte(sk)))
+				require.Equal(t, existKey.Public(), sk.Public())
 				return
 			}
 			// not exist && passed and exist && passed
-			passedKeyByte, err := base64.StdEncoding.DecodeString(test.passedKey)
+			passedKey, err := keys.PrivateKeyFromBase64(test.passedKey)
 			require.NoError(t, err)
-			require.Equal(t, string(passedKeyByte), string(rsaencryption.PrivateKeyToByte(sk)))
+			require.Equal(t, passedKey.Public(), sk.Public())
 		})
 	}
 }
@@ -255,3 +250,52 @@ func TestNetworkAndLocalEventsConfig(t *testing.T) {
 	require.True(t, found)
 	require.Equal(t, c2, storedCfg)
 }
+
+func TestRotatePrivateKey(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	storage, err := NewNodeStorage(logger, db)
+	require.NoError(t, err)
+	_, err = storage.SetupPrivateKey(skPem)
+	require.NoError(t, err)
+
+	newKey, err := keys.PrivateKeyFromBase64(skPem2)
+	require.NoError(t, err)
+	require.NoError(t, storage.RotatePrivateKey(nil, newKey))
+
+	// The node accepts only the new key from then on.
+	storage, err = NewNodeStorage(logger, db)
+	require.NoError(t, err)
+	_, err = storage.SetupPrivateKey(skPem)
+	require.ErrorContains(t, err, "Operator private key is not matching")
+	_, err = storage.SetupPrivateKey(skPem2)
+	require.NoError(t, err)
+}
+
+func TestPeerPolicy(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	storage, err := NewNodeStorage(logger, db)
+	require.NoError(t, err)
+
+	_, found, err := storage.GetPeerPolicy(nil)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	policy := &PeerPolicy{
+		StaticPeers: []string{"/ip4/192.168.1.2/tcp/13001/p2p/16Uiu2HAmQYgGRD3jKjj9q4JvPV2hYDCv6xWDUvbwmXMQ9uJ4ouwT"},
+		DenyPeers:   []string{},
+	}
+	require.NoError(t, storage.SavePeerPolicy(nil, policy))
+
+	stored, found, err := storage.GetPeerPolicy(nil)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, policy, stored)
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+var tasksPrefix = []byte("task/")
+
+// PendingTask is a task issued by a contract event, which is journaled in the same transaction
+// as its block and deleted once it's executed successfully.
+type PendingTask struct {
+	// Block is the number of the block whose event issued the task.
+	Block uint64 `json:"block"`
+	// Index is the index of the task among the tasks issued by the block.
+	Index uint32 `json:"index"`
+	// Type is the type of the task, such as StartValidator.
+	Type string `json:"type"`
+	// Data is the type-specific encoding of the task.
+	Data json.RawMessage `json:"data"`
+
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+}
+
+func (s *storage) SavePendingTask(rw basedb.ReadWriter, task *PendingTask) error {
+	b, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return s.db.Using(rw).Set(storagePrefix, pendingTaskKey(task.Block, task.Index), b)
+}
+
+// GetPendingTasks returns the pending tasks in the order they were issued.
+func (s *storage) GetPendingTasks(r basedb.Reader) ([]*PendingTask, error) {
+	var tasks []*PendingTask
+	err := s.db.UsingReader(r).GetAll(append(storagePrefix, tasksPrefix...), func(i int, obj basedb.Obj) error {
+		task := &PendingTask{}
+		if err := json.Unmarshal(obj.Value, task); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+		tasks = append(tasks, task)
+		return nil
+	})
+	return tasks, err
+}
+
+func (s *storage) DeletePendingTask(rw basedb.ReadWriter, block uint64, index uint32) error {
+	return s.db.Using(rw).Delete(storagePrefix, pendingTaskKey(block, index))
+}
+
+func (s *storage) dropPendingTasks() error {
+	return s.db.DropPrefix(append(storagePrefix, tasksPrefix...))
+}
+
+// pendingTaskKey returns the key of a pending task, which sorts by the order the tasks were issued in.
+func pendingTaskKey(block uint64, index uint32) []byte {
+	key := binary.BigEndian.AppendUint64(append([]byte{}, tasksPrefix...), block)
+	return binary.BigEndian.AppendUint32(key, index)
+}
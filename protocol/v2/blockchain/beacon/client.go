@@ -59,7 +59,21 @@ type BeaconNode interface {
 type Options struct {
 	Context        context.Context
 	Network        Network
-	BeaconNodeAddr string `yaml:"BeaconNodeAddr" env:"BEACON_NODE_ADDR" env-required:"true"`
+	BeaconNodeAddr string `yaml:"BeaconNodeAddr" env:"BEACON_NODE_ADDR" env-required:"true" env-description:"Beacon node address, or a comma-separated list of addresses to fail over between"`
 	Graffiti       []byte
 	GasLimit       uint64
 }
+
+// ProposerConfig provides the locally configured proposer settings of validators,
+// which override the fee recipient registered in the contract and the node-wide gas limit and builder settings.
+type ProposerConfig interface {
+	// FeeRecipient returns the fee recipient of the validator, if it's overridden.
+	FeeRecipient(pubKey []byte) (bellatrix.ExecutionAddress, bool)
+	// GasLimit returns the gas limit of the validator, if it's overridden.
+	GasLimit(pubKey []byte) (uint64, bool)
+	// BuilderEnabled returns whether the validator proposes blocks from external builders, if it's overridden.
+	BuilderEnabled(pubKey []byte) (bool, bool)
+	// BuilderMinBid returns the minimum value in gwei of the block of an external builder for the validator to propose it,
+	// if it's set.
+	BuilderMinBid(pubKey []byte) (uint64, bool)
+}
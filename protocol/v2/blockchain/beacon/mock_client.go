@@ -686,3 +686,86 @@ func (mr *MockBeaconNodeMockRecorder) SyncCommitteeSubnetID(index interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncCommitteeSubnetID", reflect.TypeOf((*MockBeaconNode)(nil).SyncCommitteeSubnetID), index)
 }
+
+// MockProposerConfig is a mock of ProposerConfig interface.
+type MockProposerConfig struct {
+	ctrl     *gomock.Controller
+	recorder *MockProposerConfigMockRecorder
+}
+
+// MockProposerConfigMockRecorder is the mock recorder for MockProposerConfig.
+type MockProposerConfigMockRecorder struct {
+	mock *MockProposerConfig
+}
+
+// NewMockProposerConfig creates a new mock instance.
+func NewMockProposerConfig(ctrl *gomock.Controller) *MockProposerConfig {
+	mock := &MockProposerConfig{ctrl: ctrl}
+	mock.recorder = &MockProposerConfigMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProposerConfig) EXPECT() *MockProposerConfigMockRecorder {
+	return m.recorder
+}
+
+// BuilderEnabled mocks base method.
+func (m *MockProposerConfig) BuilderEnabled(pubKey []byte) (bool, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuilderEnabled", pubKey)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// BuilderEnabled indicates an expected call of BuilderEnabled.
+func (mr *MockProposerConfigMockRecorder) BuilderEnabled(pubKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuilderEnabled", reflect.TypeOf((*MockProposerConfig)(nil).BuilderEnabled), pubKey)
+}
+
+// BuilderMinBid mocks base method.
+func (m *MockProposerConfig) BuilderMinBid(pubKey []byte) (uint64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuilderMinBid", pubKey)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// BuilderMinBid indicates an expected call of BuilderMinBid.
+func (mr *MockProposerConfigMockRecorder) BuilderMinBid(pubKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuilderMinBid", reflect.TypeOf((*MockProposerConfig)(nil).BuilderMinBid), pubKey)
+}
+
+// FeeRecipient mocks base method.
+func (m *MockProposerConfig) FeeRecipient(pubKey []byte) (bellatrix.ExecutionAddress, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FeeRecipient", pubKey)
+	ret0, _ := ret[0].(bellatrix.ExecutionAddress)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// FeeRecipient indicates an expected call of FeeRecipient.
+func (mr *MockProposerConfigMockRecorder) FeeRecipient(pubKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeeRecipient", reflect.TypeOf((*MockProposerConfig)(nil).FeeRecipient), pubKey)
+}
+
+// GasLimit mocks base method.
+func (m *MockProposerConfig) GasLimit(pubKey []byte) (uint64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasLimit", pubKey)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GasLimit indicates an expected call of GasLimit.
+func (mr *MockProposerConfigMockRecorder) GasLimit(pubKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasLimit", reflect.TypeOf((*MockProposerConfig)(nil).GasLimit), pubKey)
+}
@@ -1,10 +1,13 @@
 package instance
 
 import (
+	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/monitoring/tracing"
 )
 
 var CutoffRound = 15 // stop processing instances after 8*2+120*6 = 14.2 min (~ 2 epochs)
@@ -17,6 +20,9 @@ func (i *Instance) UponRoundTimeout(logger *zap.Logger) error {
 	newRound := i.State.Round + 1
 	logger.Debug("⌛ round timed out", fields.Round(newRound))
 
+	msgID := spectypes.MessageIDFromBytes(i.State.ID)
+	tracing.DutyEvent(msgID.GetPubKey(), msgID.GetRoleType(), "round timeout", attribute.Int64("ssv.round", int64(newRound)))
+
 	// TODO: previously this was done outside of a defer, which caused the
 	// round to be bumped before the round change message was created & broadcasted.
 	// Remember to track the impact of this change and revert/modify if necessary.
@@ -32,6 +32,10 @@ type InstanceStore interface {
 	// GetInstancesInRange returns historical instances in the given range.
 	GetInstancesInRange(identifier []byte, from specqbft.Height, to specqbft.Height) ([]*StoredInstance, error)
 
+	// GetInstances returns up to limit historical instances in the given range, in ascending order of height.
+	// A limit of 0 returns all of them.
+	GetInstances(identifier []byte, from specqbft.Height, to specqbft.Height, limit int) ([]*StoredInstance, error)
+
 	// SaveInstance updates/inserts the given instance to it's identifier's history.
 	SaveInstance(instance *StoredInstance) error
 
@@ -46,6 +50,13 @@ type InstanceStore interface {
 
 	// CleanAllInstances removes all historical and highest instances for the given identifier.
 	CleanAllInstances(logger *zap.Logger, msgID []byte) error
+
+	// PruneHistory removes the historical instances of all identifiers below the given height,
+	// and returns the number and encoded size of the instances removed.
+	PruneHistory(below specqbft.Height) (int, int64, error)
+
+	// WalkHistory calls handler with the height and encoded size of every historical instance, of all identifiers.
+	WalkHistory(handler func(height specqbft.Height, size int) error) error
 }
 
 // QBFTStore is the store used by QBFT components
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"fmt"
+	"time"
 
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	spectypes "github.com/bloxapp/ssv-spec/types"
@@ -22,6 +23,9 @@ type DecodedSSVMessage struct {
 
 	// Body is the decoded Data.
 	Body interface{} // *SignedMessage | *SignedPartialSignatureMessage | *EventMsg
+
+	// EnqueuedAt is the time the message was pushed to the queue.
+	EnqueuedAt time.Time
 }
 
 // DecodeSSVMessage decodes an SSVMessage and returns a DecodedSSVMessage.
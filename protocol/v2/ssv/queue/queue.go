@@ -60,10 +60,12 @@ func NewDefault() Queue {
 }
 
 func (q *priorityQueue) Push(msg *DecodedSSVMessage) {
+	msg.EnqueuedAt = time.Now()
 	q.inbox <- msg
 }
 
 func (q *priorityQueue) TryPush(msg *DecodedSSVMessage) bool {
+	msg.EnqueuedAt = time.Now()
 	select {
 	case q.inbox <- msg:
 		return true
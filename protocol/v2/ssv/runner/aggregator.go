@@ -205,6 +205,7 @@ func (r *AggregatorRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 
 		if err := r.GetBeaconNode().SubmitSignedAggregateSelectionProof(msg); err != nil {
 			r.metrics.RoleSubmissionFailed()
+			r.BaseRunner.trackSubmission(err)
 			return errors.Wrap(err, "could not submit to Beacon chain reconstructed signed aggregate")
 		}
 
@@ -214,6 +215,7 @@ func (r *AggregatorRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 
 		logger.Debug("✅ successful submitted aggregate")
 	}
+	r.BaseRunner.trackSubmission(nil)
 	r.GetState().Finished = true
 
 	return nil
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"math/big"
+)
+
+// Sources of the proposed blocks.
+const (
+	BlockSourceBuilder = "builder"
+	BlockSourceLocal   = "local"
+)
+
+// Reasons for choosing the source of a proposed block.
+const (
+	BlockReasonBuilderHigher     = "builder_higher"
+	BlockReasonLocalHigher       = "local_higher"
+	BlockReasonBelowMinBid       = "below_min_bid"
+	BlockReasonUnknownBid        = "unknown_bid"
+	BlockReasonUnknownLocalValue = "unknown_local_value"
+	BlockReasonBuilderFailed     = "builder_failed"
+	BlockReasonLocalFailed       = "local_failed"
+)
+
+// BlockChoice is the source of the block chosen for a proposal, and the reason it was chosen.
+type BlockChoice struct {
+	Builder bool
+	Reason  string
+}
+
+// Source returns the source of the chosen block.
+func (c BlockChoice) Source() string {
+	if c.Builder {
+		return BlockSourceBuilder
+	}
+	return BlockSourceLocal
+}
+
+// BuilderPolicy chooses between the block of an external builder and the locally built block of a proposal,
+// by the values of their execution payloads to the proposer.
+type BuilderPolicy struct {
+	// MinBid is the minimum value in wei of the block of a builder to propose it, or nil for no minimum.
+	MinBid *big.Int
+}
+
+// Choose chooses between the block of an external builder and the locally built block by their values,
+// any of which is nil if the beacon node didn't report it.
+// The block of the builder is chosen unless its bid is below the minimum or not higher than the local value.
+func (p BuilderPolicy) Choose(builderValue, localValue *big.Int) BlockChoice {
+	if builderValue == nil {
+		if p.MinBid != nil && p.MinBid.Sign() > 0 {
+			return BlockChoice{Builder: false, Reason: BlockReasonUnknownBid}
+		}
+		return BlockChoice{Builder: true, Reason: BlockReasonUnknownBid}
+	}
+	if p.MinBid != nil && builderValue.Cmp(p.MinBid) < 0 {
+		return BlockChoice{Builder: false, Reason: BlockReasonBelowMinBid}
+	}
+	if localValue == nil {
+		return BlockChoice{Builder: true, Reason: BlockReasonUnknownLocalValue}
+	}
+	if localValue.Cmp(builderValue) >= 0 {
+		return BlockChoice{Builder: false, Reason: BlockReasonLocalHigher}
+	}
+	return BlockChoice{Builder: true, Reason: BlockReasonBuilderHigher}
+}
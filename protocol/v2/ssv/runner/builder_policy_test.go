@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		minBid       *big.Int
+		builderValue *big.Int
+		localValue   *big.Int
+		expected     BlockChoice
+	}{
+		{
+			name:         "builder higher",
+			builderValue: big.NewInt(200),
+			localValue:   big.NewInt(100),
+			expected:     BlockChoice{Builder: true, Reason: BlockReasonBuilderHigher},
+		},
+		{
+			name:         "local higher",
+			builderValue: big.NewInt(100),
+			localValue:   big.NewInt(200),
+			expected:     BlockChoice{Builder: false, Reason: BlockReasonLocalHigher},
+		},
+		{
+			name:         "equal values",
+			builderValue: big.NewInt(100),
+			localValue:   big.NewInt(100),
+			expected:     BlockChoice{Builder: false, Reason: BlockReasonLocalHigher},
+		},
+		{
+			name:         "below min bid",
+			minBid:       big.NewInt(300),
+			builderValue: big.NewInt(200),
+			localValue:   big.NewInt(100),
+			expected:     BlockChoice{Builder: false, Reason: BlockReasonBelowMinBid},
+		},
+		{
+			name:         "at min bid",
+			minBid:       big.NewInt(200),
+			builderValue: big.NewInt(200),
+			localValue:   big.NewInt(100),
+			expected:     BlockChoice{Builder: true, Reason: BlockReasonBuilderHigher},
+		},
+		{
+			name:       "unknown bid",
+			localValue: big.NewInt(100),
+			expected:   BlockChoice{Builder: true, Reason: BlockReasonUnknownBid},
+		},
+		{
+			name:       "unknown bid with min bid",
+			minBid:     big.NewInt(200),
+			localValue: big.NewInt(100),
+			expected:   BlockChoice{Builder: false, Reason: BlockReasonUnknownBid},
+		},
+		{
+			name:         "unknown local value",
+			builderValue: big.NewInt(100),
+			expected:     BlockChoice{Builder: true, Reason: BlockReasonUnknownLocalValue},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choice := BuilderPolicy{MinBid: tt.minBid}.Choose(tt.builderValue, tt.localValue)
+			require.Equal(t, tt.expected, choice)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"math/big"
 	"time"
 
 	"go.uber.org/zap"
@@ -64,6 +65,15 @@ var (
 		Name: "ssv_instances_decided",
 		Help: "Number of decided QBFT instances",
 	}, []string{"role"})
+	metricsProposerBlockChoice = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_validator_proposer_block_choice",
+		Help: "Number of block proposals by the source of the chosen block and the reason",
+	}, []string{"source", "reason"})
+	metricsProposerBlockValue = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv_validator_proposer_block_value_eth",
+		Help:    "Value of the execution payload of the chosen block proposals (ETH)",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 5},
+	}, []string{"source"})
 )
 
 func init() {
@@ -119,6 +129,16 @@ func NewConsensusMetrics(role spectypes.BeaconRole) ConsensusMetrics {
 	}
 }
 
+// RecordBlockChoice counts the choice of a block proposal by its source and reason,
+// and observes the value in wei of its execution payload, if known.
+func (cm *ConsensusMetrics) RecordBlockChoice(source, reason string, value *big.Int) {
+	metricsProposerBlockChoice.WithLabelValues(source, reason).Inc()
+	if value != nil {
+		eth, _ := new(big.Float).Quo(new(big.Float).SetInt(value), big.NewFloat(1e18)).Float64()
+		metricsProposerBlockValue.WithLabelValues(source).Observe(eth)
+	}
+}
+
 // StartPreConsensus stores pre-consensus start time.
 func (cm *ConsensusMetrics) StartPreConsensus() {
 	if cm != nil {
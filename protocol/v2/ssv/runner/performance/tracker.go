@@ -0,0 +1,250 @@
+package performance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+)
+
+// Outcome is the outcome of a duty.
+type Outcome string
+
+const (
+	// OutcomePending means the duty is still running.
+	OutcomePending Outcome = "pending"
+	// OutcomeSubmitted means the duty was submitted to the beacon node.
+	OutcomeSubmitted Outcome = "submitted"
+	// OutcomeFailed means the submission to the beacon node failed.
+	OutcomeFailed Outcome = "failed"
+	// OutcomeSkipped means there was nothing to submit, for example because
+	// the validator wasn't selected as a sync committee aggregator.
+	OutcomeSkipped Outcome = "skipped"
+	// OutcomeMissed means the duty wasn't submitted in time,
+	// for example because consensus or post-consensus didn't reach a quorum.
+	OutcomeMissed Outcome = "missed"
+)
+
+// DefaultRetention is the default number of epochs for which duties are kept.
+const DefaultRetention = phase0.Epoch(32)
+
+// Duty is the record of a single duty execution.
+type Duty struct {
+	PubKey              []byte
+	Role                spectypes.BeaconRole
+	Slot                phase0.Slot
+	Started             time.Time
+	Round               specqbft.Round
+	Decided             time.Time
+	PostConsensusQuorum time.Time
+	Submitted           time.Time
+	Outcome             Outcome
+	Error               string
+}
+
+type dutyKey struct {
+	pubKey string
+	role   spectypes.BeaconRole
+	slot   phase0.Slot
+}
+
+// Filter selects duties to return from Tracker.Duties. Empty fields match everything.
+type Filter struct {
+	PubKeys   [][]byte
+	Roles     []spectypes.BeaconRole
+	FromEpoch phase0.Epoch
+	ToEpoch   phase0.Epoch
+}
+
+// Tracker records the progress and outcome of the duties executed by the runners,
+// keeping them in memory for a limited number of epochs.
+// A nil Tracker is valid and records nothing.
+type Tracker struct {
+	network   beacon.BeaconNetwork
+	retention phase0.Epoch
+
+	mu          sync.RWMutex
+	duties      map[dutyKey]*Duty
+	prunedEpoch phase0.Epoch
+}
+
+// NewTracker creates a new Tracker which keeps duties for the given number of epochs.
+func NewTracker(network beacon.BeaconNetwork, retention phase0.Epoch) *Tracker {
+	if retention == 0 {
+		retention = DefaultRetention
+	}
+	return &Tracker{
+		network:   network,
+		retention: retention,
+		duties:    make(map[dutyKey]*Duty),
+	}
+}
+
+// StartDuty records the start of a duty.
+func (t *Tracker) StartDuty(pubKey []byte, duty *spectypes.Duty) {
+	if t == nil || duty == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.duties[t.key(pubKey, duty.Type, duty.Slot)] = &Duty{
+		PubKey:  pubKey,
+		Role:    duty.Type,
+		Slot:    duty.Slot,
+		Started: time.Now(),
+		Outcome: OutcomePending,
+	}
+	t.prune()
+}
+
+// Decided records the consensus decision of a duty and the round it was decided at.
+func (t *Tracker) Decided(pubKey []byte, role spectypes.BeaconRole, slot phase0.Slot, round specqbft.Round) {
+	t.update(pubKey, role, slot, func(d *Duty) {
+		d.Decided = time.Now()
+		d.Round = round
+	})
+}
+
+// PostConsensusQuorum records the collection of a quorum of post-consensus signatures of a duty.
+func (t *Tracker) PostConsensusQuorum(pubKey []byte, role spectypes.BeaconRole, slot phase0.Slot) {
+	t.update(pubKey, role, slot, func(d *Duty) {
+		if d.PostConsensusQuorum.IsZero() {
+			d.PostConsensusQuorum = time.Now()
+		}
+	})
+}
+
+// Submitted records the submission of a duty to the beacon node, which failed if err is not nil.
+func (t *Tracker) Submitted(pubKey []byte, role spectypes.BeaconRole, slot phase0.Slot, err error) {
+	t.update(pubKey, role, slot, func(d *Duty) {
+		if err != nil {
+			d.Outcome = OutcomeFailed
+			d.Error = err.Error()
+			return
+		}
+		d.Submitted = time.Now()
+		d.Outcome = OutcomeSubmitted
+		d.Error = ""
+	})
+}
+
+// Skipped records that a duty had nothing to submit.
+func (t *Tracker) Skipped(pubKey []byte, role spectypes.BeaconRole, slot phase0.Slot) {
+	t.update(pubKey, role, slot, func(d *Duty) {
+		d.Outcome = OutcomeSkipped
+	})
+}
+
+// Duties returns copies of the recorded duties matching the filter, ordered by slot.
+// Duties still pending an epoch after their slot are reported as missed,
+// since by then no submission could be included on chain anymore.
+func (t *Tracker) Duties(filter Filter) []Duty {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	currentSlot := t.network.EstimatedCurrentSlot()
+	deadline := phase0.Slot(t.network.SlotsPerEpoch())
+	var duties []Duty
+	for _, d := range t.duties {
+		if !filter.matches(t.network.EstimatedEpochAtSlot(d.Slot), d) {
+			continue
+		}
+		duty := *d
+		if duty.Outcome == OutcomePending && currentSlot > duty.Slot+deadline {
+			duty.Outcome = OutcomeMissed
+		}
+		duties = append(duties, duty)
+	}
+
+	sort.Slice(duties, func(i, j int) bool {
+		if duties[i].Slot != duties[j].Slot {
+			return duties[i].Slot < duties[j].Slot
+		}
+		if duties[i].Role != duties[j].Role {
+			return duties[i].Role < duties[j].Role
+		}
+		return bytes.Compare(duties[i].PubKey, duties[j].PubKey) < 0
+	})
+	return duties
+}
+
+func (t *Tracker) update(pubKey []byte, role spectypes.BeaconRole, slot phase0.Slot, f func(d *Duty)) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if d, ok := t.duties[t.key(pubKey, role, slot)]; ok {
+		f(d)
+	}
+}
+
+// prune removes duties older than the retention. Must be called with the lock held.
+func (t *Tracker) prune() {
+	currentEpoch := t.network.EstimatedCurrentEpoch()
+	if currentEpoch <= t.retention || currentEpoch == t.prunedEpoch {
+		return
+	}
+	t.prunedEpoch = currentEpoch
+
+	oldest := currentEpoch - t.retention
+	for key := range t.duties {
+		if t.network.EstimatedEpochAtSlot(key.slot) < oldest {
+			delete(t.duties, key)
+		}
+	}
+}
+
+func (t *Tracker) key(pubKey []byte, role spectypes.BeaconRole, slot phase0.Slot) dutyKey {
+	return dutyKey{
+		pubKey: hex.EncodeToString(pubKey),
+		role:   role,
+		slot:   slot,
+	}
+}
+
+func (f Filter) matches(epoch phase0.Epoch, d *Duty) bool {
+	if epoch < f.FromEpoch || (f.ToEpoch != 0 && epoch > f.ToEpoch) {
+		return false
+	}
+	if len(f.Roles) > 0 {
+		found := false
+		for _, role := range f.Roles {
+			if role == d.Role {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.PubKeys) > 0 {
+		found := false
+		for _, pubKey := range f.PubKeys {
+			if bytes.Equal(pubKey, d.PubKey) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
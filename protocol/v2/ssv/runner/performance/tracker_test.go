@@ -0,0 +1,93 @@
+package performance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+)
+
+func TestTracker(t *testing.T) {
+	network := beacon.NewNetwork(spectypes.PraterNetwork)
+	tracker := NewTracker(network, 4)
+
+	pk1 := []byte{1}
+	pk2 := []byte{2}
+	currentSlot := network.EstimatedCurrentSlot()
+	lateSlot := currentSlot - phase0.Slot(network.SlotsPerEpoch()) - 1
+
+	// Submitted attestation.
+	tracker.StartDuty(pk1, &spectypes.Duty{Type: spectypes.BNRoleAttester, Slot: currentSlot})
+	tracker.Decided(pk1, spectypes.BNRoleAttester, currentSlot, 2)
+	tracker.PostConsensusQuorum(pk1, spectypes.BNRoleAttester, currentSlot)
+	tracker.Submitted(pk1, spectypes.BNRoleAttester, currentSlot, nil)
+
+	// Failed proposal.
+	tracker.StartDuty(pk2, &spectypes.Duty{Type: spectypes.BNRoleProposer, Slot: currentSlot})
+	tracker.Decided(pk2, spectypes.BNRoleProposer, currentSlot, 1)
+	tracker.Submitted(pk2, spectypes.BNRoleProposer, currentSlot, errors.New("beacon node is down"))
+
+	// Pending and missed attestations.
+	tracker.StartDuty(pk2, &spectypes.Duty{Type: spectypes.BNRoleAttester, Slot: currentSlot})
+	tracker.StartDuty(pk2, &spectypes.Duty{Type: spectypes.BNRoleAttester, Slot: lateSlot})
+
+	// Updates of unknown duties are ignored.
+	tracker.Submitted(pk1, spectypes.BNRoleAggregator, currentSlot, nil)
+
+	duties := tracker.Duties(Filter{})
+	require.Len(t, duties, 4)
+
+	require.Equal(t, lateSlot, duties[0].Slot)
+	require.Equal(t, OutcomeMissed, duties[0].Outcome)
+
+	require.Equal(t, spectypes.BNRoleAttester, duties[1].Role)
+	require.Equal(t, pk1, duties[1].PubKey)
+	require.Equal(t, OutcomeSubmitted, duties[1].Outcome)
+	require.EqualValues(t, 2, duties[1].Round)
+	require.False(t, duties[1].Decided.IsZero())
+	require.False(t, duties[1].PostConsensusQuorum.IsZero())
+	require.False(t, duties[1].Submitted.IsZero())
+
+	require.Equal(t, spectypes.BNRoleAttester, duties[2].Role)
+	require.Equal(t, pk2, duties[2].PubKey)
+	require.Equal(t, OutcomePending, duties[2].Outcome)
+
+	require.Equal(t, spectypes.BNRoleProposer, duties[3].Role)
+	require.Equal(t, OutcomeFailed, duties[3].Outcome)
+	require.Equal(t, "beacon node is down", duties[3].Error)
+
+	t.Run("filter", func(t *testing.T) {
+		duties := tracker.Duties(Filter{PubKeys: [][]byte{pk2}, Roles: []spectypes.BeaconRole{spectypes.BNRoleAttester}})
+		require.Len(t, duties, 2)
+
+		currentEpoch := network.EstimatedEpochAtSlot(currentSlot)
+		duties = tracker.Duties(Filter{FromEpoch: currentEpoch})
+		require.Len(t, duties, 3)
+
+		duties = tracker.Duties(Filter{ToEpoch: currentEpoch - 1})
+		require.Len(t, duties, 1)
+		require.Equal(t, lateSlot, duties[0].Slot)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		oldSlot := network.GetEpochFirstSlot(network.EstimatedCurrentEpoch() - 5)
+		tracker.prunedEpoch = 0
+		tracker.duties[tracker.key(pk1, spectypes.BNRoleAttester, oldSlot)] = &Duty{Slot: oldSlot}
+
+		tracker.StartDuty(pk1, &spectypes.Duty{Type: spectypes.BNRoleAggregator, Slot: currentSlot})
+		for _, duty := range tracker.Duties(Filter{}) {
+			require.NotEqual(t, oldSlot, duty.Slot)
+		}
+	})
+
+	t.Run("nil tracker", func(t *testing.T) {
+		var tracker *Tracker
+		tracker.StartDuty(pk1, &spectypes.Duty{Type: spectypes.BNRoleAttester, Slot: currentSlot})
+		tracker.Submitted(pk1, spectypes.BNRoleAttester, currentSlot, nil)
+		require.Empty(t, tracker.Duties(Filter{}))
+	})
+}
@@ -3,6 +3,8 @@ package runner
 import (
 	"crypto/sha256"
 	"encoding/json"
+	"math/big"
+	"sync"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/api"
@@ -20,6 +22,7 @@ import (
 
 	"github.com/bloxapp/ssv/beacon/goclient"
 	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	"github.com/bloxapp/ssv/protocol/v2/qbft/controller"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/runner/metrics"
 )
@@ -28,6 +31,8 @@ type ProposerRunner struct {
 	BaseRunner *BaseRunner
 	// ProducesBlindedBlocks is true when the runner will only produce blinded blocks
 	ProducesBlindedBlocks bool
+	// ProposerConfig overrides ProducesBlindedBlocks for the validator, if set
+	ProposerConfig beacon.ProposerConfig `json:"-"`
 
 	beacon   specssv.BeaconNode
 	network  specssv.Network
@@ -105,7 +110,13 @@ func (r *ProposerRunner) ProcessPreConsensus(logger *zap.Logger, signedMsg *spec
 	var ver spec.DataVersion
 	var obj ssz.Marshaler
 	var start = time.Now()
-	if r.ProducesBlindedBlocks {
+	if provider, ok := r.GetBeaconNode().(goclient.BlockValueProvider); ok && r.producesBlindedBlocks() {
+		obj, ver, err = r.chooseBlock(logger, provider, duty.Slot, fullSig)
+		if err != nil {
+			logger.Warn("could not compare builder and local blocks, falling back to blinded block", zap.Error(err))
+		}
+	}
+	if obj == nil && r.producesBlindedBlocks() {
 		// get block data
 		obj, ver, err = r.GetBeaconNode().GetBlindedBeaconBlock(duty.Slot, r.GetShare().Graffiti, fullSig)
 		if err != nil {
@@ -123,7 +134,7 @@ func (r *ProposerRunner) ProcessPreConsensus(logger *zap.Logger, signedMsg *spec
 				return errors.Wrap(err, "failed to get blinded beacon block")
 			}
 		}
-	} else {
+	} else if obj == nil {
 		// get block data
 		obj, ver, err = r.GetBeaconNode().GetBeaconBlock(duty.Slot, r.GetShare().Graffiti, fullSig)
 		if err != nil {
@@ -251,6 +262,7 @@ func (r *ProposerRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *spe
 
 			if err := r.GetBeaconNode().SubmitBlindedBeaconBlock(vBlindedBlk, specSig); err != nil {
 				r.metrics.RoleSubmissionFailed()
+				r.BaseRunner.trackSubmission(err)
 
 				return errors.Wrap(err, "could not submit to Beacon chain reconstructed signed blinded Beacon block")
 			}
@@ -263,6 +275,7 @@ func (r *ProposerRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *spe
 
 			if err := r.GetBeaconNode().SubmitBeaconBlock(vBlk, specSig); err != nil {
 				r.metrics.RoleSubmissionFailed()
+				r.BaseRunner.trackSubmission(err)
 
 				return errors.Wrap(err, "could not submit to Beacon chain reconstructed signed Beacon block")
 			}
@@ -282,10 +295,84 @@ func (r *ProposerRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *spe
 			zap.Duration("took", time.Since(start)),
 			zap.NamedError("summarize_err", summarizeErr))
 	}
+	r.BaseRunner.trackSubmission(nil)
 	r.GetState().Finished = true
 	return nil
 }
 
+// producesBlindedBlocks returns true if the runner should produce a blinded block for the validator
+func (r *ProposerRunner) producesBlindedBlocks() bool {
+	if r.ProposerConfig != nil {
+		if enabled, ok := r.ProposerConfig.BuilderEnabled(r.BaseRunner.Share.ValidatorPubKey); ok {
+			return enabled
+		}
+	}
+	return r.ProducesBlindedBlocks
+}
+
+// builderPolicy returns the builder policy of the validator.
+func (r *ProposerRunner) builderPolicy() BuilderPolicy {
+	var policy BuilderPolicy
+	if r.ProposerConfig != nil {
+		if minBid, ok := r.ProposerConfig.BuilderMinBid(r.BaseRunner.Share.ValidatorPubKey); ok {
+			policy.MinBid = new(big.Int).Mul(new(big.Int).SetUint64(minBid), big.NewInt(1e9))
+		}
+	}
+	return policy
+}
+
+// chooseBlock gets both the block of an external builder and the locally built block, and chooses between them
+// by the builder policy of the validator, falling back to either block if the other isn't available.
+// Only the block chosen by the leader of the round is proposed. The other operators don't check the choice
+// against their own policy, and their key managers sign the decided block whether it's blinded or not,
+// so the committee agrees on a block even if the operators' policies or builder settings differ.
+func (r *ProposerRunner) chooseBlock(logger *zap.Logger, provider goclient.BlockValueProvider, slot phase0.Slot, randao []byte) (ssz.Marshaler, spec.DataVersion, error) {
+	type proposal struct {
+		block   ssz.Marshaler
+		version spec.DataVersion
+		value   *big.Int
+		err     error
+	}
+	var builder, local proposal
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		builder.block, builder.version, builder.value, builder.err = provider.GetBlindedBeaconBlockWithValue(slot, r.GetShare().Graffiti, randao)
+	}()
+	go func() {
+		defer wg.Done()
+		local.block, local.version, local.value, local.err = provider.GetBeaconBlockWithValue(slot, r.GetShare().Graffiti, randao)
+	}()
+	wg.Wait()
+
+	var choice BlockChoice
+	switch {
+	case builder.err != nil && local.err != nil:
+		return nil, 0, errors.Errorf("failed to get beacon block from builder (%v) or locally (%v)", builder.err, local.err)
+	case builder.err != nil:
+		choice = BlockChoice{Builder: false, Reason: BlockReasonBuilderFailed}
+	case local.err != nil:
+		choice = BlockChoice{Builder: true, Reason: BlockReasonLocalFailed}
+	default:
+		choice = r.builderPolicy().Choose(builder.value, local.value)
+	}
+
+	chosen := local
+	if choice.Builder {
+		chosen = builder
+	}
+	r.metrics.RecordBlockChoice(choice.Source(), choice.Reason, chosen.value)
+	logger.Debug("chose block proposal",
+		zap.String("source", choice.Source()),
+		zap.String("reason", choice.Reason),
+		zap.Stringer("builder_value", builder.value),
+		zap.Stringer("local_value", local.value),
+		zap.NamedError("builder_err", builder.err),
+		zap.NamedError("local_err", local.err))
+	return chosen.block, chosen.version, nil
+}
+
 // decidedBlindedBlock returns true if decided value has a blinded block, false if regular block
 // WARNING!! should be called after decided only
 func (r *ProposerRunner) decidedBlindedBlock() bool {
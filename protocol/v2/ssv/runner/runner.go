@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"context"
 	"sync"
 
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
@@ -9,9 +10,12 @@ import (
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	ssz "github.com/ferranbt/fastssz"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/monitoring/tracing"
 	"github.com/bloxapp/ssv/protocol/v2/qbft/controller"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/runner/performance"
 )
 
 type Getters interface {
@@ -54,7 +58,8 @@ type BaseRunner struct {
 	BeaconRoleType spectypes.BeaconRole
 
 	// implementation vars
-	TimeoutF TimeoutF `json:"-"`
+	TimeoutF    TimeoutF             `json:"-"`
+	DutyTracker *performance.Tracker `json:"-"`
 
 	// highestDecidedSlot holds the highest decided duty slot and gets updated after each decided is reached
 	highestDecidedSlot spec.Slot
@@ -74,6 +79,44 @@ func (b *BaseRunner) baseSetupForNewDuty(duty *spectypes.Duty) {
 	b.mtx.Lock() // writes to b.State
 	b.State = state
 	b.mtx.Unlock()
+
+	b.DutyTracker.StartDuty(b.Share.ValidatorPubKey, duty)
+	tracing.StartDuty(context.Background(), b.Share.ValidatorPubKey, duty)
+	if hasPreConsensus(duty.Type) {
+		tracing.StartStage(b.Share.ValidatorPubKey, duty.Type, tracing.StagePreConsensus)
+	}
+}
+
+// hasPreConsensus returns true if duties of the given role start with a pre-consensus stage.
+func hasPreConsensus(role spectypes.BeaconRole) bool {
+	switch role {
+	case spectypes.BNRoleAttester, spectypes.BNRoleSyncCommittee:
+		return false
+	default:
+		return true
+	}
+}
+
+// trackSubmission records the outcome of submitting the running duty to the beacon node.
+func (b *BaseRunner) trackSubmission(err error) {
+	if duty := b.State.StartingDuty; duty != nil {
+		b.DutyTracker.Submitted(b.Share.ValidatorPubKey, duty.Type, duty.Slot, err)
+
+		outcome := performance.OutcomeSubmitted
+		if err != nil {
+			outcome = performance.OutcomeFailed
+		}
+		tracing.EndStage(b.Share.ValidatorPubKey, duty.Type, tracing.StageBeaconSubmission)
+		tracing.EndDuty(b.Share.ValidatorPubKey, duty.Type, string(outcome), err)
+	}
+}
+
+// trackSkipped records that the running duty had nothing to submit.
+func (b *BaseRunner) trackSkipped() {
+	if duty := b.State.StartingDuty; duty != nil {
+		b.DutyTracker.Skipped(b.Share.ValidatorPubKey, duty.Type, duty.Slot)
+		tracing.EndDuty(b.Share.ValidatorPubKey, duty.Type, string(performance.OutcomeSkipped), nil)
+	}
 }
 
 func NewBaseRunner(
@@ -119,6 +162,9 @@ func (b *BaseRunner) basePreConsensusMsgProcessing(runner Runner, signedMsg *spe
 	}
 
 	hasQuorum, roots, err := b.basePartialSigMsgProcessing(signedMsg, b.State.PreConsensusContainer)
+	if hasQuorum {
+		tracing.EndStage(b.Share.ValidatorPubKey, b.BeaconRoleType, tracing.StagePreConsensus)
+	}
 	return hasQuorum, roots, errors.Wrap(err, "could not process pre-consensus partial signature msg")
 }
 
@@ -179,6 +225,10 @@ func (b *BaseRunner) baseConsensusMsgProcessing(logger *zap.Logger, runner Runne
 	}
 
 	runner.GetBaseRunner().State.DecidedValue = decidedValue
+	b.DutyTracker.Decided(b.Share.ValidatorPubKey, decidedValue.Duty.Type, decidedValue.Duty.Slot, decidedMsg.Message.Round)
+	tracing.EndStage(b.Share.ValidatorPubKey, decidedValue.Duty.Type, tracing.StageConsensus,
+		attribute.Int64("ssv.round", int64(decidedMsg.Message.Round)))
+	tracing.StartStage(b.Share.ValidatorPubKey, decidedValue.Duty.Type, tracing.StagePostConsensus)
 
 	return true, decidedValue, nil
 }
@@ -190,6 +240,11 @@ func (b *BaseRunner) basePostConsensusMsgProcessing(logger *zap.Logger, runner R
 	}
 
 	hasQuorum, roots, err := b.basePartialSigMsgProcessing(signedMsg, b.State.PostConsensusContainer)
+	if hasQuorum && b.State.DecidedValue != nil {
+		b.DutyTracker.PostConsensusQuorum(b.Share.ValidatorPubKey, b.State.DecidedValue.Duty.Type, b.State.DecidedValue.Duty.Slot)
+		tracing.EndStage(b.Share.ValidatorPubKey, b.State.DecidedValue.Duty.Type, tracing.StagePostConsensus)
+		tracing.StartStage(b.Share.ValidatorPubKey, b.State.DecidedValue.Duty.Type, tracing.StageBeaconSubmission)
+	}
 	return hasQuorum, roots, errors.Wrap(err, "could not process post-consensus partial signature msg")
 }
 
@@ -249,6 +304,8 @@ func (b *BaseRunner) decide(logger *zap.Logger, runner Runner, input *spectypes.
 
 	}
 
+	tracing.StartStage(b.Share.ValidatorPubKey, input.Duty.Type, tracing.StageConsensus,
+		attribute.Int64("ssv.height", int64(input.Duty.Slot)))
 	if err := runner.GetBaseRunner().QBFTController.StartNewInstance(logger,
 		specqbft.Height(input.Duty.Slot),
 		byts,
@@ -10,6 +10,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// errWrongSigningRoot is returned when a partial signature message signs other roots than expected.
+var errWrongSigningRoot = errors.New("wrong signing root")
+
 func (b *BaseRunner) ValidatePreConsensusMsg(runner Runner, signedMsg *spectypes.SignedPartialSignatureMessage) error {
 	if !b.hasRunningDuty() {
 		return errors.New("no running duty")
@@ -120,7 +123,7 @@ func (b *BaseRunner) verifyExpectedRoot(runner Runner, signedMsg *spectypes.Sign
 	// verify roots
 	for i, r := range sortedRoots {
 		if !bytes.Equal(sortedExpectedRoots[i][:], r[:]) {
-			return errors.New("wrong signing root")
+			return errWrongSigningRoot
 		}
 	}
 	return nil
@@ -110,6 +110,7 @@ func (r *SyncCommitteeAggregatorRunner) ProcessPreConsensus(logger *zap.Logger,
 		subnets = append(subnets, subnet)
 	}
 	if len(selectionProofs) == 0 {
+		r.BaseRunner.trackSkipped()
 		r.GetState().Finished = true
 		return nil
 	}
@@ -256,6 +257,7 @@ func (r *SyncCommitteeAggregatorRunner) ProcessPostConsensus(logger *zap.Logger,
 
 			if err := r.GetBeaconNode().SubmitSignedContributionAndProof(signedContribAndProof); err != nil {
 				r.metrics.RoleSubmissionFailed()
+				r.BaseRunner.trackSubmission(err)
 				return errors.Wrap(err, "could not submit to Beacon chain reconstructed contribution and proof")
 			}
 
@@ -267,6 +269,7 @@ func (r *SyncCommitteeAggregatorRunner) ProcessPostConsensus(logger *zap.Logger,
 			break
 		}
 	}
+	r.BaseRunner.trackSubmission(nil)
 	r.GetState().Finished = true
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/ssv-spec/qbft"
 	specssv "github.com/bloxapp/ssv-spec/ssv"
@@ -14,19 +15,32 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/beacon/goclient"
 	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	"github.com/bloxapp/ssv/protocol/v2/qbft/controller"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/runner/metrics"
 )
 
 type ValidatorRegistrationRunner struct {
 	BaseRunner *BaseRunner
+	// GasLimit is the gas limit to register, or spectypes.DefaultGasLimit if it's 0
+	GasLimit uint64 `json:"-"`
+	// ProposerConfig overrides the fee recipient and gas limit to register, if set
+	ProposerConfig beacon.ProposerConfig `json:"-"`
 
 	beacon   specssv.BeaconNode
 	network  specssv.Network
 	signer   spectypes.KeyManager
 	valCheck qbft.ProposedValueCheckF
 
+	// registration is the validator registration of the running duty, which is computed once per duty,
+	// so that reloading the proposer config can't make the submitted registration differ from the signed one.
+	registration     *v1.ValidatorRegistration
+	registrationDuty *spectypes.Duty
+	// overridden is set if the registration of the running duty is overridden by the proposer config.
+	overridden bool
+
 	metrics metrics.ConsensusMetrics
 }
 
@@ -65,6 +79,11 @@ func (r *ValidatorRegistrationRunner) HasRunningDuty() bool {
 func (r *ValidatorRegistrationRunner) ProcessPreConsensus(logger *zap.Logger, signedMsg *spectypes.SignedPartialSignatureMessage) error {
 	quorum, roots, err := r.BaseRunner.basePreConsensusMsgProcessing(r, signedMsg)
 	if err != nil {
+		if errors.Is(err, errWrongSigningRoot) && r.overridden {
+			logger.Warn("operator signed a different validator registration than the locally overridden one, "+
+				"quorum is only reached if enough operators of the validator configure the same fee recipient and gas limit",
+				fields.OperatorID(signedMsg.Signer))
+		}
 		return errors.Wrap(err, "failed processing validator registration message")
 	}
 
@@ -82,14 +101,24 @@ func (r *ValidatorRegistrationRunner) ProcessPreConsensus(logger *zap.Logger, si
 	specSig := phase0.BLSSignature{}
 	copy(specSig[:], fullSig)
 
-	if err := r.beacon.SubmitValidatorRegistration(r.BaseRunner.Share.ValidatorPubKey, r.BaseRunner.Share.FeeRecipientAddress, specSig); err != nil {
+	// The registration is submitted as it was signed, if the beacon node allows it.
+	registration := r.validatorRegistration()
+	if submitter, ok := r.beacon.(goclient.ValidatorRegistrationSubmitter); ok {
+		err = submitter.SubmitSignedValidatorRegistration(registration, specSig)
+	} else {
+		err = r.beacon.SubmitValidatorRegistration(r.BaseRunner.Share.ValidatorPubKey, registration.FeeRecipient, specSig)
+	}
+	if err != nil {
+		r.BaseRunner.trackSubmission(err)
 		return errors.Wrap(err, "could not submit validator registration")
 	}
 
 	logger.Debug("validator registration submitted successfully",
-		fields.FeeRecipient(r.BaseRunner.Share.FeeRecipientAddress[:]),
+		fields.FeeRecipient(registration.FeeRecipient[:]),
+		zap.Uint64("gas_limit", registration.GasLimit),
 		zap.String("signature", hex.EncodeToString(specSig[:])))
 
+	r.BaseRunner.trackSubmission(nil)
 	r.GetState().Finished = true
 	return nil
 }
@@ -103,11 +132,7 @@ func (r *ValidatorRegistrationRunner) ProcessPostConsensus(logger *zap.Logger, s
 }
 
 func (r *ValidatorRegistrationRunner) expectedPreConsensusRootsAndDomain() ([]ssz.HashRoot, phase0.DomainType, error) {
-	vr, err := r.calculateValidatorRegistration()
-	if err != nil {
-		return nil, spectypes.DomainError, errors.Wrap(err, "could not calculate validator registration")
-	}
-	return []ssz.HashRoot{vr}, spectypes.DomainApplicationBuilder, nil
+	return []ssz.HashRoot{r.validatorRegistration()}, spectypes.DomainApplicationBuilder, nil
 }
 
 // expectedPostConsensusRootsAndDomain an INTERNAL function, returns the expected post-consensus roots to sign
@@ -116,10 +141,7 @@ func (r *ValidatorRegistrationRunner) expectedPostConsensusRootsAndDomain() ([]s
 }
 
 func (r *ValidatorRegistrationRunner) executeDuty(logger *zap.Logger, duty *spectypes.Duty) error {
-	vr, err := r.calculateValidatorRegistration()
-	if err != nil {
-		return errors.Wrap(err, "could not calculate validator registration")
-	}
+	vr := r.validatorRegistration()
 
 	// sign partial randao
 	msg, err := r.BaseRunner.signBeaconObject(r, vr, duty.Slot, spectypes.DomainApplicationBuilder)
@@ -159,18 +181,57 @@ func (r *ValidatorRegistrationRunner) executeDuty(logger *zap.Logger, duty *spec
 	return nil
 }
 
-func (r *ValidatorRegistrationRunner) calculateValidatorRegistration() (*v1.ValidatorRegistration, error) {
+// validatorRegistration returns the validator registration of the running duty, calculating it on first use.
+func (r *ValidatorRegistrationRunner) validatorRegistration() *v1.ValidatorRegistration {
+	duty := r.BaseRunner.State.StartingDuty
+	if r.registration == nil || r.registrationDuty != duty {
+		r.registration, r.overridden = r.calculateValidatorRegistration()
+		r.registrationDuty = duty
+	}
+	return r.registration
+}
+
+// calculateValidatorRegistration returns the validator registration of the running duty,
+// and whether its fee recipient or gas limit is overridden by the proposer config.
+// Each operator signs the registration with its own proposer config, so overrides only reach quorum
+// if enough operators of the validator configure the same ones.
+func (r *ValidatorRegistrationRunner) calculateValidatorRegistration() (*v1.ValidatorRegistration, bool) {
 	pk := phase0.BLSPubKey{}
 	copy(pk[:], r.BaseRunner.Share.ValidatorPubKey)
 
 	epoch := r.BaseRunner.BeaconNetwork.EstimatedEpochAtSlot(r.BaseRunner.State.StartingDuty.Slot)
 
+	feeRecipient, feeRecipientOverridden := r.feeRecipient()
+	gasLimit, gasLimitOverridden := r.gasLimit()
 	return &v1.ValidatorRegistration{
-		FeeRecipient: r.BaseRunner.Share.FeeRecipientAddress,
-		GasLimit:     spectypes.DefaultGasLimit,
+		FeeRecipient: feeRecipient,
+		GasLimit:     gasLimit,
 		Timestamp:    r.BaseRunner.BeaconNetwork.EpochStartTime(epoch),
 		Pubkey:       pk,
-	}, nil
+	}, feeRecipientOverridden || gasLimitOverridden
+}
+
+// feeRecipient returns the fee recipient to register, and whether it's overridden by the proposer config.
+func (r *ValidatorRegistrationRunner) feeRecipient() (bellatrix.ExecutionAddress, bool) {
+	if r.ProposerConfig != nil {
+		if feeRecipient, ok := r.ProposerConfig.FeeRecipient(r.BaseRunner.Share.ValidatorPubKey); ok {
+			return feeRecipient, true
+		}
+	}
+	return r.BaseRunner.Share.FeeRecipientAddress, false
+}
+
+// gasLimit returns the gas limit to register, and whether it's overridden by the proposer config.
+func (r *ValidatorRegistrationRunner) gasLimit() (uint64, bool) {
+	if r.ProposerConfig != nil {
+		if gasLimit, ok := r.ProposerConfig.GasLimit(r.BaseRunner.Share.ValidatorPubKey); ok {
+			return gasLimit, true
+		}
+	}
+	if r.GasLimit != 0 {
+		return r.GasLimit, false
+	}
+	return spectypes.DefaultGasLimit, false
 }
 
 func (r *ValidatorRegistrationRunner) GetBaseRunner() *BaseRunner {
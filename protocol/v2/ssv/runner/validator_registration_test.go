@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testProposerConfig overrides the fee recipient and gas limit of every validator, if set.
+type testProposerConfig struct {
+	feeRecipient *bellatrix.ExecutionAddress
+	gasLimit     uint64
+}
+
+func (c *testProposerConfig) FeeRecipient(pubKey []byte) (bellatrix.ExecutionAddress, bool) {
+	if c.feeRecipient == nil {
+		return bellatrix.ExecutionAddress{}, false
+	}
+	return *c.feeRecipient, true
+}
+
+func (c *testProposerConfig) GasLimit(pubKey []byte) (uint64, bool) {
+	return c.gasLimit, c.gasLimit != 0
+}
+
+func (c *testProposerConfig) BuilderEnabled(pubKey []byte) (bool, bool) {
+	return false, false
+}
+
+func (c *testProposerConfig) BuilderMinBid(pubKey []byte) (uint64, bool) {
+	return 0, false
+}
+
+func TestValidatorRegistration(t *testing.T) {
+	config := &testProposerConfig{}
+	r := &ValidatorRegistrationRunner{
+		BaseRunner: &BaseRunner{
+			BeaconNetwork: spectypes.PraterNetwork,
+			Share: &spectypes.Share{
+				ValidatorPubKey:     make([]byte, 48),
+				FeeRecipientAddress: bellatrix.ExecutionAddress{1},
+			},
+			State: NewRunnerState(3, &spectypes.Duty{Slot: 100}),
+		},
+		ProposerConfig: config,
+	}
+
+	registration := r.validatorRegistration()
+	require.Equal(t, bellatrix.ExecutionAddress{1}, registration.FeeRecipient)
+	require.EqualValues(t, spectypes.DefaultGasLimit, registration.GasLimit)
+	require.False(t, r.overridden)
+
+	// Reloading the proposer config doesn't change the registration of the running duty.
+	config.feeRecipient = &bellatrix.ExecutionAddress{2}
+	config.gasLimit = 40_000_000
+	require.Same(t, registration, r.validatorRegistration())
+
+	// The next duty registers the reloaded overrides.
+	r.BaseRunner.State = NewRunnerState(3, &spectypes.Duty{Slot: 132})
+	registration = r.validatorRegistration()
+	require.Equal(t, bellatrix.ExecutionAddress{2}, registration.FeeRecipient)
+	require.EqualValues(t, 40_000_000, registration.GasLimit)
+	require.True(t, r.overridden)
+}
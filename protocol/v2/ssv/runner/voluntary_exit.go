@@ -90,6 +90,7 @@ func (r *VoluntaryExitRunner) ProcessPreConsensus(logger *zap.Logger, signedMsg
 	}
 
 	if err := r.beacon.SubmitVoluntaryExit(signedVoluntaryExit, specSig); err != nil {
+		r.BaseRunner.trackSubmission(err)
 		return errors.Wrap(err, "could not submit voluntary exit")
 	}
 
@@ -99,6 +100,7 @@ func (r *VoluntaryExitRunner) ProcessPreConsensus(logger *zap.Logger, signedMsg
 		zap.String("signature", hex.EncodeToString(specSig[:])),
 	)
 
+	r.BaseRunner.trackSubmission(nil)
 	r.GetState().Finished = true
 	return nil
 }
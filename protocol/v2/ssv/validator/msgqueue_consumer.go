@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/monitoring/tracing"
 	"github.com/bloxapp/ssv/protocol/v2/message"
 	"github.com/bloxapp/ssv/protocol/v2/qbft/instance"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/queue"
@@ -150,7 +151,10 @@ func (v *Validator) ConsumeQueue(logger *zap.Logger, msgID spectypes.MessageID,
 		}
 
 		// Handle the message.
-		if err := handler(logger, msg); err != nil {
+		handled := tracing.RecordMessage(msgID.GetPubKey(), msgID.GetRoleType(), message.MsgTypeToString(msg.MsgType), msg.EnqueuedAt)
+		err := handler(logger, msg)
+		handled(err)
+		if err != nil {
 			v.logMsg(logger, msg, "❗ could not handle message",
 				fields.MessageType(msg.SSVMessage.MsgType),
 				zap.Error(err))
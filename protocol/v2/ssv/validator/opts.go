@@ -10,6 +10,7 @@ import (
 	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	qbftctrl "github.com/bloxapp/ssv/protocol/v2/qbft/controller"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/runner"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/runner/performance"
 	"github.com/bloxapp/ssv/protocol/v2/types"
 )
 
@@ -32,8 +33,10 @@ type Options struct {
 	BuilderProposals  bool
 	QueueSize         int
 	GasLimit          uint64
+	ProposerConfig    beacon.ProposerConfig
 	MessageValidator  validation.MessageValidator
 	Metrics           Metrics
+	DutyTracker       *performance.Tracker
 }
 
 func (o *Options) defaults() {
@@ -0,0 +1,237 @@
+// Package snapshot implements registry snapshot files, from which new nodes can be bootstrapped
+// instead of syncing the registry from the contract events since its deployment.
+//
+// A snapshot starts with a plaintext header, identifying the network and the block of the registry state,
+// followed by the gzipped registry state. The header holds the checksum of the registry state,
+// and is optionally signed with the operator key of the node which exported it.
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/ssv/operator/keys"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+const (
+	// Version is the version of the snapshot format.
+	Version = 1
+
+	magic = "SSVSNAPSHOT"
+	// maxHeaderSize bounds the size of the header to read.
+	maxHeaderSize = 1 << 16
+)
+
+// Header describes a snapshot.
+type Header struct {
+	Version int       `json:"version"`
+	Network string    `json:"network"`
+	Block   uint64    `json:"block"`
+	Created time.Time `json:"created"`
+	// Checksum is the SHA-256 of the gzipped registry state.
+	Checksum []byte `json:"checksum"`
+	// Signer is the public key of the operator which signed the snapshot, in the base64 PEM format of operator keys.
+	Signer string `json:"signer,omitempty"`
+	// Signature is the RSA signature of the header without it.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Exporter exports the registry state.
+type Exporter interface {
+	BeginRead() basedb.ReadTxn
+	ExportRegistry(r basedb.Reader) (*operatorstorage.RegistryState, error)
+}
+
+// Export writes a snapshot of the current registry state to w, which is read in a single transaction
+// so that it's consistent with its last processed block, even while the node is running.
+func Export(w io.Writer, exporter Exporter, network string, signer keys.OperatorSigner) error {
+	txn := exporter.BeginRead()
+	defer txn.Discard()
+
+	state, err := exporter.ExportRegistry(txn)
+	if err != nil {
+		return errors.Wrap(err, "failed to export registry")
+	}
+	return Write(w, state, network, signer)
+}
+
+// Write writes a snapshot of the given registry state of the given network to w.
+// If signer is not nil, the snapshot is signed with it.
+func Write(w io.Writer, state *operatorstorage.RegistryState, network string, signer keys.OperatorSigner) error {
+	var body bytes.Buffer
+	gw := gzip.NewWriter(&body)
+	if err := json.NewEncoder(gw).Encode(state); err != nil {
+		return errors.Wrap(err, "failed to encode registry state")
+	}
+	if err := gw.Close(); err != nil {
+		return errors.Wrap(err, "failed to compress registry state")
+	}
+	checksum := sha256.Sum256(body.Bytes())
+
+	header := Header{
+		Version:  Version,
+		Network:  network,
+		Block:    state.Block,
+		Created:  time.Now().UTC(),
+		Checksum: checksum[:],
+	}
+	if signer != nil {
+		signerPubKey, err := keys.Base64PublicKey(signer)
+		if err != nil {
+			return errors.Wrap(err, "failed to extract signer public key")
+		}
+		header.Signer = signerPubKey
+		signed, err := header.signed()
+		if err != nil {
+			return err
+		}
+		header.Signature, err = signer.Sign(signed)
+		if err != nil {
+			return errors.Wrap(err, "failed to sign snapshot")
+		}
+	}
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode header")
+	}
+	var preamble bytes.Buffer
+	preamble.WriteString(magic)
+	_ = binary.Write(&preamble, binary.BigEndian, uint32(len(headerBytes)))
+	preamble.Write(headerBytes)
+	if _, err := w.Write(preamble.Bytes()); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return errors.Wrap(err, "failed to write registry state")
+	}
+	return nil
+}
+
+// Read reads a snapshot of the given network from r, and verifies its checksum.
+// If trustedSigner is not empty, the snapshot must be signed by it,
+// given in the base64 PEM format of operator public keys.
+func Read(r io.Reader, network string, trustedSigner string) (*Header, *operatorstorage.RegistryState, error) {
+	br := bufio.NewReader(r)
+	header, err := readHeader(br)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Version != Version {
+		return nil, nil, fmt.Errorf("unsupported snapshot version %d", header.Version)
+	}
+	if header.Network != network {
+		return nil, nil, fmt.Errorf("snapshot belongs to network %s, not %s", header.Network, network)
+	}
+	if trustedSigner != "" {
+		if err := header.verify(trustedSigner); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	body, err := io.ReadAll(br)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read registry state")
+	}
+	checksum := sha256.Sum256(body)
+	if !bytes.Equal(checksum[:], header.Checksum) {
+		return nil, nil, errors.New("snapshot checksum mismatch")
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decompress registry state")
+	}
+	state := &operatorstorage.RegistryState{}
+	if err := json.NewDecoder(gr).Decode(state); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode registry state")
+	}
+	if state.Block != header.Block {
+		return nil, nil, fmt.Errorf("snapshot header is at block %d, but registry state at block %d", header.Block, state.Block)
+	}
+	return header, state, nil
+}
+
+// ReadHeader reads the header of a snapshot without verifying it.
+func ReadHeader(r io.Reader) (*Header, error) {
+	return readHeader(bufio.NewReader(r))
+}
+
+func readHeader(r *bufio.Reader) (*Header, error) {
+	prefix := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(r, prefix); err != nil || string(prefix[:len(magic)]) != magic {
+		return nil, errors.New("not an SSV registry snapshot")
+	}
+	size := binary.BigEndian.Uint32(prefix[len(magic):])
+	if size > maxHeaderSize {
+		return nil, fmt.Errorf("header too large (%d bytes)", size)
+	}
+	headerBytes := make([]byte, size)
+	if _, err := io.ReadFull(r, headerBytes); err != nil {
+		return nil, errors.Wrap(err, "failed to read header")
+	}
+	var header Header
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, errors.Wrap(err, "failed to decode header")
+	}
+	return &header, nil
+}
+
+// digest returns the hash of the header without its signature, which is what's signed.
+// signed returns the encoding of the header without its signature, whose hash is signed.
+func (h Header) signed() ([]byte, error) {
+	h.Signature = nil
+	b, err := json.Marshal(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode header")
+	}
+	return b, nil
+}
+
+func (h Header) digest() ([]byte, error) {
+	b, err := h.signed()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(b)
+	return hash[:], nil
+}
+
+func (h Header) verify(trustedSigner string) error {
+	if len(h.Signature) == 0 {
+		return errors.New("snapshot is not signed")
+	}
+	if h.Signer != trustedSigner {
+		return errors.New("snapshot is not signed by the trusted signer")
+	}
+	pemBytes, err := base64.StdEncoding.DecodeString(trustedSigner)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode trusted signer")
+	}
+	pubKey, err := rsaencryption.ConvertPemToPublicKey(pemBytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode trusted signer")
+	}
+	digest, err := h.digest()
+	if err != nil {
+		return err
+	}
+	if err := rsa.VerifyPKCS1v15(pubKey, crypto.SHA256, digest, h.Signature); err != nil {
+		return errors.New("invalid snapshot signature")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/operator/keys"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+func TestWriteRead(t *testing.T) {
+	_, skPem, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	signer, err := rsaencryption.ConvertPemToPrivateKey(string(skPem))
+	require.NoError(t, err)
+	signerPubKey, err := rsaencryption.ExtractPublicKey(signer)
+	require.NoError(t, err)
+
+	state := &operatorstorage.RegistryState{
+		Block:     123,
+		Operators: []registrystorage.OperatorData{{ID: 1, PublicKey: []byte("pk")}},
+		Shares:    []*operatorstorage.RegistryShare{{Share: []byte("share"), EncryptedKeys: [][]byte{[]byte("key")}}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, state, "holesky", keys.PrivateKeyFromRSA(signer)))
+	data := buf.Bytes()
+
+	t.Run("read", func(t *testing.T) {
+		header, read, err := Read(bytes.NewReader(data), "holesky", signerPubKey)
+		require.NoError(t, err)
+		require.EqualValues(t, 123, header.Block)
+		require.Equal(t, signerPubKey, header.Signer)
+		require.Equal(t, state, read)
+	})
+
+	t.Run("wrong network", func(t *testing.T) {
+		_, _, err := Read(bytes.NewReader(data), "mainnet", "")
+		require.ErrorContains(t, err, "snapshot belongs to network holesky")
+	})
+
+	t.Run("untrusted signer", func(t *testing.T) {
+		_, otherPem, err := rsaencryption.GenerateKeys()
+		require.NoError(t, err)
+		other, err := rsaencryption.ConvertPemToPrivateKey(string(otherPem))
+		require.NoError(t, err)
+		otherPubKey, err := rsaencryption.ExtractPublicKey(other)
+		require.NoError(t, err)
+		_, _, err = Read(bytes.NewReader(data), "holesky", otherPubKey)
+		require.ErrorContains(t, err, "not signed by the trusted signer")
+	})
+
+	t.Run("tampered header", func(t *testing.T) {
+		tampered := bytes.Replace(data, []byte(`"block":123`), []byte(`"block":124`), 1)
+		_, _, err := Read(bytes.NewReader(tampered), "holesky", signerPubKey)
+		require.ErrorContains(t, err, "invalid snapshot signature")
+	})
+
+	t.Run("tampered state", func(t *testing.T) {
+		tampered := append([]byte{}, data...)
+		tampered[len(tampered)-10] ^= 0xff
+		_, _, err := Read(bytes.NewReader(tampered), "holesky", "")
+		require.ErrorContains(t, err, "snapshot checksum mismatch")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, state, "holesky", nil))
+		_, _, err := Read(bytes.NewReader(buf.Bytes()), "holesky", "")
+		require.NoError(t, err)
+		_, _, err = Read(bytes.NewReader(buf.Bytes()), "holesky", base64.StdEncoding.EncodeToString([]byte("signer")))
+		require.ErrorContains(t, err, "snapshot is not signed")
+	})
+
+	t.Run("not a snapshot", func(t *testing.T) {
+		_, err := ReadHeader(bytes.NewReader([]byte("something else entirely")))
+		require.ErrorContains(t, err, "not an SSV registry snapshot")
+	})
+}
@@ -37,6 +37,7 @@ type RecipientData struct {
 type Recipients interface {
 	GetRecipientData(r basedb.Reader, owner common.Address) (*RecipientData, bool, error)
 	GetRecipientDataMany(r basedb.Reader, owners []common.Address) (map[common.Address]bellatrix.ExecutionAddress, error)
+	ListRecipients(r basedb.Reader) ([]*RecipientData, error)
 	GetNextNonce(r basedb.Reader, owner common.Address) (Nonce, error)
 	BumpNonce(rw basedb.ReadWriter, owner common.Address) error
 	SaveRecipientData(rw basedb.ReadWriter, recipientData *RecipientData) (*RecipientData, error)
@@ -113,6 +114,27 @@ func (s *recipientsStorage) GetRecipientDataMany(r basedb.Reader, owners []commo
 	return results, nil
 }
 
+// ListRecipients returns the data of all recipients.
+func (s *recipientsStorage) ListRecipients(r basedb.Reader) ([]*RecipientData, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var recipients []*RecipientData
+	prefix := bytes.Join([][]byte{s.prefix, recipientsPrefix, []byte("/")}, nil)
+	err := s.db.UsingReader(r).GetAll(prefix, func(i int, obj basedb.Obj) error {
+		var recipient RecipientData
+		if err := json.Unmarshal(obj.Value, &recipient); err != nil {
+			return errors.Wrap(err, "could not unmarshal recipient data")
+		}
+		recipients = append(recipients, &recipient)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
 func (s *recipientsStorage) GetNextNonce(r basedb.Reader, owner common.Address) (Nonce, error) {
 	data, found, err := s.GetRecipientData(r, owner)
 	if err != nil {
@@ -31,6 +31,10 @@ type Shares interface {
 	// List returns a list of shares, filtered by the given filters (if any).
 	List(txn basedb.Reader, filters ...SharesFilter) []*types.SSVShare
 
+	// ReadAll reads all shares from the database rather than from the in-memory state,
+	// so that they're consistent with the other data read by the given transaction.
+	ReadAll(txn basedb.Reader) ([]*types.SSVShare, error)
+
 	// Save saves the given shares.
 	Save(txn basedb.ReadWriter, shares ...*types.SSVShare) error
 
@@ -40,6 +44,9 @@ type Shares interface {
 	// Drop deletes all shares.
 	Drop() error
 
+	// Reload reloads all shares from the database, discarding the in-memory state.
+	Reload() error
+
 	// UpdateValidatorMetadata updates validator metadata.
 	UpdateValidatorMetadata(pk string, metadata *beaconprotocol.ValidatorMetadata) error
 }
@@ -71,6 +78,7 @@ func (s *sharesStorage) load() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.shares = make(map[string]*types.SSVShare)
 	return s.db.GetAll(append(s.prefix, sharesPrefix...), func(i int, obj basedb.Obj) error {
 		val := &types.SSVShare{}
 		if err := val.Decode(obj.Value); err != nil {
@@ -109,6 +117,22 @@ Shares:
 	return shares
 }
 
+func (s *sharesStorage) ReadAll(r basedb.Reader) ([]*types.SSVShare, error) {
+	var shares []*types.SSVShare
+	err := s.db.UsingReader(r).GetAll(append(s.prefix, sharesPrefix...), func(i int, obj basedb.Obj) error {
+		val := &types.SSVShare{}
+		if err := val.Decode(obj.Value); err != nil {
+			return fmt.Errorf("failed to deserialize share: %w", err)
+		}
+		shares = append(shares, val)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
 func (s *sharesStorage) Save(rw basedb.ReadWriter, shares ...*types.SSVShare) error {
 	if len(shares) == 0 {
 		return nil
@@ -147,6 +171,10 @@ func (s *sharesStorage) Delete(rw basedb.ReadWriter, pubKey []byte) error {
 	return nil
 }
 
+func (s *sharesStorage) Reload() error {
+	return s.load()
+}
+
 // UpdateValidatorMetadata updates the metadata of the given validator
 func (s *sharesStorage) UpdateValidatorMetadata(pk string, metadata *beaconprotocol.ValidatorMetadata) error {
 	key, err := hex.DecodeString(pk)
@@ -0,0 +1,327 @@
+// Package backup implements encrypted backups of the node's database.
+//
+// A backup starts with a plaintext header, identifying the network of the database,
+// followed by the database backup encrypted in chunks with AES-GCM under a key derived from the operator key.
+// The header is authenticated by every chunk, and the last chunk is marked so that truncation is detected.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+const (
+	// Version is the version of the backup format.
+	Version = 1
+
+	magic     = "SSVBACKUP"
+	chunkSize = 1 << 20
+	saltSize  = 16
+	// maxHeaderSize bounds the size of the header to read.
+	maxHeaderSize = 1 << 16
+)
+
+// Header describes a backup.
+type Header struct {
+	Version     int       `json:"version"`
+	Network     string    `json:"network"`
+	Created     time.Time `json:"created"`
+	Salt        []byte    `json:"salt"`
+	NoncePrefix []byte    `json:"nonce_prefix"`
+}
+
+// Backup writes an encrypted backup of the database of the given network to w.
+// The backup is consistent and can be taken while the node is running.
+func Backup(w io.Writer, db kv.Database, encryptionKey string, network string) error {
+	bw, err := NewWriter(w, encryptionKey, network)
+	if err != nil {
+		return err
+	}
+	if err := db.Backup(bw); err != nil {
+		return errors.Wrap(err, "failed to back up database")
+	}
+	return bw.Close()
+}
+
+// Restore restores an encrypted backup into the database, which must be empty.
+// The backup must belong to the given network.
+func Restore(r io.Reader, db kv.Database, encryptionKey string, network string) (*Header, error) {
+	br, err := NewReader(r, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if br.Header.Network != network {
+		return nil, fmt.Errorf("backup belongs to network %s, not %s", br.Header.Network, network)
+	}
+	if err := db.Restore(br); err != nil {
+		return nil, errors.Wrap(err, "failed to restore database")
+	}
+	return &br.Header, nil
+}
+
+// Writer encrypts a backup written to it. Close must be called to complete the backup.
+type Writer struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  Header
+	ad      []byte
+	buf     []byte
+	counter uint64
+	closed  bool
+}
+
+// NewWriter writes the header of a backup to w, and returns a Writer encrypting the backup data.
+func NewWriter(w io.Writer, encryptionKey string, network string) (*Writer, error) {
+	header := Header{
+		Version:     Version,
+		Network:     network,
+		Created:     time.Now().UTC(),
+		Salt:        make([]byte, saltSize),
+		NoncePrefix: make([]byte, 4),
+	}
+	if _, err := rand.Read(header.Salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+	if _, err := rand.Read(header.NoncePrefix); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode header")
+	}
+	aead, err := newAEAD(encryptionKey, header.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	var preamble bytes.Buffer
+	preamble.WriteString(magic)
+	_ = binary.Write(&preamble, binary.BigEndian, uint32(len(headerBytes)))
+	preamble.Write(headerBytes)
+	if _, err := w.Write(preamble.Bytes()); err != nil {
+		return nil, errors.Wrap(err, "failed to write header")
+	}
+
+	return &Writer{
+		w:      w,
+		aead:   aead,
+		header: header,
+		ad:     headerDigest(headerBytes),
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (bw *Writer) Write(p []byte) (int, error) {
+	if bw.closed {
+		return 0, errors.New("backup writer is closed")
+	}
+	n := len(p)
+	for len(p) > 0 {
+		free := chunkSize - len(bw.buf)
+		if free > len(p) {
+			free = len(p)
+		}
+		bw.buf = append(bw.buf, p[:free]...)
+		p = p[free:]
+		if len(bw.buf) == chunkSize {
+			if err := bw.flush(false); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Close writes the last chunk of the backup. It doesn't close the underlying writer.
+func (bw *Writer) Close() error {
+	if bw.closed {
+		return nil
+	}
+	bw.closed = true
+	return bw.flush(true)
+}
+
+func (bw *Writer) flush(last bool) error {
+	sealed := bw.aead.Seal(nil, nonce(bw.header.NoncePrefix, bw.counter), bw.buf, chunkAD(bw.ad, last))
+	bw.counter++
+	bw.buf = bw.buf[:0]
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := bw.w.Write(length[:]); err != nil {
+		return errors.Wrap(err, "failed to write chunk")
+	}
+	if _, err := bw.w.Write(sealed); err != nil {
+		return errors.Wrap(err, "failed to write chunk")
+	}
+	return nil
+}
+
+// Reader decrypts a backup.
+type Reader struct {
+	Header Header
+
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	ad      []byte
+	buf     []byte
+	counter uint64
+	done    bool
+}
+
+// NewReader reads the header of a backup from r, and returns a Reader decrypting the backup data.
+func NewReader(r io.Reader, encryptionKey string) (*Reader, error) {
+	header, headerBytes, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(encryptionKey, header.Salt)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		Header: *header,
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		ad:     headerDigest(headerBytes),
+	}, nil
+}
+
+// ReadHeader reads the header of a backup, without decrypting it.
+func ReadHeader(r io.Reader) (*Header, error) {
+	header, _, err := readHeader(r)
+	return header, err
+}
+
+func (br *Reader) Read(p []byte) (int, error) {
+	for len(br.buf) == 0 {
+		if br.done {
+			return 0, io.EOF
+		}
+		if err := br.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, br.buf)
+	br.buf = br.buf[n:]
+	return n, nil
+}
+
+// next reads and decrypts the next chunk.
+func (br *Reader) next() error {
+	var length [4]byte
+	if _, err := io.ReadFull(br.r, length[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("backup is truncated")
+		}
+		return errors.Wrap(err, "failed to read chunk")
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > chunkSize+uint32(br.aead.Overhead()) {
+		return errors.New("invalid chunk size")
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(br.r, sealed); err != nil {
+		return errors.Wrap(err, "failed to read chunk")
+	}
+
+	nonce := nonce(br.Header.NoncePrefix, br.counter)
+	br.counter++
+	if plain, err := br.aead.Open(nil, nonce, sealed, chunkAD(br.ad, false)); err == nil {
+		br.buf = plain
+		return nil
+	}
+	plain, err := br.aead.Open(nil, nonce, sealed, chunkAD(br.ad, true))
+	if err != nil {
+		return errors.New("failed to decrypt backup, either the operator key is wrong or the backup is corrupted")
+	}
+	// The backup must end with its last chunk, since anything appended to it isn't authenticated.
+	if _, err := br.r.Peek(1); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return errors.Wrap(err, "failed to read chunk")
+		}
+		return errors.New("backup has trailing data after its last chunk")
+	}
+	br.buf = plain
+	br.done = true
+	return nil
+}
+
+func readHeader(r io.Reader) (*Header, []byte, error) {
+	prefix := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read header")
+	}
+	if string(prefix[:len(magic)]) != magic {
+		return nil, nil, errors.New("not an SSV backup")
+	}
+	size := binary.BigEndian.Uint32(prefix[len(magic):])
+	if size > maxHeaderSize {
+		return nil, nil, errors.New("invalid header size")
+	}
+	headerBytes := make([]byte, size)
+	if _, err := io.ReadFull(r, headerBytes); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read header")
+	}
+
+	var header Header
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode header")
+	}
+	if header.Version != Version {
+		return nil, nil, fmt.Errorf("unsupported backup version %d", header.Version)
+	}
+	if len(header.Salt) != saltSize || len(header.NoncePrefix) != 4 {
+		return nil, nil, errors.New("invalid header")
+	}
+	return &header, headerBytes, nil
+}
+
+// newAEAD returns the cipher of a backup, keyed by the hash of the operator key and the backup's salt.
+func newAEAD(encryptionKey string, salt []byte) (cipher.AEAD, error) {
+	if encryptionKey == "" {
+		return nil, errors.New("encryption key is empty")
+	}
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(encryptionKey))
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return cipher.NewGCM(block)
+}
+
+func nonce(prefix []byte, counter uint64) []byte {
+	n := make([]byte, 12)
+	copy(n, prefix)
+	binary.BigEndian.PutUint64(n[4:], counter)
+	return n
+}
+
+func headerDigest(headerBytes []byte) []byte {
+	digest := sha256.Sum256(headerBytes)
+	return digest[:]
+}
+
+func chunkAD(headerDigest []byte, last bool) []byte {
+	ad := append([]byte{}, headerDigest...)
+	if last {
+		return append(ad, 1)
+	}
+	return append(ad, 0)
+}
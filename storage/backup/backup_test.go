@@ -0,0 +1,111 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/bloxapp/ssv/storage/kv"
+)
+
+func newTestDB(t *testing.T) *kv.BadgerDB {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{Ctx: context.Background()})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestBackupRestore(t *testing.T) {
+	const key = "operator-key-hash"
+	prefix := []byte("prefix")
+
+	db := newTestDB(t)
+	// Enough data to span multiple chunks.
+	value := bytes.Repeat([]byte{7}, 64<<10)
+	for i := 0; i < 40; i++ {
+		require.NoError(t, db.Set(prefix, []byte(fmt.Sprintf("key%d", i)), value))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Backup(&buf, db, key, "holesky"))
+	data := buf.Bytes()
+
+	header, err := ReadHeader(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, "holesky", header.Network)
+
+	t.Run("restore", func(t *testing.T) {
+		restored := newTestDB(t)
+		header, err := Restore(bytes.NewReader(data), restored, key, "holesky")
+		require.NoError(t, err)
+		require.Equal(t, Version, header.Version)
+
+		count, err := restored.CountPrefix(prefix)
+		require.NoError(t, err)
+		require.EqualValues(t, 40, count)
+		obj, found, err := restored.Get(prefix, []byte("key39"))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, value, obj.Value)
+
+		// The database must be empty.
+		_, err = Restore(bytes.NewReader(data), restored, key, "holesky")
+		require.ErrorContains(t, err, "database is not empty")
+	})
+
+	t.Run("restore into pebble", func(t *testing.T) {
+		restored, err := kv.NewPebbleInMemory(logging.TestLogger(t), basedb.Options{Ctx: context.Background()})
+		require.NoError(t, err)
+		defer restored.Close()
+		_, err = Restore(bytes.NewReader(data), restored, key, "holesky")
+		require.NoError(t, err)
+
+		count, err := restored.CountPrefix(prefix)
+		require.NoError(t, err)
+		require.EqualValues(t, 40, count)
+		obj, found, err := restored.Get(prefix, []byte("key39"))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, value, obj.Value)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := Restore(bytes.NewReader(data), newTestDB(t), "another-key", "holesky")
+		require.ErrorContains(t, err, "failed to decrypt backup")
+	})
+
+	t.Run("wrong network", func(t *testing.T) {
+		_, err := Restore(bytes.NewReader(data), newTestDB(t), key, "mainnet")
+		require.ErrorContains(t, err, "backup belongs to network holesky")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(data[:len(data)/2]), key)
+		require.NoError(t, err)
+		_, err = bytes.NewBuffer(nil).ReadFrom(r)
+		require.Error(t, err)
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(append(append([]byte{}, data...), "appended"...)), key)
+		require.NoError(t, err)
+		_, err = bytes.NewBuffer(nil).ReadFrom(r)
+		require.ErrorContains(t, err, "trailing data")
+	})
+
+	t.Run("tampered header", func(t *testing.T) {
+		tampered := bytes.Replace(data, []byte(`"holesky"`), []byte(`"mainnet"`), 1)
+		_, err := Restore(bytes.NewReader(tampered), newTestDB(t), key, "mainnet")
+		require.ErrorContains(t, err, "failed to decrypt backup")
+	})
+
+	t.Run("not a backup", func(t *testing.T) {
+		_, err := ReadHeader(bytes.NewReader([]byte("something else entirely")))
+		require.ErrorContains(t, err, "not an SSV backup")
+	})
+}
@@ -5,9 +5,16 @@ import (
 	"time"
 )
 
+// Storage engines which implement Database.
+const (
+	EngineBadger = "badger"
+	EnginePebble = "pebble"
+)
+
 // Options for creating all db type
 type Options struct {
 	Ctx        context.Context
+	Engine     string        `yaml:"Engine" env:"DB_ENGINE" env-default:"badger" env-description:"Storage engine of the database (badger or pebble)"`
 	Path       string        `yaml:"Path" env:"DB_PATH" env-default:"./data/db" env-description:"Path for storage"`
 	Reporting  bool          `yaml:"Reporting" env:"DB_REPORTING" env-default:"false" env-description:"Flag to run on-off db size reporting"`
 	GCInterval time.Duration `yaml:"GCInterval" env:"DB_GC_INTERVAL" env-default:"6m" env-description:"Interval between garbage collection cycles. Set to 0 to disable."`
@@ -18,6 +25,21 @@ type Reader interface {
 	Get(prefix []byte, key []byte) (Obj, bool, error)
 	GetMany(prefix []byte, keys [][]byte, iterator func(Obj) error) error
 	GetAll(prefix []byte, handler func(int, Obj) error) error
+	// Iterate calls handler with the items of the given prefix in the order of their keys,
+	// within the range and up to the limit of the given options.
+	Iterate(prefix []byte, opts IterateOptions, handler func(Obj) error) error
+}
+
+// IterateOptions is the range of keys to iterate over, relative to the prefix.
+type IterateOptions struct {
+	// Start is the first key of the range, inclusive. If empty, the range starts at the first key.
+	Start []byte
+	// End is the last key of the range, exclusive. If empty, the range ends at the last key.
+	End []byte
+	// Reverse iterates over the range in descending order of keys.
+	Reverse bool
+	// Limit is the maximal number of items to iterate over, or 0 for no limit.
+	Limit int
 }
 
 // ReadWrite is a read-write accessor to the database.
@@ -31,7 +53,6 @@ type ReadWriter interface {
 // Txn is a read-write transaction.
 type Txn interface {
 	ReadWriter
-	// TODO: add iterator
 	Commit() error
 	Discard()
 }
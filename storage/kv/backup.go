@@ -0,0 +1,171 @@
+package kv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/dgraph-io/badger/v4/pb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxPendingRestoreWrites is the number of pending writes to buffer while restoring a backup.
+	maxPendingRestoreWrites = 256
+
+	// backupListSize is the size of the lists of items to write at once while backing up PebbleDB.
+	backupListSize = 1 << 20
+	// restoreBatchSize is the size of the batches to commit while restoring a backup into PebbleDB.
+	restoreBatchSize = 4 << 20
+
+	// badgerBitDelete is the meta bit of deleted items in badger's backups.
+	badgerBitDelete = 1 << 0
+)
+
+// Backup writes a full backup of the database to w.
+// The backup is a consistent snapshot taken while the database keeps serving reads and writes.
+func (b *BadgerDB) Backup(w io.Writer) error {
+	_, err := b.db.Backup(w, 0)
+	return err
+}
+
+// Restore loads a backup written by Backup into the database, which must be empty.
+func (b *BadgerDB) Restore(r io.Reader) error {
+	if err := ensureEmpty(b); err != nil {
+		return err
+	}
+	return b.db.Load(r, maxPendingRestoreWrites)
+}
+
+// Backup writes a full backup of the database to w, in the format of BadgerDB's backups.
+// The backup is a consistent snapshot taken while the database keeps serving reads and writes.
+func (p *PebbleDB) Backup(w io.Writer) error {
+	snapshot := p.db.NewSnapshot()
+	defer snapshot.Close()
+
+	list := &pb.KVList{}
+	size := 0
+	iter := snapshot.NewIter(nil)
+	for iter.First(); iter.Valid(); iter.Next() {
+		list.Kv = append(list.Kv, &pb.KV{
+			Key:     bytes.Clone(iter.Key()),
+			Value:   bytes.Clone(iter.Value()),
+			Version: 1,
+		})
+		size += len(iter.Key()) + len(iter.Value())
+		if size >= backupListSize {
+			if err := writeKVList(w, list); err != nil {
+				_ = iter.Close()
+				return err
+			}
+			list.Kv, size = list.Kv[:0], 0
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	if len(list.Kv) > 0 {
+		return writeKVList(w, list)
+	}
+	return nil
+}
+
+// Restore loads a backup written by Backup into the database, which must be empty.
+// Backups written by BadgerDB may hold earlier versions and deletions of items,
+// of which only the latest versions are restored.
+func (p *PebbleDB) Restore(r io.Reader) error {
+	if err := ensureEmpty(p); err != nil {
+		return err
+	}
+
+	batch := p.db.NewBatch()
+	defer func() { _ = batch.Close() }()
+
+	var lastKey []byte
+	now := uint64(time.Now().Unix())
+	err := readKVLists(r, func(list *pb.KVList) error {
+		for _, kv := range list.Kv {
+			// The versions of an item are ordered from the latest.
+			if lastKey != nil && bytes.Equal(kv.Key, lastKey) {
+				continue
+			}
+			lastKey = kv.Key
+			if len(kv.Meta) > 0 && kv.Meta[0]&badgerBitDelete != 0 {
+				continue
+			}
+			if kv.ExpiresAt != 0 && kv.ExpiresAt <= now {
+				continue
+			}
+			if err := batch.Set(kv.Key, kv.Value, nil); err != nil {
+				return err
+			}
+			if batch.Len() >= restoreBatchSize {
+				if err := batch.Commit(pebble.Sync); err != nil {
+					return err
+				}
+				_ = batch.Close()
+				batch = p.db.NewBatch()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func ensureEmpty(db Database) error {
+	count, err := db.CountPrefix(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to check whether the database is empty")
+	}
+	if count > 0 {
+		return errors.New("database is not empty")
+	}
+	return nil
+}
+
+// writeKVList writes a list of items like badger's backups do, prefixed by its size.
+func writeKVList(w io.Writer, list *pb.KVList) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(list.Size())); err != nil {
+		return err
+	}
+	buf, err := list.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// readKVLists reads the lists of items of a backup written like badger's backups.
+func readKVLists(r io.Reader, handle func(list *pb.KVList) error) error {
+	br := bufio.NewReaderSize(r, 16<<10)
+	var buf []byte
+	for {
+		var size uint64
+		err := binary.Read(br, binary.LittleEndian, &size)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if uint64(cap(buf)) < size {
+			buf = make([]byte, size)
+		}
+		if _, err := io.ReadFull(br, buf[:size]); err != nil {
+			return err
+		}
+		list := &pb.KVList{}
+		if err := list.Unmarshal(buf[:size]); err != nil {
+			return err
+		}
+		if err := handle(list); err != nil {
+			return err
+		}
+	}
+}
@@ -183,6 +183,12 @@ func (b *BadgerDB) GetAll(prefix []byte, handler func(int, basedb.Obj) error) er
 	return err
 }
 
+// Iterate calls handler with the items of the given prefix in the order of their keys,
+// within the range and up to the limit of the given options
+func (b *BadgerDB) Iterate(prefix []byte, opts basedb.IterateOptions, handler func(basedb.Obj) error) error {
+	return b.db.View(b.iterator(prefix, opts, handler))
+}
+
 // CountPrefix return the object count for all keys under specified prefix(bucket)
 func (b *BadgerDB) CountPrefix(prefix []byte) (int64, error) {
 	var res int64
@@ -204,6 +210,11 @@ func (b *BadgerDB) DropPrefix(prefix []byte) error {
 	return b.db.DropPrefix(prefix)
 }
 
+// DropAll cleans all items in the database
+func (b *BadgerDB) DropAll() error {
+	return b.db.DropAll()
+}
+
 // Close closes the database.
 func (b *BadgerDB) Close() error {
 	// Stop & wait for background goroutines.
@@ -325,6 +336,66 @@ func (b *BadgerDB) manyGetter(prefix []byte, keys [][]byte, iterator func(basedb
 	}
 }
 
+func (b *BadgerDB) iterator(prefix []byte, opts basedb.IterateOptions, handler func(basedb.Obj) error) func(txn *badger.Txn) error {
+	return func(txn *badger.Txn) error {
+		start := concatKey(prefix, opts.Start)
+		end := prefixUpperBound(prefix)
+		if len(opts.End) > 0 {
+			end = concatKey(prefix, opts.End)
+		}
+
+		itOpts := badger.DefaultIteratorOptions
+		itOpts.Reverse = opts.Reverse
+		if !opts.Reverse || end != nil {
+			// Without an end, reverse iteration starts at the last key of the database,
+			// which is within the prefix, so the iterator can't be restricted to it.
+			itOpts.Prefix = prefix
+		}
+		it := txn.NewIterator(itOpts)
+		defer it.Close()
+
+		switch {
+		case !opts.Reverse:
+			it.Seek(start)
+		case end == nil:
+			it.Rewind()
+		default:
+			// Seeking backwards lands on the end itself if it exists, which is excluded from the range.
+			it.Seek(end)
+			if it.Valid() && bytes.Equal(it.Item().Key(), end) {
+				it.Next()
+			}
+		}
+
+		count := 0
+		for ; it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			key := item.Key()
+			if opts.Reverse && bytes.Compare(key, start) < 0 {
+				break
+			}
+			if !opts.Reverse && end != nil && bytes.Compare(key, end) >= 0 {
+				break
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err := handler(basedb.Obj{
+				Key:   bytes.Clone(key[len(prefix):]),
+				Value: value,
+			}); err != nil {
+				return err
+			}
+			count++
+			if opts.Limit > 0 && count >= opts.Limit {
+				break
+			}
+		}
+		return nil
+	}
+}
+
 // Using returns the given ReadWriter, falling back to the database if it's nil.
 func (b *BadgerDB) Using(rw basedb.ReadWriter) basedb.ReadWriter {
 	if rw == nil {
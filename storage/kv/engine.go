@@ -0,0 +1,70 @@
+package kv
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// Database is a basedb.Database of one of the storage engines.
+type Database interface {
+	basedb.Database
+
+	// Backup writes a full backup of the database to w, in the format of badger's backups,
+	// so that it can be restored into a database of either engine.
+	Backup(w io.Writer) error
+	// Restore loads a backup written by Backup into the database, which must be empty.
+	Restore(r io.Reader) error
+	// DropAll deletes all the items in the database.
+	DropAll() error
+}
+
+// Open creates a persistent DB instance of the storage engine in the given options.
+func Open(logger *zap.Logger, options basedb.Options) (Database, error) {
+	switch options.Engine {
+	case "", basedb.EngineBadger:
+		db, err := New(logger, options)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case basedb.EnginePebble:
+		db, err := NewPebble(logger, options)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	default:
+		return nil, fmt.Errorf("unknown storage engine %q", options.Engine)
+	}
+}
+
+// Copy copies all the items of the source database into the target database, which must be empty,
+// and returns the number of items copied. The databases may be of different storage engines.
+func Copy(target Database, source Database) (int64, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(source.Backup(pw))
+	}()
+	if err := target.Restore(pr); err != nil {
+		_ = pr.CloseWithError(err)
+		return 0, errors.Wrap(err, "failed to copy items")
+	}
+
+	sourceCount, err := source.CountPrefix(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count source items")
+	}
+	targetCount, err := target.CountPrefix(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count target items")
+	}
+	if targetCount != sourceCount {
+		return 0, fmt.Errorf("copied %d items out of %d", targetCount, sourceCount)
+	}
+	return targetCount, nil
+}
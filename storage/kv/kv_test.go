@@ -0,0 +1,372 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// testEngines creates in-memory databases of every storage engine.
+var testEngines = map[string]func(*zap.Logger, basedb.Options) (Database, error){
+	basedb.EngineBadger: func(logger *zap.Logger, options basedb.Options) (Database, error) {
+		return NewInMemory(logger, options)
+	},
+	basedb.EnginePebble: func(logger *zap.Logger, options basedb.Options) (Database, error) {
+		return NewPebbleInMemory(logger, options)
+	},
+}
+
+// testEachEngine runs the test with an in-memory database of every storage engine.
+func testEachEngine(t *testing.T, logger *zap.Logger, options basedb.Options, test func(t *testing.T, db Database)) {
+	for engine, newDB := range testEngines {
+		engine, newDB := engine, newDB
+		t.Run(engine, func(t *testing.T) {
+			db, err := newDB(logger, options)
+			require.NoError(t, err)
+			defer db.Close()
+
+			test(t, db)
+		})
+	}
+}
+
+func TestEndToEnd(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	zapCore, observedLogs := observer.New(zap.DebugLevel)
+	logger := zap.New(zapCore)
+	options := basedb.Options{
+		Reporting: true,
+		Ctx:       ctx,
+	}
+
+	testEachEngine(t, logger, options, func(t *testing.T, db Database) {
+		testEndToEnd(t, db, observedLogs)
+	})
+}
+
+func testEndToEnd(t *testing.T, db Database, observedLogs *observer.ObservedLogs) {
+	toSave := []struct {
+		prefix []byte
+		key    []byte
+		value  []byte
+	}{
+		{
+			[]byte("prefix1"),
+			[]byte("key1"),
+			[]byte("value"),
+		},
+		{
+			[]byte("prefix1"),
+			[]byte("key2"),
+			[]byte("value"),
+		},
+		{
+			[]byte("prefix2"),
+			[]byte("key1"),
+			[]byte("value"),
+		},
+	}
+
+	for _, save := range toSave {
+		require.NoError(t, db.Set(save.prefix, save.key, save.value))
+	}
+
+	obj, found, err := db.Get(toSave[0].prefix, toSave[0].key)
+	require.True(t, found)
+	require.NoError(t, err)
+	require.EqualValues(t, toSave[0].key, obj.Key)
+	require.EqualValues(t, toSave[0].value, obj.Value)
+
+	count := 0
+	err = db.GetAll(toSave[0].prefix, func(i int, obj basedb.Obj) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	obj, found, err = db.Get(toSave[2].prefix, toSave[2].key)
+	require.True(t, found)
+	require.NoError(t, err)
+	require.EqualValues(t, toSave[2].key, obj.Key)
+	require.EqualValues(t, toSave[2].value, obj.Value)
+
+	logCountBeforeReport := observedLogs.Len()
+	db.(interface{ report() }).report()
+	logCountAfterReport := observedLogs.Len()
+	require.Equal(t, logCountBeforeReport+1, logCountAfterReport)
+
+	require.NoError(t, db.Delete(toSave[0].prefix, toSave[0].key))
+	obj, found, err = db.Get(toSave[0].prefix, toSave[0].key)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, db.DropPrefix([]byte("prefix2")))
+	deleted, err := db.DeletePrefix([]byte("prefix1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	remaining, err := db.CountPrefix(nil)
+	require.NoError(t, err)
+	require.Zero(t, remaining)
+}
+
+func TestDb_GetAll(t *testing.T) {
+	logger := logging.TestLogger(t)
+
+	t.Run("100_items", func(t *testing.T) {
+		testEachEngine(t, logger, basedb.Options{}, func(t *testing.T, db Database) {
+			getAllTest(t, 100, db)
+		})
+	})
+
+	t.Run("10K_items", func(t *testing.T) {
+		testEachEngine(t, logger, basedb.Options{}, func(t *testing.T, db Database) {
+			getAllTest(t, 10000, db)
+		})
+	})
+
+	t.Run("100K_items", func(t *testing.T) {
+		testEachEngine(t, logger, basedb.Options{}, func(t *testing.T, db Database) {
+			getAllTest(t, 100000, db)
+		})
+	})
+}
+
+func TestDb_GetMany(t *testing.T) {
+	testEachEngine(t, logging.TestLogger(t), basedb.Options{}, testGetMany)
+}
+
+func testGetMany(t *testing.T, db Database) {
+	prefix := []byte("prefix")
+	var i uint64
+	for i = 0; i < 100; i++ {
+		require.NoError(t, db.Set(prefix, uInt64ToByteSlice(i+1), uInt64ToByteSlice(i+1)))
+	}
+
+	results := make([]basedb.Obj, 0)
+	err := db.GetMany(prefix, [][]byte{uInt64ToByteSlice(1), uInt64ToByteSlice(2),
+		uInt64ToByteSlice(5), uInt64ToByteSlice(10)}, func(obj basedb.Obj) error {
+		require.True(t, bytes.Equal(obj.Key, obj.Value))
+		results = append(results, obj)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, len(results))
+}
+
+func TestDb_SetMany(t *testing.T) {
+	testEachEngine(t, logging.TestLogger(t), basedb.Options{}, testSetMany)
+}
+
+func testSetMany(t *testing.T, db Database) {
+	prefix := []byte("prefix")
+	var values [][]byte
+	err := db.SetMany(prefix, 10, func(i int) (basedb.Obj, error) {
+		seq := uint64(i + 1)
+		values = append(values, uInt64ToByteSlice(seq))
+		return basedb.Obj{Key: uInt64ToByteSlice(seq), Value: uInt64ToByteSlice(seq)}, nil
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		seq := uint64(i + 1)
+		obj, found, err := db.Get(prefix, uInt64ToByteSlice(seq))
+		require.NoError(t, err, "should find item %d", i)
+		require.True(t, found, "should find item %d", i)
+		require.True(t, bytes.Equal(obj.Value, values[i]), "item %d wrong value", i)
+	}
+}
+
+func uInt64ToByteSlice(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, n)
+	return b
+}
+
+func getAllTest(t *testing.T, n int, db basedb.Database) {
+	// populating DB
+	prefix := []byte("test")
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("test-%d", i)
+		require.NoError(t, db.Set(prefix, []byte(id), []byte(id+"-data")))
+	}
+	time.Sleep(1 * time.Millisecond)
+
+	var all []basedb.Obj
+	err := db.GetAll(prefix, func(i int, obj basedb.Obj) error {
+		all = append(all, obj)
+		return nil
+	})
+	require.Equal(t, n, len(all))
+	require.NoError(t, err)
+	visited := map[string][]byte{}
+	for _, item := range all {
+		visited[string(item.Key)] = item.Value
+	}
+	require.Equal(t, n, len(visited))
+	count, err := db.DeletePrefix(prefix)
+	require.NoError(t, err)
+	require.Equal(t, n, count)
+}
+
+func TestDb_Txn(t *testing.T) {
+	testEachEngine(t, logging.TestLogger(t), basedb.Options{}, func(t *testing.T, db Database) {
+		prefix := []byte("prefix")
+		require.NoError(t, db.Set(prefix, []byte("key1"), []byte("value1")))
+
+		// Writes are visible within the transaction, but not outside of it until it's committed.
+		txn := db.Begin()
+		require.NoError(t, txn.Set(prefix, []byte("key2"), []byte("value2")))
+		require.NoError(t, txn.Delete(prefix, []byte("key1")))
+		_, found, err := txn.Get(prefix, []byte("key1"))
+		require.NoError(t, err)
+		require.False(t, found)
+		obj, found, err := txn.Get(prefix, []byte("key2"))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, []byte("value2"), obj.Value)
+		_, found, err = db.Get(prefix, []byte("key2"))
+		require.NoError(t, err)
+		require.False(t, found)
+
+		// A read transaction doesn't see writes committed after it began.
+		readTxn := db.BeginRead()
+		defer readTxn.Discard()
+		require.NoError(t, txn.Commit())
+		txn.Discard()
+		_, found, err = readTxn.Get(prefix, []byte("key2"))
+		require.NoError(t, err)
+		require.False(t, found)
+		_, found, err = db.Get(prefix, []byte("key1"))
+		require.NoError(t, err)
+		require.False(t, found)
+		_, found, err = db.Get(prefix, []byte("key2"))
+		require.NoError(t, err)
+		require.True(t, found)
+
+		// Discarded writes are dropped.
+		require.EqualError(t, db.Update(func(txn basedb.Txn) error {
+			require.NoError(t, txn.Set(prefix, []byte("key3"), []byte("value3")))
+			return fmt.Errorf("fail")
+		}), "fail")
+		_, found, err = db.Get(prefix, []byte("key3"))
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+}
+
+func TestCopy(t *testing.T) {
+	logger := logging.TestLogger(t)
+	items := map[string]string{}
+
+	// The backups of badger may hold earlier versions and deletions of items, which are not copied.
+	badgerDB, err := NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer badgerDB.Close()
+	prefix := []byte("prefix")
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		require.NoError(t, badgerDB.Set(prefix, []byte(key), []byte("old")))
+		switch i % 3 {
+		case 0:
+			require.NoError(t, badgerDB.Set(prefix, []byte(key), []byte(key)))
+			items[key] = key
+		case 1:
+			require.NoError(t, badgerDB.Delete(prefix, []byte(key)))
+		default:
+			items[key] = "old"
+		}
+	}
+
+	requireItems := func(db Database) {
+		all := map[string]string{}
+		require.NoError(t, db.GetAll(prefix, func(i int, obj basedb.Obj) error {
+			all[string(obj.Key)] = string(obj.Value)
+			return nil
+		}))
+		require.Equal(t, items, all)
+	}
+
+	pebbleDB, err := NewPebbleInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer pebbleDB.Close()
+	count, err := Copy(pebbleDB, badgerDB)
+	require.NoError(t, err)
+	require.EqualValues(t, len(items), count)
+	requireItems(pebbleDB)
+
+	// Copying back from pebble to badger.
+	badgerCopy, err := NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer badgerCopy.Close()
+	_, err = Copy(badgerCopy, pebbleDB)
+	require.NoError(t, err)
+	requireItems(badgerCopy)
+
+	// The target must be empty.
+	_, err = Copy(pebbleDB, badgerDB)
+	require.ErrorContains(t, err, "database is not empty")
+}
+
+func TestDb_Iterate(t *testing.T) {
+	testEachEngine(t, logging.TestLogger(t), basedb.Options{}, func(t *testing.T, db Database) {
+		prefix := []byte("prefix")
+		for i := uint64(0); i < 10; i++ {
+			require.NoError(t, db.Set(prefix, bigEndian(i), []byte{byte(i)}))
+		}
+		// Items of neighboring prefixes are excluded.
+		require.NoError(t, db.Set([]byte("prefiw"), bigEndian(1), []byte("before")))
+		require.NoError(t, db.Set([]byte("prefiy"), bigEndian(1), []byte("after")))
+
+		iterate := func(r basedb.Reader, opts basedb.IterateOptions) []byte {
+			var values []byte
+			require.NoError(t, r.Iterate(prefix, opts, func(obj basedb.Obj) error {
+				require.Equal(t, bigEndian(uint64(obj.Value[0])), obj.Key)
+				values = append(values, obj.Value[0])
+				return nil
+			}))
+			return values
+		}
+
+		require.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, iterate(db, basedb.IterateOptions{}))
+		require.Equal(t, []byte{3, 4, 5, 6}, iterate(db, basedb.IterateOptions{Start: bigEndian(3), End: bigEndian(7)}))
+		require.Equal(t, []byte{3, 4}, iterate(db, basedb.IterateOptions{Start: bigEndian(3), Limit: 2}))
+		require.Equal(t, []byte{9, 8, 7}, iterate(db, basedb.IterateOptions{Reverse: true, Limit: 3}))
+		require.Equal(t, []byte{6, 5, 4, 3}, iterate(db, basedb.IterateOptions{Start: bigEndian(3), End: bigEndian(7), Reverse: true}))
+		require.Equal(t, []byte{1, 0}, iterate(db, basedb.IterateOptions{End: bigEndian(2), Reverse: true}))
+		require.Empty(t, iterate(db, basedb.IterateOptions{Start: bigEndian(7), End: bigEndian(3)}))
+		require.Empty(t, iterate(db, basedb.IterateOptions{Start: bigEndian(20)}))
+
+		// Transactions iterate over their own writes.
+		txn := db.Begin()
+		defer txn.Discard()
+		require.NoError(t, txn.Delete(prefix, bigEndian(8)))
+		require.NoError(t, txn.Set(prefix, bigEndian(10), []byte{10}))
+		require.Equal(t, []byte{10, 9, 7}, iterate(txn, basedb.IterateOptions{Reverse: true, Limit: 3}))
+
+		// Iteration stops at the first error.
+		fail := fmt.Errorf("fail")
+		calls := 0
+		require.ErrorIs(t, db.Iterate(prefix, basedb.IterateOptions{}, func(obj basedb.Obj) error {
+			calls++
+			return fail
+		}), fail)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func bigEndian(n uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, n)
+}
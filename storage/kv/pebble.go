@@ -0,0 +1,411 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// PebbleDB struct.
+// Unlike BadgerDB, Pebble keeps the values within its LSM tree and reclaims disk space
+// by compacting it in the background, so it doesn't need periodic garbage collection.
+type PebbleDB struct {
+	logger *zap.Logger
+
+	db *pebble.DB
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPebble creates a persistent Pebble DB instance.
+func NewPebble(logger *zap.Logger, options basedb.Options) (*PebbleDB, error) {
+	return createPebbleDB(logger, options, false)
+}
+
+// NewPebbleInMemory creates an in-memory Pebble DB instance.
+func NewPebbleInMemory(logger *zap.Logger, options basedb.Options) (*PebbleDB, error) {
+	return createPebbleDB(logger, options, true)
+}
+
+func createPebbleDB(logger *zap.Logger, options basedb.Options, inMemory bool) (*PebbleDB, error) {
+	opt := &pebble.Options{}
+	path := options.Path
+	if inMemory {
+		opt.FS = vfs.NewMem()
+		path = ""
+	}
+
+	opt.Logger = newPebbleLogger(zap.NewNop())
+	if logger != nil && options.Reporting {
+		opt.Logger = newPebbleLogger(logger)
+	}
+
+	db, err := pebble.Open(path, opt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open pebble")
+	}
+
+	// Set up context/cancel to control background goroutines.
+	parentCtx := options.Ctx
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+
+	pebbleDB := PebbleDB{
+		logger: logger,
+		db:     db,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	// Start periodic reporting.
+	if options.Reporting && options.Ctx != nil {
+		pebbleDB.wg.Add(1)
+		go pebbleDB.periodicallyReport(1 * time.Minute)
+	}
+
+	return &pebbleDB, nil
+}
+
+// Pebble returns the underlying pebble.DB
+func (p *PebbleDB) Pebble() *pebble.DB {
+	return p.db
+}
+
+// Begin creates a read-write transaction.
+// Its reads see its own writes, along with the writes committed by other transactions.
+func (p *PebbleDB) Begin() basedb.Txn {
+	batch := p.db.NewIndexedBatch()
+	return &pebbleTxn{reader: batch, batch: batch, db: p}
+}
+
+// BeginRead creates a read-only transaction, which reads a consistent snapshot of the database.
+func (p *PebbleDB) BeginRead() basedb.ReadTxn {
+	return &pebbleTxn{reader: p.db.NewSnapshot(), db: p}
+}
+
+// Set save value with key to storage
+func (p *PebbleDB) Set(prefix []byte, key []byte, value []byte) error {
+	return p.db.Set(concatKey(prefix, key), value, pebble.Sync)
+}
+
+// SetMany save many values with the given keys in a single batch
+func (p *PebbleDB) SetMany(prefix []byte, n int, next func(int) (basedb.Obj, error)) error {
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	if err := setMany(batch, prefix, n, next); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// Get return value for specified key
+func (p *PebbleDB) Get(prefix []byte, key []byte) (basedb.Obj, bool, error) {
+	return p.get(p.db, prefix, key)
+}
+
+// GetMany return values for the given keys
+func (p *PebbleDB) GetMany(prefix []byte, keys [][]byte, iterator func(basedb.Obj) error) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	snapshot := p.db.NewSnapshot()
+	defer snapshot.Close()
+	return p.getMany(snapshot, prefix, keys, iterator)
+}
+
+// Delete key in specific prefix
+func (p *PebbleDB) Delete(prefix []byte, key []byte) error {
+	return p.db.Delete(concatKey(prefix, key), pebble.Sync)
+}
+
+// DeletePrefix all items with this prefix
+func (p *PebbleDB) DeletePrefix(prefix []byte) (int, error) {
+	batch := p.db.NewBatch()
+	defer batch.Close()
+
+	count := 0
+	iter := p.db.NewIter(prefixIterOptions(prefix))
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := batch.Delete(iter.Key(), nil); err != nil {
+			_ = iter.Close()
+			return 0, err
+		}
+		count++
+	}
+	if err := iter.Close(); err != nil {
+		return 0, err
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAll returns all the items of a given collection
+func (p *PebbleDB) GetAll(prefix []byte, handler func(int, basedb.Obj) error) error {
+	snapshot := p.db.NewSnapshot()
+	defer snapshot.Close()
+	return p.getAll(snapshot, prefix, handler)
+}
+
+// Iterate calls handler with the items of the given prefix in the order of their keys,
+// within the range and up to the limit of the given options
+func (p *PebbleDB) Iterate(prefix []byte, opts basedb.IterateOptions, handler func(basedb.Obj) error) error {
+	snapshot := p.db.NewSnapshot()
+	defer snapshot.Close()
+	return p.iterate(snapshot, prefix, opts, handler)
+}
+
+// CountPrefix return the object count for all keys under specified prefix(bucket)
+func (p *PebbleDB) CountPrefix(prefix []byte) (int64, error) {
+	var res int64
+	iter := p.db.NewIter(prefixIterOptions(prefix))
+	for iter.First(); iter.Valid(); iter.Next() {
+		res++
+	}
+	return res, iter.Close()
+}
+
+// DropPrefix cleans all items in a collection
+func (p *PebbleDB) DropPrefix(prefix []byte) error {
+	end := prefixUpperBound(prefix)
+	if end == nil {
+		// The prefix has no upper bound, so the items are deleted one by one.
+		_, err := p.DeletePrefix(prefix)
+		return err
+	}
+	return p.db.DeleteRange(prefix, end, pebble.Sync)
+}
+
+// DropAll cleans all items in the database
+func (p *PebbleDB) DropAll() error {
+	_, err := p.DeletePrefix(nil)
+	return err
+}
+
+// Close closes the database.
+func (p *PebbleDB) Close() error {
+	// Stop & wait for background goroutines.
+	p.cancel()
+	p.wg.Wait()
+
+	// Close the database.
+	err := p.db.Close()
+	if err != nil {
+		p.logger.Fatal("failed to close db", zap.Error(err))
+	}
+	return err
+}
+
+// report the db size and metrics
+func (p *PebbleDB) report() {
+	logger := p.logger.Named(logging.NamePebbleDBReporting)
+	metrics := p.db.Metrics()
+
+	logger.Debug("PebbleDBReport",
+		zap.Uint64("disk_space_usage", metrics.DiskSpaceUsage()),
+		zap.Int64("block_cache_size", metrics.BlockCache.Size),
+		zap.Int64("block_cache_hits", metrics.BlockCache.Hits),
+		zap.Int64("block_cache_misses", metrics.BlockCache.Misses),
+		zap.Int64("compactions", metrics.Compact.Count))
+}
+
+func (p *PebbleDB) periodicallyReport(interval time.Duration) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.report()
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// Update is a gateway to a read-write transaction,
+// which is committed if fn succeeds and discarded otherwise
+func (p *PebbleDB) Update(fn func(basedb.Txn) error) error {
+	txn := p.Begin()
+	defer txn.Discard()
+	if err := fn(txn); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+func (p *PebbleDB) get(r pebble.Reader, prefix []byte, key []byte) (basedb.Obj, bool, error) {
+	value, closer, err := r.Get(concatKey(prefix, key))
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) { // in order to couple the not found errors together
+			return basedb.Obj{}, false, nil
+		}
+		return basedb.Obj{}, true, err
+	}
+	defer closer.Close()
+	return basedb.Obj{
+		Key:   key,
+		Value: bytes.Clone(value),
+	}, true, nil
+}
+
+func (p *PebbleDB) getMany(r pebble.Reader, prefix []byte, keys [][]byte, iterator func(basedb.Obj) error) error {
+	for _, k := range keys {
+		obj, found, err := p.get(r, prefix, k)
+		if err != nil {
+			p.logger.Warn("failed to get item", zap.String("key", string(k)))
+			return err
+		}
+		if !found {
+			p.logger.Debug("item not found", zap.String("key", string(k)))
+			continue
+		}
+		if err := iterator(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PebbleDB) getAll(r pebble.Reader, prefix []byte, handler func(int, basedb.Obj) error) error {
+	iter := r.NewIter(prefixIterOptions(prefix))
+	i := 0
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := handler(i, basedb.Obj{
+			Key:   bytes.Clone(iter.Key()[len(prefix):]),
+			Value: bytes.Clone(iter.Value()),
+		}); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		i++
+	}
+	return iter.Close()
+}
+
+func (p *PebbleDB) iterate(r pebble.Reader, prefix []byte, opts basedb.IterateOptions, handler func(basedb.Obj) error) error {
+	iterOpts := &pebble.IterOptions{
+		LowerBound: concatKey(prefix, opts.Start),
+		UpperBound: prefixUpperBound(prefix),
+	}
+	if len(opts.End) > 0 {
+		iterOpts.UpperBound = concatKey(prefix, opts.End)
+	}
+	if iterOpts.UpperBound != nil && bytes.Compare(iterOpts.LowerBound, iterOpts.UpperBound) >= 0 {
+		return nil
+	}
+
+	iter := r.NewIter(iterOpts)
+	first, next := iter.First, iter.Next
+	if opts.Reverse {
+		first, next = iter.Last, iter.Prev
+	}
+	count := 0
+	for valid := first(); valid; valid = next() {
+		if err := handler(basedb.Obj{
+			Key:   bytes.Clone(iter.Key()[len(prefix):]),
+			Value: bytes.Clone(iter.Value()),
+		}); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		count++
+		if opts.Limit > 0 && count >= opts.Limit {
+			break
+		}
+	}
+	return iter.Close()
+}
+
+// Using returns the given ReadWriter, falling back to the database if it's nil.
+func (p *PebbleDB) Using(rw basedb.ReadWriter) basedb.ReadWriter {
+	if rw == nil {
+		return p
+	}
+	return rw
+}
+
+// UsingReader returns the given Reader, falling back to the database if it's nil.
+func (p *PebbleDB) UsingReader(r basedb.Reader) basedb.Reader {
+	if r == nil {
+		return p
+	}
+	return r
+}
+
+func setMany(w pebble.Writer, prefix []byte, n int, next func(int) (basedb.Obj, error)) error {
+	for i := 0; i < n; i++ {
+		item, err := next(i)
+		if err != nil {
+			return err
+		}
+		if err := w.Set(concatKey(prefix, item.Key), item.Value, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// concatKey returns the key of the given prefix and key in a new slice,
+// so that appending to the prefix doesn't overwrite the prefixes of other keys.
+func concatKey(prefix []byte, key []byte) []byte {
+	k := make([]byte, 0, len(prefix)+len(key))
+	k = append(k, prefix...)
+	return append(k, key...)
+}
+
+// prefixIterOptions returns the options of an iterator over the keys with the given prefix.
+func prefixIterOptions(prefix []byte) *pebble.IterOptions {
+	return &pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	}
+}
+
+// prefixUpperBound returns the smallest key which is greater than all the keys with the given prefix,
+// or nil if there's no such key.
+func prefixUpperBound(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// pebbleLogger is a wrapper for pebble.Logger
+type pebbleLogger struct {
+	logger *zap.Logger
+}
+
+// newPebbleLogger creates a new instance of logger
+func newPebbleLogger(l *zap.Logger) pebble.Logger {
+	return &pebbleLogger{l.Named(logging.NamePebbleDBLog)}
+}
+
+// Infof implements pebble.Logger
+func (pl *pebbleLogger) Infof(s string, i ...interface{}) {
+	pl.logger.Info(fmt.Sprintf(s, i...))
+}
+
+// Fatalf implements pebble.Logger
+func (pl *pebbleLogger) Fatalf(s string, i ...interface{}) {
+	pl.logger.Fatal(fmt.Sprintf(s, i...))
+}
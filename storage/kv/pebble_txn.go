@@ -0,0 +1,75 @@
+package kv
+
+import (
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+var errReadOnlyTxn = errors.New("read-only transaction")
+
+// pebbleTxn is a transaction over an indexed batch, or a snapshot if it's read-only.
+type pebbleTxn struct {
+	reader    pebble.Reader
+	batch     *pebble.Batch
+	db        *PebbleDB
+	discarded bool
+}
+
+func (t *pebbleTxn) Commit() error {
+	if t.batch == nil {
+		return errReadOnlyTxn
+	}
+	return t.batch.Commit(pebble.Sync)
+}
+
+func (t *pebbleTxn) Discard() {
+	if t.discarded {
+		return
+	}
+	t.discarded = true
+	// Closing the batch after it's committed only releases it.
+	_ = t.reader.Close()
+}
+
+func (t *pebbleTxn) Set(prefix []byte, key []byte, value []byte) error {
+	if t.batch == nil {
+		return errReadOnlyTxn
+	}
+	return t.batch.Set(concatKey(prefix, key), value, nil)
+}
+
+func (t *pebbleTxn) SetMany(prefix []byte, n int, next func(int) (basedb.Obj, error)) error {
+	if t.batch == nil {
+		return errReadOnlyTxn
+	}
+	return setMany(t.batch, prefix, n, next)
+}
+
+func (t *pebbleTxn) Get(prefix []byte, key []byte) (basedb.Obj, bool, error) {
+	return t.db.get(t.reader, prefix, key)
+}
+
+func (t *pebbleTxn) GetMany(prefix []byte, keys [][]byte, iterator func(basedb.Obj) error) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return t.db.getMany(t.reader, prefix, keys, iterator)
+}
+
+func (t *pebbleTxn) GetAll(prefix []byte, handler func(int, basedb.Obj) error) error {
+	return t.db.getAll(t.reader, prefix, handler)
+}
+
+func (t *pebbleTxn) Iterate(prefix []byte, opts basedb.IterateOptions, handler func(basedb.Obj) error) error {
+	return t.db.iterate(t.reader, prefix, opts, handler)
+}
+
+func (t *pebbleTxn) Delete(prefix []byte, key []byte) error {
+	if t.batch == nil {
+		return errReadOnlyTxn
+	}
+	return t.batch.Delete(concatKey(prefix, key), nil)
+}
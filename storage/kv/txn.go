@@ -78,6 +78,10 @@ func (t badgerTxn) GetAll(prefix []byte, handler func(int, basedb.Obj) error) er
 	return t.db.allGetter(prefix, handler)(t.txn)
 }
 
+func (t badgerTxn) Iterate(prefix []byte, opts basedb.IterateOptions, handler func(basedb.Obj) error) error {
+	return t.db.iterator(prefix, opts, handler)(t.txn)
+}
+
 func (t badgerTxn) Delete(prefix []byte, key []byte) error {
 	return t.txn.Delete(append(prefix, key...))
 }
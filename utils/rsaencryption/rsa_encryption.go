@@ -61,6 +61,15 @@ func DecodeKey(sk *rsa.PrivateKey, hash []byte) ([]byte, error) {
 	return decryptedKey, nil
 }
 
+// EncodeKey encrypts the given key with public key (rsa), as decrypted by DecodeKey
+func EncodeKey(pk *rsa.PublicKey, key []byte) ([]byte, error) {
+	encryptedKey, err := rsa.EncryptPKCS1v15(rand.Reader, pk, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt key")
+	}
+	return encryptedKey, nil
+}
+
 // ConvertPemToPrivateKey return rsa private key from secret key
 func ConvertPemToPrivateKey(skPem string) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode([]byte(skPem))
@@ -150,7 +159,12 @@ func PrivateKeyToByte(sk *rsa.PrivateKey) []byte {
 
 // ExtractPublicKey get public key from private key and return base64 encoded public key
 func ExtractPublicKey(sk *rsa.PrivateKey) (string, error) {
-	pkBytes, err := x509.MarshalPKIXPublicKey(&sk.PublicKey)
+	return EncodePublicKey(&sk.PublicKey)
+}
+
+// EncodePublicKey returns base64 encoded public key
+func EncodePublicKey(pk *rsa.PublicKey) (string, error) {
+	pkBytes, err := x509.MarshalPKIXPublicKey(pk)
 	if err != nil {
 		return "", errors.Wrap(err, "Failed to marshal private key")
 	}
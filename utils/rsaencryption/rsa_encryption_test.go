@@ -34,6 +34,16 @@ func TestDecodeKey(t *testing.T) {
 	require.Equal(t, "626d6a13ae5b1458c310700941764f3841f279f9c8de5f4ba94abd01dc082517", string(key))
 }
 
+func TestEncodeKey(t *testing.T) {
+	sk, err := ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+	encrypted, err := EncodeKey(&sk.PublicKey, []byte("626d6a13ae5b1458c310700941764f3841f279f9c8de5f4ba94abd01dc082517"))
+	require.NoError(t, err)
+	key, err := DecodeKey(sk, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "626d6a13ae5b1458c310700941764f3841f279f9c8de5f4ba94abd01dc082517", string(key))
+}
+
 func TestExtractPublicKey(t *testing.T) {
 	_, skByte, err := GenerateKeys()
 	require.NoError(t, err)